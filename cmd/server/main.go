@@ -32,6 +32,7 @@ import (
 )
 
 var GAPIService *apicalls.Caller
+var TestProvider apicalls.TestProvider
 
 func main() {
 
@@ -76,10 +77,18 @@ func main() {
 		return
 	}
 	// initialize the API connections to external services
-	err = GoogleAPIService()
-	if err != nil {
-		fmt.Println(err)
-		return
+	// the tests are served from local files instead of Google Forms if a local directory is set, for offline development
+	localTestsDir := os.Getenv("LocalTestProviderDir")
+	if localTestsDir != "" {
+		fmt.Printf("Using the local test provider : %s\n", localTestsDir)
+		TestProvider = apicalls.NewLocalTestProvider(localTestsDir)
+	} else {
+		err = GoogleAPIService()
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		TestProvider = apicalls.NewGoogleTestProvider(GAPIService, config.RedisClient, config.QueriesPool)
 	}
 	// initialize the asynchronous functions 
 	err = AsyncsInit()
@@ -197,17 +206,17 @@ func routes(router *gin.Engine) {
 	publicRoute := womid.Group("/public")
 	publicHandler.RegisterRoute(publicRoute)
 
-	adminService := services.NewAdminService(queries, GAPIService, notifyService)
+	adminService := services.NewAdminService(queries, GAPIService, notifyService, redis)
 	adminHandler := handlers.NewAdminHandler(adminService)
 	adminRoute := wmid.Group("/admin")
 	adminHandler.RegisterRoute(adminRoute)
 
-	companyService := services.NewCompanyService(queries, GAPIService, redis, notifyService)
+	companyService := services.NewCompanyService(queries, TestProvider, redis, notifyService)
 	companyHandler := handlers.NewCompanyHandler(companyService)
 	companyRoute := wmid.Group("/company")
 	companyHandler.RegisterRoute(companyRoute)
 
-	studentService := services.NewStudentService(queries, redis, GAPIService, notifyService)
+	studentService := services.NewStudentService(queries, redis, TestProvider, notifyService)
 	studentHandler := handlers.NewStudentHandler(studentService)
 	studentRoute := wmid.Group("/student")
 	studentHandler.RegisterRoute(studentRoute)
@@ -252,8 +261,9 @@ func GoogleAPIService() (error) {
 
 	GAPIService = apicalls.NewCaller(driveService, formsService, firebaseApp, fireMsgClient)
 
-	fmt.Println("Getting New DrivePageToken to start with ...")
-	_, err  = GAPIService.DriveChanges()
+	// the page token itself is stored by the drive changes poller, this only checks the access to the Drive
+	fmt.Println("Checking the access to the Drive changes ...")
+	_, err  = GAPIService.StartPageToken()
 	if err != nil {
 		return err
 	}
@@ -263,7 +273,7 @@ func GoogleAPIService() (error) {
 
 func AsyncsInit() error {
 
-	aService := tasks.NewAsyncService(config.QueriesPool, GAPIService)
+	aService := tasks.NewAsyncService(config.QueriesPool, TestProvider)
 	
 	err := aService.StartAsyncs()
 	if err != nil {
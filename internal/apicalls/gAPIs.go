@@ -27,24 +27,58 @@ func NewCaller(driveService *drive.Service, formsService *forms.Service, firebas
 	}
 }
 
-var DrivePageToken string
+// StartPageToken returns the page token of the current state of the Drive, the changes after it are listed from it
+func (p *Caller) StartPageToken() (string, error) {
 
-func (p *Caller) DriveChanges() (*drive.ChangeList, error) {
+	startToken, err := p.DriveService.Changes.GetStartPageToken().Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to get the start page token : %v", err)
+	}
+
+	return startToken.StartPageToken, nil
+}
 
-	if DrivePageToken == "" {
-		startToken, err := p.DriveService.Changes.GetStartPageToken().Do()
+// DriveChanges lists every change in the Drive since the page token, across all the pages of the list.
+// Returns the page token to list the next changes from.
+func (p *Caller) DriveChanges(pageToken string) ([]*drive.Change, string, error) {
+
+	changes := []*drive.Change{}
+	for {
+		currentList, err := p.DriveService.Changes.List(pageToken).Do()
 		if err != nil {
-			return nil, fmt.Errorf("unable to get the start page token : %v", err)
+			return nil, "", fmt.Errorf("unable to get list of changes in GDrive : %v", err)
 		}
-		DrivePageToken = startToken.StartPageToken
-	}
-	currentList, err := p.DriveService.Changes.List(DrivePageToken).Do()
-	if err != nil {
-		return nil, fmt.Errorf("unable to get list of changes in GDrive : %v", err)
+		changes = append(changes, currentList.Changes...)
+		// the last page has the token for the changes after it
+		if currentList.NewStartPageToken != "" {
+			return changes, currentList.NewStartPageToken, nil
+		}
+		pageToken = currentList.NextPageToken
 	}
-	DrivePageToken = currentList.NewStartPageToken
+}
 
-	return currentList, nil 
+// SharedForms lists the ids of every form the account has access to, across all the pages of the list
+func (p *Caller) SharedForms() ([]string, error) {
+
+	formIDs := []string{}
+	pageToken := ""
+	for {
+		currentList, err := p.DriveService.Files.List().
+			Q("mimeType = 'application/vnd.google-apps.form' and trashed = false").
+			Fields("nextPageToken", "files(id)").
+			PageToken(pageToken).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get list of forms in GDrive : %v", err)
+		}
+		for _, file := range currentList.Files {
+			formIDs = append(formIDs, file.Id)
+		}
+		if currentList.NextPageToken == "" {
+			return formIDs, nil
+		}
+		pageToken = currentList.NextPageToken
+	}
 }
 
 func (p *Caller) GetFormMetadata(formID string) (*forms.Form, error) {
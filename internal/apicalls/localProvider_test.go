@@ -0,0 +1,142 @@
+package apicalls
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// a form in the Forms API format with a choice question, a text question graded manually and an ungraded one
+const localForm = `{
+	"formId": "form1",
+	"responderUri": "https://docs.google.com/forms/d/e/form1/viewform",
+	"items": [
+		{"itemId": "q1", "questionItem": {"question": {"choiceQuestion": {"type": "RADIO", "options": [{"value": "3"}, {"value": "4"}]},
+			"grading": {"pointValue": 2, "correctAnswers": {"answers": [{"value": "4"}]}}}}},
+		{"itemId": "break", "pageBreakItem": {}},
+		{"itemId": "q2", "questionItem": {"question": {"choiceQuestion": {"type": "CHECKBOX", "options": [{"value": "2"}, {"value": "3"}, {"value": "4"}]},
+			"grading": {"pointValue": 3, "correctAnswers": {"answers": [{"value": "2"}, {"value": "3"}]}}}}},
+		{"itemId": "q3", "questionItem": {"question": {"textQuestion": {"paragraph": true}, "grading": {"pointValue": 5}}}},
+		{"itemId": "q4", "questionItem": {"question": {"textQuestion": {}}}}
+	]
+}`
+
+func newLocalProvider(t *testing.T) *LocalTestProvider {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "form1.json"), []byte(localForm), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "form2.json"), []byte(`{"formId": "form2", "responderUri": "https://forms.gle/form2"}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewLocalTestProvider(dir)
+}
+
+func TestLocalResolveFormID(t *testing.T) {
+
+	provider := newLocalProvider(t)
+
+	tests := []struct {
+		name string
+		link string
+		want string
+		err error
+	}{
+		{"responder link", "https://docs.google.com/forms/d/e/form1/viewform", "form1", nil},
+		{"short link", "https://forms.gle/form2", "form2", nil},
+		{"unknown link", "https://forms.gle/unknown", "", ErrFormNotShared},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.ResolveFormID(context.Background(), tt.link)
+			if got != tt.want || !errors.Is(err, tt.err) {
+				t.Errorf("got %q %v, want %q %v", got, err, tt.want, tt.err)
+			}
+		})
+	}
+}
+
+func TestLocalGetQuestions(t *testing.T) {
+
+	provider := newLocalProvider(t)
+
+	tests := []struct {
+		name string
+		formID string
+		items int
+		fails bool
+	}{
+		{"form", "form1", 5, false},
+		{"the id cannot leave the directory", "../form1", 5, false},
+		{"unknown form", "form3", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, err := provider.GetQuestions(tt.formID)
+			if tt.fails {
+				if err == nil {
+					t.Fatal("got no error for a missing form")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(form.Items) != tt.items {
+				t.Errorf("got %d items, want %d", len(form.Items), tt.items)
+			}
+			// the questions are served without the answers
+			for _, item := range form.Items {
+				if item.QuestionItem != nil && item.QuestionItem.Question.Grading != nil {
+					t.Errorf("item %s has its grading", item.ItemId)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalGetAnswerKey(t *testing.T) {
+
+	provider := newLocalProvider(t)
+
+	answers, err := provider.GetAnswerKey("form1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type answer struct {
+		correct int
+		points int64
+		manual bool
+		multiple bool
+		section int32
+	}
+	want := map[string]answer{
+		"q1": {1, 2, false, false, 0},
+		"q2": {2, 3, false, true, 1},
+		"q3": {0, 5, true, false, 1},
+	}
+
+	if len(answers) != len(want) {
+		t.Fatalf("got %d answers, want %d : %+v", len(answers), len(want), answers)
+	}
+	for _, a := range answers {
+		w, ok := want[a.QuestionID]
+		if !ok {
+			t.Errorf("question %s is not graded", a.QuestionID)
+			continue
+		}
+		got := answer{len(a.CorrectAnswer), a.Points, a.Manual, a.Multiple, a.Section}
+		if got != w {
+			t.Errorf("question %s : got %+v, want %+v", a.QuestionID, got, w)
+		}
+	}
+}
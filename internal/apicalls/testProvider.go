@@ -0,0 +1,347 @@
+package apicalls
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"google.golang.org/api/forms/v1"
+)
+
+// TestProvider is the source of the tests hosted outside the platform (GForms).
+// The test flow (taking the test, evaluating it) only talks to this interface,
+// so the Google APIs can be swapped with the local provider for offline development.
+type TestProvider interface {
+	// GetQuestions returns the form of the test with the answer key removed, as it is served to the students
+	GetQuestions(formID string) (*forms.Form, error)
+	// GetAnswerKey returns the correct answers and points of every graded question in the form
+	GetAnswerKey(formID string) ([]dto.TestAnswer, error)
+	// ResolveFormID returns the form id for a responder link, ErrFormNotShared if the form cannot be accessed
+	ResolveFormID(ctx context.Context, responderLink string) (string, error)
+}
+
+// ErrFormNotShared is returned when the form for a responder link is not (yet) accessible to the provider
+var ErrFormNotShared = errors.New("form not shared with the provider")
+
+// FormSyncer is a TestProvider that finds the shared forms in the background,
+// SyncForms is called on a schedule and returns the number of forms that were added or removed
+type FormSyncer interface {
+	SyncForms(ctx context.Context) (int, error)
+}
+
+// StripAnswers removes the grading (correct answers, feedback) from every question in the form
+func StripAnswers(form *forms.Form) *forms.Form {
+	for _, b := range form.Items {
+		qItem := b.QuestionItem
+		if qItem != nil && qItem.Question != nil && qItem.Question.Grading != nil {
+			qItem.Question.Grading = nil
+		}
+	}
+	return form
+}
+
+// SectionIndex maps the id of every item in the form to the index of its section.
+// The sections are split by the page breaks, the items before the first page break are in section 0.
+// Every page break starts the next section, even if the previous one has no questions, so the index is the position of the section.
+// A page break that is the first item of the form does not start a new section, it only names the first one.
+func SectionIndex(form *forms.Form) map[string]int32 {
+	sections := map[string]int32{}
+	section := int32(0)
+	for i, b := range form.Items {
+		if b.PageBreakItem != nil && i > 0 {
+			section++
+		}
+		sections[b.ItemId] = section
+	}
+	return sections
+}
+
+// AnswerKey extracts the correct answers and points from every graded question in the form, the item id is used as the question id.
+// Text questions (short answer, paragraph) are graded manually if the response does not match exactly, they may have no correct answers.
+func AnswerKey(form *forms.Form) []dto.TestAnswer {
+	sections := SectionIndex(form)
+	answers := []dto.TestAnswer{}
+	for _, b := range form.Items {
+		qItem := b.QuestionItem
+		if qItem == nil || qItem.Question == nil || qItem.Question.Grading == nil {
+			continue
+		}
+		manual := qItem.Question.TextQuestion != nil
+		hasAnswers := qItem.Question.Grading.CorrectAnswers != nil && qItem.Question.Grading.CorrectAnswers.Answers != nil
+		if !hasAnswers && !manual {
+			continue
+		}
+
+		ans := []string{}
+		if hasAnswers {
+			for _, a := range qItem.Question.Grading.CorrectAnswers.Answers {
+				ans = append(ans, a.Value)
+			}
+		}
+		answers = append(answers, dto.TestAnswer{
+			QuestionID: b.ItemId,
+			CorrectAnswer: ans,
+			Points: qItem.Question.Grading.PointValue,
+			Manual: manual,
+			Multiple: qItem.Question.ChoiceQuestion != nil && qItem.Question.ChoiceQuestion.Type == "CHECKBOX",
+			Section: sections[b.ItemId],
+		})
+	}
+	return answers
+}
+
+// >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
+
+// GoogleTestProvider serves the tests from Google Forms, the forms are found through the Drive changes
+// of the collaborator account they are shared with. The changes are synced in the background, the shared forms and
+// the page token of the changes are stored in the db, the {responderUri : formId} pairs are cached in Redis for a while.
+type GoogleTestProvider struct {
+	Caller *Caller
+	RedisClient *redis.Client
+	Queries *sqlc.Queries
+}
+
+func NewGoogleTestProvider(caller *Caller, redisClient *redis.Client, queries *sqlc.Queries) *GoogleTestProvider {
+	return &GoogleTestProvider{
+		Caller: caller,
+		RedisClient: redisClient,
+		Queries: queries,
+	}
+}
+
+func (g *GoogleTestProvider) GetQuestions(formID string) (*forms.Form, error) {
+	form, err := g.Caller.GetCompleteForm(formID)
+	if err != nil {
+		return nil, err
+	}
+	return StripAnswers(form), nil
+}
+
+func (g *GoogleTestProvider) GetAnswerKey(formID string) ([]dto.TestAnswer, error) {
+	form, err := g.Caller.GetCompleteForm(formID)
+	if err != nil {
+		return nil, err
+	}
+	return AnswerKey(form), nil
+}
+
+func (g *GoogleTestProvider) ResolveFormID(ctx context.Context, responderLink string) (string, error) {
+
+	// check if we already have the form id in the Redis Cache
+	formID, err := g.RedisClient.Get(ctx, responderLink).Result()
+	if err == nil {
+		return formID, nil
+	} else if err != redis.Nil {
+		return "", fmt.Errorf("failed to get from redis : %v", err)
+	}
+
+	// if not, the forms found by the sync are in the db
+	formID, err = g.Queries.SharedFormByLink(ctx, responderLink)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			// the sync has not found the form
+			// the user has not provided you with the access, or it was given after the last sync
+			return "", ErrFormNotShared
+		}
+		return "", fmt.Errorf("failed to get shared form : %v", err)
+	}
+	err = g.RedisClient.Set(ctx, responderLink, formID, config.FormLinkTTL * time.Hour).Err()
+	if err != nil {
+		return "", fmt.Errorf("failed to insert into redis : %v", err)
+	}
+
+	return formID, nil
+}
+
+// SyncForms reads the Drive changes since the stored page token, the forms shared with the collaborator account are added
+// and the ones it lost access to are removed. The first sync adds every form shared before it and stores the current page token.
+// A form whose metadata cannot be read is skipped, so one form does not hold back the others.
+func (g *GoogleTestProvider) SyncForms(ctx context.Context) (int, error) {
+
+	pageToken, err := g.Queries.DrivePageToken(ctx)
+	if err != nil {
+		if err.Error() != errs.NoRowsMatch {
+			return 0, fmt.Errorf("failed to get drive page token : %v", err)
+		}
+		return g.backfillForms(ctx)
+	}
+
+	// call drive change api to get file changes from the last page token
+	changes, newToken, err := g.Caller.DriveChanges(pageToken)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get GDrive changes : %v", err)
+	}
+
+	synced := 0
+	// loop over every changed object
+	for _, change := range changes {
+		// the file was removed or the access to it was taken away
+		if change.Removed || (change.File != nil && change.File.Trashed) {
+			removed, err := g.Queries.DeleteSharedForm(ctx, change.FileId)
+			if err != nil {
+				return synced, fmt.Errorf("failed to delete shared form : %v", err)
+			}
+			for _, responderUri := range removed {
+				err = g.RedisClient.Del(ctx, responderUri).Err()
+				if err != nil {
+					return synced, fmt.Errorf("failed to delete from redis : %v", err)
+				}
+				synced++
+			}
+			continue
+		}
+		// check if it is not null and has a mimetype of apps.form
+		if change.File == nil || change.File.MimeType != "application/vnd.google-apps.form" {
+			continue
+		}
+		added, err := g.syncForm(ctx, change.FileId)
+		if err != nil {
+			return synced, err
+		}
+		if added {
+			synced++
+		}
+	}
+
+	// the token is only moved once every change is stored, a failed sync reads the same changes again
+	err = g.Queries.SetDrivePageToken(ctx, newToken)
+	if err != nil {
+		return synced, fmt.Errorf("failed to save drive page token : %v", err)
+	}
+
+	return synced, nil
+}
+
+// backfillForms adds every form shared with the collaborator account before the first sync, and stores the page token.
+// The token is taken before the forms are listed, a form shared in between is read again from the changes.
+func (g *GoogleTestProvider) backfillForms(ctx context.Context) (int, error) {
+
+	pageToken, err := g.Caller.StartPageToken()
+	if err != nil {
+		return 0, err
+	}
+	formIDs, err := g.Caller.SharedForms()
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, formID := range formIDs {
+		added, err := g.syncForm(ctx, formID)
+		if err != nil {
+			return synced, err
+		}
+		if added {
+			synced++
+		}
+	}
+
+	err = g.Queries.SetDrivePageToken(ctx, pageToken)
+	if err != nil {
+		return synced, fmt.Errorf("failed to save drive page token : %v", err)
+	}
+
+	return synced, nil
+}
+
+// syncForm stores the responder link of a shared form, returns false if the form's metadata cannot be read.
+// The error is only returned for a failure to store it, which the sync retries.
+func (g *GoogleTestProvider) syncForm(ctx context.Context, fileID string) (bool, error) {
+
+	// get the metadata from the forms api
+	formData, err := g.Caller.GetFormMetadata(fileID)
+	if err != nil {
+		// the form may have been deleted since, or is not readable by the account, it is skipped
+		fmt.Printf("Skipped shared form ID %s : failed to get GForm metadata : %v\n", fileID, err)
+		return false, nil
+	}
+	err = g.Queries.UpsertSharedForm(ctx, sqlc.UpsertSharedFormParams{
+		FormID: formData.FormId,
+		ResponderUri: formData.ResponderUri,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save shared form : %v", err)
+	}
+	// Set the key:value in the Redis Cache {responderUri : formId}
+	err = g.RedisClient.Set(ctx, formData.ResponderUri, formData.FormId, config.FormLinkTTL * time.Hour).Err()
+	if err != nil {
+		return false, fmt.Errorf("failed to insert into redis : %v", err)
+	}
+
+	return true, nil
+}
+
+// >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
+
+// LocalTestProvider serves the tests from a directory of json files, used for offline development and testing.
+// Every file is named {formId}.json and contains a complete form in the Forms API format (forms.Form), with grading.
+// A responder link resolves to the form whose responderUri matches it.
+type LocalTestProvider struct {
+	Dir string
+}
+
+func NewLocalTestProvider(dir string) *LocalTestProvider {
+	return &LocalTestProvider{
+		Dir: dir,
+	}
+}
+
+func (l *LocalTestProvider) readForm(path string) (*forms.Form, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read local form : %v", err)
+	}
+	form := new(forms.Form)
+	err = json.Unmarshal(data, form)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse local form %s : %v", path, err)
+	}
+	return form, nil
+}
+
+func (l *LocalTestProvider) GetQuestions(formID string) (*forms.Form, error) {
+	form, err := l.readForm(filepath.Join(l.Dir, filepath.Base(formID) + ".json"))
+	if err != nil {
+		return nil, err
+	}
+	return StripAnswers(form), nil
+}
+
+func (l *LocalTestProvider) GetAnswerKey(formID string) ([]dto.TestAnswer, error) {
+	form, err := l.readForm(filepath.Join(l.Dir, filepath.Base(formID) + ".json"))
+	if err != nil {
+		return nil, err
+	}
+	return AnswerKey(form), nil
+}
+
+func (l *LocalTestProvider) ResolveFormID(ctx context.Context, responderLink string) (string, error) {
+
+	files, err := filepath.Glob(filepath.Join(l.Dir, "*.json"))
+	if err != nil {
+		return "", fmt.Errorf("unable to list local forms : %v", err)
+	}
+
+	for _, file := range files {
+		form, err := l.readForm(file)
+		if err != nil {
+			return "", err
+		}
+		if form.ResponderUri == responderLink {
+			// the file name is the form id
+			return strings.TrimSuffix(filepath.Base(file), ".json"), nil
+		}
+	}
+
+	return "", ErrFormNotShared
+}
@@ -0,0 +1,59 @@
+package apicalls
+
+import (
+	"maps"
+	"testing"
+
+	"google.golang.org/api/forms/v1"
+)
+
+func TestSectionIndex(t *testing.T) {
+
+	question := func(id string) *forms.Item {
+		return &forms.Item{ItemId: id, QuestionItem: &forms.QuestionItem{}}
+	}
+	pageBreak := func(id string) *forms.Item {
+		return &forms.Item{ItemId: id, PageBreakItem: &forms.PageBreakItem{}}
+	}
+
+	tests := []struct {
+		name string
+		items []*forms.Item
+		want map[string]int32
+	}{
+		{
+			name: "no page breaks",
+			items: []*forms.Item{question("q1"), question("q2")},
+			want: map[string]int32{"q1": 0, "q2": 0},
+		},
+		{
+			name: "items before the first page break",
+			items: []*forms.Item{question("q1"), pageBreak("b1"), question("q2")},
+			want: map[string]int32{"q1": 0, "b1": 1, "q2": 1},
+		},
+		{
+			name: "a page break first only names the first section",
+			items: []*forms.Item{pageBreak("b1"), question("q1"), pageBreak("b2"), question("q2")},
+			want: map[string]int32{"b1": 0, "q1": 0, "b2": 1, "q2": 1},
+		},
+		{
+			name: "an empty section keeps its position",
+			items: []*forms.Item{pageBreak("b1"), question("q1"), pageBreak("b2"), pageBreak("b3"), question("q2")},
+			want: map[string]int32{"b1": 0, "q1": 0, "b2": 1, "b3": 2, "q2": 2},
+		},
+		{
+			name: "empty form",
+			items: []*forms.Item{},
+			want: map[string]int32{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SectionIndex(&forms.Form{Items: tt.items})
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
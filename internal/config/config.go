@@ -14,8 +14,63 @@ const (
 
 const (
 	TestResultPollerTimeout = 900 // seconds // 15 mins
+	ExpiredAttemptsPollerTimeout = 60 // seconds
+	SimilarityPollerTimeout = 300 // seconds // 5 mins
+	// the jobs past their application deadline are closed this often
+	JobDeadlinePollerTimeout = 60 // seconds
+	// the forms shared with the collaborator account are picked up from the Drive changes this often
+	DriveChangesPollerTimeout = 30 // seconds
+	// responses that arrive this late after an attempt's deadline are still accepted, covers network latency
+	TestResponseGracePeriod = 10 // seconds
+	// how long a user's progress through the sections of a test is kept in the cache, longer than any attempt
+	TestSectionProgressTTL = 24 // hours
+	// the last ip of a user in a test is kept in the cache to record ip changes, longer than any attempt
+	ProctorIPTTL = 24 // hours
+	// a batch of proctoring events from the client is capped, the details of an event are truncated
+	ProctorEventsPerRequest = 50
+	ProctorDetailMaxLength = 200 // characters
+	// an attempt is flagged in the result draft at this many events, or at any ip change
+	IntegrityFlagMinEvents = 5
+	// a pair of attempts is flagged for similar answers at this many identical wrong answers, if they are at least
+	// this fraction of the wrong answers of the one with fewer, or when their time on the common questions is this close on average,
+	// as a fraction of the longer time
+	SimilarityMinSharedWrong = 3
+	SimilaritySharedWrongRatio = 0.5
+	SimilarityMinTimedQuestions = 5
+	SimilarityTimeTolerance = 0.1
+	// the extra time of a student's override is capped, a reason must be given for every override
+	OverrideMaxExtraMinutes = 24 * 60
+	OverrideReasonMaxLength = 500 // characters
+	// the responses of a company's dry run of its test are kept in the cache this long after the last one
+	DryRunTTL = 24 // hours
+	// the reason of a re-evaluation of a test is stored with its version of the result
+	ReevaluationReasonMaxLength = 500 // characters
+	// the title, location, type, salary and position of a job are capped
+	JobFieldMaxLength = 200 // characters
+	// the stages of the hiring pipeline of a job are capped
+	JobStagesMax = 10
+	// a job can restrict the gender of its applicants only where the law allows it, e.g. for a diversity drive
+	JobGenderCriteriaAllowed = true
 )
 
+const (
+	// the media of a test is downloaded in parallel when its content is cached
+	MediaFetchParallel = 8
+	MediaFetchTimeout = 30 // seconds
+	MediaMaxSize = 10000000 // bytes
+	// the content uri of a form's media is short lived, the media it points to is looked up by it only this long
+	MediaURLTTL = 30 // mins
+	// a responder link is resolved to its form id from the cache for this long, then from the db
+	FormLinkTTL = 24 // hours
+	// the content of a test is cached by one request at a time, the others wait for it this long
+	TestCacheLockTimeout = 120 // seconds
+	// the preview of a test that has not started is cached apart from the students' cache this long, the form may still change
+	PreviewCacheTTL = 5 // mins
+)
+
+// the dates entered in the forms as datetime-local have no offset, they are read in this time zone
+const PlatformTimeZone = "Asia/Kolkata"
+
 const (
 	SignupConfirmLinkTokenExpiration = 15 // mins
 	ResetLinkTokenExpiration = 15 // mins
@@ -23,6 +78,53 @@ const (
 
 const (
 	TempFileStorage = "./temp"
+	// the media of the tests, stored by the hash of its content
+	MediaStorageDir = "./media"
+	// a local copy of the go-echarts assets, for the assets that are not vendored in internal/go-charts/assets
+	ChartAssetsDir = "./assets/echarts"
+)
+
+const (
+	// tests.upload_method, where the questions of a test come from
+	TestUploadGForm = "GForms"
+	TestUploadCSVJSON = "CSVJSON"
+	TestUploadManual = "Manual"
+
+	// testquestions.type, question types for tests built on the platform
+	QuestionSingleChoice = "SingleChoice"
+	QuestionMultipleChoice = "MultipleChoice"
+	QuestionShortText = "ShortText"
+	QuestionParagraph = "Paragraph" // has no correct answer, always graded manually
+
+	// the access of the test provider to a form, checked before a test is created from it
+	FormAccessGranted = "access granted"
+	FormNotShared = "not shared yet"
+
+	// bankquestions.difficulty, the draw rules of a test can pick questions by it
+	DifficultyEasy = "Easy"
+	DifficultyMedium = "Medium"
+	DifficultyHard = "Hard"
+
+	// proctorevents.event_type, sent by the client while a test is open
+	ProctorTabSwitch = "TabSwitch"
+	ProctorFocusLoss = "FocusLoss"
+	ProctorFullscreenExit = "FullscreenExit"
+	ProctorCopy = "Copy"
+	ProctorPaste = "Paste"
+	ProctorIPChange = "IPChange" // recorded by the server, not accepted from the client
+
+	// resultversions.reason, why a version of the result draft was generated
+	ResultReasonTestEnded = "Test ended"
+	ResultReasonCutoff = "Cutoff changed"
+	ResultReasonMarkingScheme = "Marking scheme changed"
+	ResultReasonGraded = "Manual grading completed"
+)
+
+const (
+	// bulk import of questions for the CSVJSON upload method
+	TestImportMaxFileSize = 1000000 // bytes
+	TestImportMaxQuestions = 500
+	TestImportValueSeparator = "|" // separates multiple options / answers in a CSV cell
 )
 
 var (
@@ -28,7 +28,8 @@ func InitDB() (error) {
 		return fmt.Errorf("error creating database pool: %s", err)
 	}
 
-	// inittialize queries pool
+	// inittialize queries pool, the pool itself is kept for the transactions
+	Pool = pool
 	QueriesPool = sqlc.New(pool)
 	
 	// Connect to redis client
@@ -10,7 +10,6 @@ import (
 )
 
 type NewJobData struct {
-	JobId int64
 	JobTitle string
 	JobLocation string
 	JobDescription string
@@ -18,9 +17,37 @@ type NewJobData struct {
 	JobSalary string
 	SkillsRequired string
 	JobPosition string
+	// eligibility, the lists are comma separated, left empty to allow any
+	MinCGPA float64
+	EligibleDepartments string
+	EligibleCourses string
+	EligibleYears string
+	EligibleGenders string
+	// a datetime-local value in the platform's time zone, left empty to keep the job open until it is closed,
+	// left out of an edit to keep the deadline of the job
+	ApplicationDeadline string
 	Extras map[string]interface{}
 }
 
+// UpdateJobData is an edit of a job, every field of the listing is replaced, the deadline only if it is sent
+type UpdateJobData struct {
+	JobId int64
+	NewJobData
+}
+
+// JobStages are the ordered stages of the hiring pipeline of a job, empty to remove the pipeline
+type JobStages struct {
+	JobID int64
+	Stages []string
+}
+
+// ApplicableJob is a job listed to a student, with the reasons the student cannot apply to it
+type ApplicableJob struct {
+	sqlc.GetApplicableJobsTypeFilterRow
+	Eligible bool
+	IneligibleReasons []string
+}
+
 type AllJobs struct {
     ID         int    		`json:"id"`
 	Title       string		`json:"title"`
@@ -123,14 +150,26 @@ type NewTestPost struct {
 	Duration int64
 	QuestionCount int64
 	EndDateTime time.Time `form:"EndDateTime" time_format:"2006-01-02T15:04"`
+	// the test opens at StartDateTime, at creation if it is not set
+	StartDateTime time.Time `form:"StartDateTime" time_format:"2006-01-02T15:04"`
+	// the minutes after the test opens within which an attempt must be started, no limit if nil
+	LateEntry *int64
+	// the minutes after the test closes for which the attempts in progress can still be continued
+	GracePeriod int64
 	BindedJobId int64
 	Type string
 	UploadMethod string
 	Threshold int64
+	NegativeMarking int64
+	PartialCredit bool
+	MinScore *int64
+	Shuffle bool
 
 	// TODO: this is kinda useless, remove it !
 	FormattedEndDate string
 	FormattedEndTime string 
+	FormattedStartDate string
+	FormattedStartTime string
 	JobTitle string
 	CompanyName string
 	Link string
@@ -141,11 +180,22 @@ type NewTestGForms struct {
 	Notes string
 }
 
+// Status is config.FormAccessGranted or config.FormNotShared
+type FormStatus struct {
+	ResponderLink string
+	Status string
+	Message string
+}
+
 type TestQuestion struct {
 	Item *forms.Item
 	PrevId string
 	NextId string
 	TTL time.Duration
+
+	// the section of the item, SectionTTL is 0 if the section has no timer of its own
+	SectionName string
+	SectionTTL time.Duration
 }
 
 type TestResponse struct {
@@ -153,12 +203,244 @@ type TestResponse struct {
 	Response []string
 	TimeTaken int64
 }
+
+// the score of a company's dry run of its own test, by the answer key and the marking scheme of the test
+// MinScore is applied to Score, Pending is the number of responses that would wait for manual grading
+type DryRunScore struct {
+	Score int64
+	MaxScore int64
+	Cutoff int64
+	Passed bool
+	Pending int
+	Questions []DryRunQuestion
+}
+
+// Status is one of Correct, Partial, Wrong, Unattempted, Pending (manual grading) or Unmarked (no answer key)
+type DryRunQuestion struct {
+	QuestionID string
+	Title string
+	Response []string
+	CorrectAnswer []string
+	Points int64
+	MaxPoints int64
+	Status string
+}
+
+// the events the client observed while a test is open, sent in batches
+// Type is one of config.ProctorTabSwitch, ProctorFocusLoss, ProctorFullscreenExit, ProctorCopy, ProctorPaste
+type ProctorEvents struct {
+	TestID int64
+	Events []ProctorEvent
+}
+
+type ProctorEvent struct {
+	Type string
+	Detail string
+}
+
+// the event timeline of an attempt for the company, Counts has the number of events by their type
+type ProctorTimeline struct {
+	StudentName string
+	RollNumber string
+	StartTime string
+	EndTime string
+	Counts map[string]int64
+	Events []sqlc.ProctorTimelineRow
+}
+// an override of the test window for a student, given by the company or an admin
+// ExtraMinutes extends both the duration and the close of the test for the student,
+// EndDateTime is a make-up window that replaces the end time of the test for the student (no late entry limit),
+// FreshAttempt discards the student's attempt so the test can be taken again
+type TestOverride struct {
+	TestID int64
+	StudentID int64
+	ExtraMinutes int32
+	EndDateTime *time.Time `form:"EndDateTime" time_format:"2006-01-02T15:04"`
+	FreshAttempt bool
+	Reason string
+}
+
+// a request to evaluate an ended test again, the result draft is generated as a new version with the reason
+// AnswerKey has the corrected answers of the questions of a test built on the platform, empty to evaluate with the current key
+type Reevaluation struct {
+	TestID int64
+	Reason string
+	AnswerKey []AnswerKeyFix
+}
+
+type AnswerKeyFix struct {
+	QuestionID int64
+	CorrectAnswer []string
+	Points int32
+}
+
+// the students whose pass / fail changed between two versions of a test's result
+// a student only in one of the versions has the scores of the other as null
+type ResultDiff struct {
+	From int32
+	To int32
+	Changes []sqlc.ResultVersionDiffRow
+}
+
+// the analysis of a question over all the submitted attempts of a test
+// Candidates are the ones the question was served to, a question drawn from the question bank is only served to some
+// Difficulty is the percentage of those candidates who answered it correctly, the ones who skipped it count as wrong
+// Discrimination is the difference of the correct answers in the top and bottom 27% of the candidates by score,
+// as a fraction of the size of the group, between -1 and 1
+// MedianTimeTaken is in the unit the responses are recorded in
+type ItemAnalysis struct {
+	QuestionID string
+	Title string
+	Candidates int64
+	Attempted int64
+	Correct int64
+	Difficulty float64
+	Discrimination float64
+	MedianTimeTaken float64
+	// only for the choice questions, in the order of the options
+	Options []OptionFrequency
+}
+
+type OptionFrequency struct {
+	Option string
+	Picks int64
+	Correct bool
+}
+
+// the placement policy set by the admins, a rule left at 0 does not apply
+type PlacementPolicy struct {
+	BlockAfterAccept bool
+	// in rupees a year, e.g. 1200000 for 12 LPA
+	DreamMinSalary float64
+	MaxActiveApplications int32
+	DebarAfterDeclines int32
+}
+
+// turns the automatic shortlisting by the cutoff of a test on or off
+type AutoShortlist struct {
+	TestID int64
+	Enabled bool
+}
+
+// the applications that would be moved by the cutoff of a test if the results were published now
+type ShortlistPreview struct {
+	Enabled bool
+	Shortlisted int
+	Rejected int
+	Candidates []ShortlistCandidate
+}
+
+type ShortlistCandidate struct {
+	sqlc.ShortlistPreviewRow
+	NewStatus string
+}
+
 // TODO: replace this later with the 'NewTestPost' struct
 type UpdateTest struct {
 	TestID int64
 	Threshold int64
 }
 
+// the marking scheme of a test
+// NegativeMarking is the percentage of a question's points deducted for a wrong answer
+// PartialCredit gives proportional points on multi-select questions, (correct - wrong) options selected / correct options
+// MinScore is the lowest score a student can get, no floor if it is not sent
+type UpdateMarkingScheme struct {
+	TestID int64
+	NegativeMarking int64
+	PartialCredit bool
+	MinScore *int64
+}
+
+// the correct answer and points for a question of a test, used to evaluate the responses
+// Manual marks text questions, responses that do not match the correct answer exactly are graded by the company
+// Multiple marks multi-select questions, they can get partial credit if the test allows it
+// Section is the index of the question's section in the test
+type TestAnswer struct {
+	QuestionID string
+	CorrectAnswer []string
+	Points int64
+	Manual bool
+	Multiple bool
+	Section int32
+}
+
+// a response waiting for manual grading, with the title of its question
+type UngradedResponse struct {
+	sqlc.GradingQueueRow
+	QuestionTitle string
+}
+
+type GradeResponse struct {
+	TestID int64
+	ResponseID int64
+	Points int32
+	Comment string
+}
+
+// a single question of a test built on the platform (Manual, CSVJSON)
+type TestQuestionData struct {
+	QuestionID int64
+	TestID int64
+	Position int32
+	Type string
+	Title string
+	Description string
+	Options []string
+	CorrectAnswer []string
+	Points int32
+	// 0 if the question is not in a section, it is then placed in the first section
+	SectionID int64
+}
+
+// the order of the items and options a candidate was served, reproduced from the test's shuffle seed
+// Order only has the questions drawn for the candidate if the test draws from the question bank
+// Options has the shuffled options of every choice question by its item id
+type ShuffleAudit struct {
+	Shuffled bool
+	UserID int64
+	Order []string
+	Options map[string][]string
+}
+
+// a question in the company's question bank, tagged by topic and difficulty
+// the points are set by the draw rule that picks the question for a test
+type BankQuestionData struct {
+	QuestionID int64
+	Type string
+	Title string
+	Description string
+	Options []string
+	CorrectAnswer []string
+	Tags []string
+	Difficulty string
+}
+
+// a rule of a test that draws Count questions from the bank for every candidate
+// the questions are picked by any of the Tags (any topic if empty) at the Difficulty (any if empty), each is worth Points
+type DrawRuleData struct {
+	RuleID int64
+	TestID int64
+	Tags []string
+	Difficulty string
+	Count int32
+	Points int32
+	Position int32
+	// 0 if the drawn questions are not in a section
+	SectionID int64
+}
+
+// a named section of a test, its questions are served together and it is closed once the student moves on
+// Duration is the section's own timer in minutes, MinScore is the section cutoff, both are optional
+type TestSectionData struct {
+	SectionID int64
+	TestID int64
+	Name string
+	Position int32
+	Duration *int64
+	MinScore *int64
+}
+
 type Token struct {
 	Issuer string
 	Subject string
@@ -230,6 +512,25 @@ type CumulativeChartsData struct {
 
 	PassCount int64
 	FailCount int64
+
+	// pass / fail by the section cutoffs, empty if the test has no sections
+	SectionNames []string
+	SectionPass []int64
+	SectionFail []int64
+
+	// the attempts flagged by their proctoring events, FlagEvents has a series for every event type in FlagTypes
+	FlaggedStudents []string
+	FlagTypes []string
+	FlagEvents [][]int64
+
+	// the pairs of attempts flagged for similar answers, their identical wrong answers
+	// and the average difference of their time per question in percent, -1 if too few questions were timed
+	SimilarPairs []string
+	SharedWrong []int64
+	TimeDifference []float64
+
+	// the analysis of every question in the order of the test
+	Items []ItemAnalysis
 }
 
 type IndividualChartsData struct {
@@ -238,6 +539,21 @@ type IndividualChartsData struct {
 
 	RadarNames []*opts.Indicator
 	RadarValues []float32
+
+	// the questions by how they were marked (correct, partial, wrong, unattempted)
+	ResponseNames []string
+	ResponseValues []int64
+
+	// how the score adds up (earned, deducted, floor adjustment, final score)
+	ScoreNames []string
+	ScoreValues []int64
+
+	// the overrides of the test window that were in effect for the attempt, empty if there were none
+	Overrides string
+
+	// the heading of the static (pdf) report and the details of the attempt under it, as rows of name and value
+	Title string
+	Details [][]string
 }
 
 
@@ -10,7 +10,7 @@ import (
 
 func IndividualResult(data *dto.IndividualChartsData) (*components.Page, error) {
 
-	qcountFunnel, err := qCountFunnel(data.FunnelDimensions, data.FunnelValues)
+	qcountFunnel, err := qCountFunnel(data.FunnelDimensions, data.FunnelValues, data.Overrides)
 	if err != nil {
 		return nil, err
 	}
@@ -21,17 +21,29 @@ func IndividualResult(data *dto.IndividualChartsData) (*components.Page, error)
 	}
 
 
+	responsePie, err := responseBreakdownPie(data.ResponseNames, data.ResponseValues)
+	if err != nil {
+		return nil, err
+	}
+
+	scoreBar, err := scoreBreakdownBar(data.ScoreNames, data.ScoreValues)
+	if err != nil {
+		return nil, err
+	}
+
+
 	page := components.NewPage()
-	page.AddCharts(qcountFunnel, accuracyRadar)
+	page.AddCharts(qcountFunnel, accuracyRadar, responsePie, scoreBar)
 
 	return page, nil
 }
 
-func qCountFunnel(dimensions []string, values []int64) (*charts.Funnel, error) {
+// the overrides of the attempt, if any, are shown as the subtitle of the first chart
+func qCountFunnel(dimensions []string, values []int64, overrides string) (*charts.Funnel, error) {
 
 	qFunnel := charts.NewFunnel()
 	qFunnel.SetGlobalOptions(
-		charts.WithTitleOpts(opts.Title{Title: "Total VS Attempted VS Correct",}),
+		charts.WithTitleOpts(opts.Title{Title: "Total VS Attempted VS Correct", Subtitle: overrides,}),
 	)
 
 	funnelData := make([]opts.FunnelData, 0)
@@ -62,4 +74,53 @@ func accuracyRadar(indicators []*opts.Indicator, values []float32) (*charts.Rada
 	radar.AddSeries("Insights", radarData)
 
 	return radar, nil
-}
\ No newline at end of file
+}
+
+func responseBreakdownPie(names []string, values []int64) (*charts.Pie, error) {
+
+	pie := charts.NewPie()
+	pie.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "Response Breakdown"}),
+		charts.WithColorsOpts(opts.Colors{"green", "orange", "red", "grey"}),
+	)
+
+	pieData := make([]opts.PieData, 0)
+	for i, n := range names {
+		pieData = append(pieData, opts.PieData{Name: n, Value: values[i]})
+	}
+
+	pie.AddSeries("Responses", pieData).SetSeriesOptions(
+		charts.WithLabelOpts(opts.Label{
+			Show: opts.Bool(true),
+			Formatter: "{b} : {c}",
+		}),
+		charts.WithPieChartOpts(opts.PieChart{
+			Radius: []string{"45%", "75%"},
+		}),
+	)
+
+	return pie, nil
+}
+
+func scoreBreakdownBar(names []string, values []int64) (*charts.Bar, error) {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "Score Breakdown"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "Points"}),
+	)
+
+	barData := make([]opts.BarData, 0)
+	for _, v := range values {
+		barData = append(barData, opts.BarData{Value: v})
+	}
+
+	bar.SetXAxis(names).AddSeries("Points", barData).SetSeriesOptions(
+		charts.WithLabelOpts(opts.Label{
+			Show: opts.Bool(true),
+			Position: "top",
+		}),
+	)
+
+	return bar, nil
+}
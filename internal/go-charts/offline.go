@@ -0,0 +1,97 @@
+package gocharts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+	"go.mod/internal/config"
+)
+
+// the result pages are opened on machines without internet access, so their scripts and styles are embedded in the page
+// the pages are rendered with a placeholder host for the assets, which is then replaced with the content of the asset
+// the assets are vendored in ./assets and embedded in the binary (the same file names as on the go-echarts assets host, eg. echarts.min.js)
+// an asset that is not vendored is read from config.ChartAssetsDir, a page with an asset in neither place is not rendered
+
+//go:embed assets
+var embeddedAssets embed.FS
+
+const offlineHost = "offline-assets/"
+
+var assetTag = regexp.MustCompile(`<script src="` + offlineHost + `([^"]+)"></script>|<link href="` + offlineHost + `([^"]+)" rel="stylesheet">`)
+
+// the assets are read once, they do not change while the server runs
+var assetCache sync.Map
+
+// RenderOffline renders the page with all its assets embedded.
+// It fails if an asset is not available locally, the page would not render its charts offline.
+func RenderOffline(page *components.Page, w io.Writer) error {
+
+	page.SetAssetsHost(offlineHost)
+
+	var buf bytes.Buffer
+	err := page.Render(&buf)
+	if err != nil {
+		return err
+	}
+
+	// the first asset that could not be read, the page is not written
+	var assetErr error
+	content := assetTag.ReplaceAllFunc(buf.Bytes(), func(tag []byte) []byte {
+		if assetErr != nil {
+			return tag
+		}
+		match := assetTag.FindSubmatch(tag)
+		if len(match[1]) > 0 {
+			asset, err := readAsset(string(match[1]))
+			if err != nil {
+				assetErr = err
+				return tag
+			}
+			// the script must not close its own tag
+			asset = bytes.ReplaceAll(asset, []byte("</script"), []byte(`<\/script`))
+			return append(append([]byte("<script>"), asset...), "</script>"...)
+		}
+		asset, err := readAsset(string(match[2]))
+		if err != nil {
+			assetErr = err
+			return tag
+		}
+		return append(append([]byte("<style>"), asset...), "</style>"...)
+	})
+	if assetErr != nil {
+		return assetErr
+	}
+
+	_, err = w.Write(content)
+	return err
+}
+
+// readAsset returns the content of an asset, from the vendored copy or from config.ChartAssetsDir
+func readAsset(name string) ([]byte, error) {
+
+	if asset, ok := assetCache.Load(name); ok {
+		return asset.([]byte), nil
+	}
+
+	// the name comes from the page, it is kept inside the assets directory
+	name = strings.TrimPrefix(path.Clean("/" + name), "/")
+	asset, err := embeddedAssets.ReadFile("assets/" + name)
+	if err != nil {
+		asset, err = os.ReadFile(filepath.Join(config.ChartAssetsDir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, fmt.Errorf("chart asset %s is neither vendored in internal/go-charts/assets nor in %s : %v", name, config.ChartAssetsDir, err)
+		}
+	}
+	assetCache.Store(name, asset)
+
+	return asset, nil
+}
@@ -0,0 +1,37 @@
+package gocharts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+)
+
+func TestRenderOfflineMissingAsset(t *testing.T) {
+
+	// echarts.min.js may be vendored, the page also needs an asset that is not
+	page := components.NewPage()
+	page.JSAssets.Add("missing.min.js")
+
+	var buf bytes.Buffer
+	err := RenderOffline(page, &buf)
+	if err == nil || !strings.Contains(err.Error(), "is neither vendored") {
+		t.Fatalf("got error %v, want the missing asset to fail the render", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("got a page of %d bytes, want nothing written", buf.Len())
+	}
+}
+
+func TestReadAssetPath(t *testing.T) {
+
+	// the name is kept inside the assets directory
+	asset, err := readAsset("../README.md")
+	if err != nil {
+		t.Fatalf("unexpected error : %v", err)
+	}
+	if !bytes.Contains(asset, []byte("echarts.min.js")) {
+		t.Errorf("got %q, want the vendored README", asset)
+	}
+}
@@ -1,6 +1,8 @@
 package gocharts
 
 import (
+	"fmt"
+
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/components"
 	"github.com/go-echarts/go-echarts/v2/opts"
@@ -30,6 +32,33 @@ func ResultDraft(data *dto.CumulativeChartsData) (*components.Page, error) {
 
 	// add them to the page
 	page.AddCharts(marksbar, passfailPie)
+
+	if len(data.SectionNames) > 0 {
+		sectionsBar, err := sectionPassFailBar(data.SectionNames, data.SectionPass, data.SectionFail)
+		if err != nil {
+			return nil, err
+		}
+		page.AddCharts(sectionsBar)
+	}
+
+	if len(data.FlaggedStudents) > 0 {
+		flagsBar, err := integrityFlagsBar(data.FlaggedStudents, data.FlagTypes, data.FlagEvents)
+		if err != nil {
+			return nil, err
+		}
+		page.AddCharts(flagsBar)
+	}
+
+	if len(data.SimilarPairs) > 0 {
+		page.AddCharts(similarityBar(data.SimilarPairs, data.SharedWrong, data.TimeDifference))
+	}
+
+	if len(data.Items) > 0 {
+		page.AddCharts(itemQualityBar(data.Items), itemTimeBar(data.Items))
+		if optionsBar := itemOptionsBar(data.Items); optionsBar != nil {
+			page.AddCharts(optionsBar)
+		}
+	}
 	
 	return page, nil
 }
@@ -86,4 +115,215 @@ func passfailPie(pass int64, fail int64) (*charts.Pie, error) {
 
 
 	return pie, nil
-}
\ No newline at end of file
+}
+
+func sectionPassFailBar(sections []string, pass []int64, fail []int64) (*charts.Bar, error) {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "Section Wise Pass Fail Count"}),
+		charts.WithColorsOpts(opts.Colors{"green", "red"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Section"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "No. of Students"}),
+	)
+
+	passData := make([]opts.BarData, 0)
+	failData := make([]opts.BarData, 0)
+	for i := range sections {
+		passData = append(passData, opts.BarData{Value: pass[i]})
+		failData = append(failData, opts.BarData{Value: fail[i]})
+	}
+
+	bar.SetXAxis(sections).
+		AddSeries("Pass", passData).
+		AddSeries("Fail", failData).
+		SetSeriesOptions(charts.WithBarChartOpts(opts.BarChart{Stack: "passfail"}))
+
+	return bar, nil
+}
+
+func integrityFlagsBar(students []string, types []string, events [][]int64) (*charts.Bar, error) {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Integrity Flags",
+			Subtitle: "Attempts flagged by their proctoring events",
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width: "80%",
+			Height: "500px",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Student"}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "No. of Events"}),
+	)
+
+	bar.SetXAxis(students)
+	for i, t := range types {
+		series := make([]opts.BarData, 0)
+		for _, count := range events[i] {
+			series = append(series, opts.BarData{Value: count})
+		}
+		bar.AddSeries(t, series)
+	}
+	bar.SetSeriesOptions(charts.WithBarChartOpts(opts.BarChart{Stack: "events"}))
+
+	return bar, nil
+}
+
+// similarityBar shows the identical wrong answers and the time difference of every flagged pair of attempts
+func similarityBar(pairs []string, sharedWrong []int64, timeDifference []float64) *charts.Bar {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Similar Answers",
+			Subtitle: "Pairs of attempts with many identical wrong answers or nearly the same time per question, for review",
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width: "80%",
+			Height: "500px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Pair", AxisLabel: &opts.AxisLabel{Formatter: "{value}", Width: 120, Overflow: "truncate"}}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "Count / %"}),
+	)
+
+	wrong := make([]opts.BarData, 0, len(pairs))
+	times := make([]opts.BarData, 0, len(pairs))
+	for i := range pairs {
+		wrong = append(wrong, opts.BarData{Value: sharedWrong[i]})
+		// the pairs with too few timed questions have no time difference
+		if timeDifference[i] < 0 {
+			times = append(times, opts.BarData{Value: "-"})
+		} else {
+			times = append(times, opts.BarData{Value: timeDifference[i]})
+		}
+	}
+
+	bar.SetXAxis(pairs).
+		AddSeries("Identical Wrong Answers", wrong).
+		AddSeries("Time Difference (%)", times)
+
+	return bar
+}
+
+// itemLabels are the labels of the questions on the x axis, Q1, Q2 ... with the title in the tooltip
+func itemLabels(items []dto.ItemAnalysis) []string {
+	labels := make([]string, 0, len(items))
+	for i, item := range items {
+		labels = append(labels, fmt.Sprintf("Q%d %s", i+1, item.Title))
+	}
+	return labels
+}
+
+func itemQualityBar(items []dto.ItemAnalysis) *charts.Bar {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Item Analysis",
+			Subtitle: "Difficulty is the % of candidates who answered correctly, discrimination compares the top and bottom 27% (x100)",
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width: "80%",
+			Height: "500px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Question", AxisLabel: &opts.AxisLabel{Formatter: "{value}", Width: 80, Overflow: "truncate"}}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "%"}),
+	)
+
+	difficulty := make([]opts.BarData, 0, len(items))
+	discrimination := make([]opts.BarData, 0, len(items))
+	for _, item := range items {
+		difficulty = append(difficulty, opts.BarData{Value: item.Difficulty})
+		discrimination = append(discrimination, opts.BarData{Value: item.Discrimination * 100})
+	}
+
+	bar.SetXAxis(itemLabels(items)).
+		AddSeries("Difficulty", difficulty).
+		AddSeries("Discrimination", discrimination)
+
+	return bar
+}
+
+func itemTimeBar(items []dto.ItemAnalysis) *charts.Bar {
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{Title: "Median Time Taken"}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width: "80%",
+			Height: "500px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Question", AxisLabel: &opts.AxisLabel{Formatter: "{value}", Width: 80, Overflow: "truncate"}}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "Time"}),
+	)
+
+	times := make([]opts.BarData, 0, len(items))
+	for _, item := range items {
+		times = append(times, opts.BarData{Value: item.MedianTimeTaken})
+	}
+
+	bar.SetXAxis(itemLabels(items)).AddSeries("Median Time", times)
+
+	return bar
+}
+
+// itemOptionsBar stacks the picks of every option of the choice questions, the correct options are in green.
+// The options are stacked by their position, nil if the test has no choice questions.
+func itemOptionsBar(items []dto.ItemAnalysis) *charts.Bar {
+
+	labels := []string{}
+	choices := []dto.ItemAnalysis{}
+	positions := 0
+	for i, item := range items {
+		if len(item.Options) == 0 {
+			continue
+		}
+		labels = append(labels, fmt.Sprintf("Q%d %s", i+1, item.Title))
+		choices = append(choices, item)
+		positions = max(positions, len(item.Options))
+	}
+	if len(choices) == 0 {
+		return nil
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Option Frequency",
+			Subtitle: "Picks of every option, the correct options are in green",
+		}),
+		charts.WithInitializationOpts(opts.Initialization{
+			Width: "80%",
+			Height: "500px",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true)}),
+		charts.WithXAxisOpts(opts.XAxis{Name: "Question", AxisLabel: &opts.AxisLabel{Formatter: "{value}", Width: 80, Overflow: "truncate"}}),
+		charts.WithYAxisOpts(opts.YAxis{Name: "No. of Picks"}),
+	)
+
+	bar.SetXAxis(labels)
+	for p := 0; p < positions; p++ {
+		series := make([]opts.BarData, 0, len(choices))
+		for _, item := range choices {
+			if p >= len(item.Options) {
+				series = append(series, opts.BarData{Value: 0})
+				continue
+			}
+			o := item.Options[p]
+			data := opts.BarData{Name: o.Option, Value: o.Picks}
+			if o.Correct {
+				data.ItemStyle = &opts.ItemStyle{Color: "green"}
+			}
+			series = append(series, data)
+		}
+		bar.AddSeries(fmt.Sprintf("Option %d", p+1), series)
+	}
+	bar.SetSeriesOptions(charts.WithBarChartOpts(opts.BarChart{Stack: "options"}))
+
+	return bar
+}
@@ -0,0 +1,51 @@
+package gocharts
+
+import (
+	"fmt"
+	"math"
+
+	"go.mod/internal/dto"
+	"go.mod/internal/utils/pdfgen"
+)
+
+// the static reports have the same data as the result pages, drawn as plain tables and bar charts in a pdf
+// they do not need a browser or any script to be read
+
+// IndividualResultPDF creates the static report of a student's result
+func IndividualResultPDF(data *dto.IndividualChartsData) []byte {
+
+	doc := pdfgen.New()
+	doc.Heading(data.Title)
+	if len(data.Details) > 0 {
+		doc.Table([]string{"Attempt", ""}, data.Details)
+	}
+	if data.Overrides != "" {
+		doc.Paragraph(data.Overrides)
+	}
+
+	doc.Subheading("Summary")
+	summary := make([][]string, 0, len(data.FunnelDimensions) + len(data.RadarNames))
+	for i, d := range data.FunnelDimensions {
+		summary = append(summary, []string{d, fmt.Sprint(data.FunnelValues[i])})
+	}
+	for i, r := range data.RadarNames {
+		// the accuracy is not a number if nothing was attempted
+		if r.Name == "Accuracy" && !math.IsNaN(float64(data.RadarValues[i])) {
+			summary = append(summary, []string{"Accuracy", fmt.Sprintf("%.1f%%", data.RadarValues[i])})
+		}
+	}
+	doc.Table([]string{"Questions", "Count"}, summary)
+
+	doc.BarChart("Response Breakdown", data.ResponseNames, floats(data.ResponseValues), []pdfgen.Color{pdfgen.Green, pdfgen.Orange, pdfgen.Red, pdfgen.Grey})
+	doc.BarChart("Score Breakdown", data.ScoreNames, floats(data.ScoreValues), []pdfgen.Color{pdfgen.Blue})
+
+	return doc.Bytes()
+}
+
+func floats(values []int64) []float64 {
+	f := make([]float64, len(values))
+	for i, v := range values {
+		f[i] = float64(v)
+	}
+	return f
+}
@@ -8,8 +8,8 @@ import (
 	sqlc "go.mod/internal/sqlc/generate"
 )
 
-// SankeyApplications generates the sankey chart of the student's applications' distribution
-func SankeyApplicants(data *sqlc.ApplicantsCountRow) *charts.Sankey {
+// SankeyApplicants generates the sankey chart of the applicants of a job, through the stages of its pipeline if it has one
+func SankeyApplicants(data *sqlc.ApplicantsCountRow, stages []StageCount) *charts.Sankey {
 	zeroLinkval := float32(0.05)
 
 	hc := float32(data.HiredCount)
@@ -21,23 +21,32 @@ func SankeyApplicants(data *sqlc.ApplicantsCountRow) *charts.Sankey {
 	rcURC := urc - slc - float32(data.ReviewedCount)
 	rcSLC := slc - oc - float32(data.ShortlistedCount)
 
+	stageNodes, stageLinks, last, rcStages := pipeline("Shortlisted", "Rejected", 2, stages, zeroLinkval)
+	depth := 2 + len(stages)
+
 	var sankeyNode = []opts.SankeyNode{
 		{Name: "Total Applicants", Value: fmt.Sprintf("%f", ac), Depth: opts.Int(0)},
 		{Name: "Reviewed", Value: fmt.Sprintf("%f", urc), Depth: opts.Int(1)},
 		{Name: "Shortlisted", Value: fmt.Sprintf("%f", slc), Depth: opts.Int(2)},
-		{Name: "Rejected", Value: fmt.Sprintf("%f", rc), Depth: opts.Int(3)},
-		{Name: "Offered", Value: fmt.Sprintf("%f", oc), Depth: opts.Int(3)},
-		{Name: "Hired", Value: fmt.Sprintf("%f", hc), Depth: opts.Int(4)},
 	}
+	sankeyNode = append(sankeyNode, stageNodes...)
+	sankeyNode = append(sankeyNode,
+		opts.SankeyNode{Name: "Rejected", Value: fmt.Sprintf("%f", rc), Depth: opts.Int(depth + 1)},
+		opts.SankeyNode{Name: "Offered", Value: fmt.Sprintf("%f", oc), Depth: opts.Int(depth + 1)},
+		opts.SankeyNode{Name: "Hired", Value: fmt.Sprintf("%f", hc), Depth: opts.Int(depth + 2)},
+	)
 
 	var sankeyLink = []opts.SankeyLink{
 		{Source: "Total Applicants", Target: "Reviewed", Value: float32(max(urc, zeroLinkval))},
 		{Source: "Reviewed", Target: "Shortlisted", Value: float32(max(slc, zeroLinkval))},
 		{Source: "Reviewed", Target: "Rejected", Value: float32(max(rcURC, zeroLinkval))},
-		{Source: "Shortlisted", Target: "Offered", Value: float32(max(oc, zeroLinkval))},
-		{Source: "Shortlisted", Target: "Rejected", Value: float32(max(rcSLC, zeroLinkval))},
-		{Source: "Offered", Target: "Hired", Value: float32(max(hc, zeroLinkval))},
 	}
+	sankeyLink = append(sankeyLink, stageLinks...)
+	sankeyLink = append(sankeyLink,
+		opts.SankeyLink{Source: last, Target: "Offered", Value: float32(max(oc, zeroLinkval))},
+		opts.SankeyLink{Source: "Shortlisted", Target: "Rejected", Value: float32(max(rcSLC - rcStages, zeroLinkval))},
+		opts.SankeyLink{Source: "Offered", Target: "Hired", Value: float32(max(hc, zeroLinkval))},
+	)
 
 
 	sankey := charts.NewSankey()
@@ -8,8 +8,9 @@ import (
 	sqlc "go.mod/internal/sqlc/generate"
 )
 
-// SankeyApplications generates the sankey chart of the student's applications' distribution
-func SankeyApplications(data *sqlc.ApplicationsStatusCountsRow) *charts.Sankey {
+// SankeyApplications generates the sankey chart of the student's applications' distribution,
+// the stages of the jobs' pipelines are shown as rounds by their position, as every job has its own stages
+func SankeyApplications(data *sqlc.ApplicationsStatusCountsRow, rounds []StageCount) *charts.Sankey {
 
 	zeroLinkval := float32(0.05)
 
@@ -22,23 +23,32 @@ func SankeyApplications(data *sqlc.ApplicationsStatusCountsRow) *charts.Sankey {
 	rcURC := urc - slc - float32(data.UnderReviewCount)
 	rcSLC := slc - oc - float32(data.ShortlistedCount)
 
+	stageNodes, stageLinks, last, rcStages := pipeline("Shortlisted", "Rejected", 2, rounds, zeroLinkval)
+	depth := 2 + len(rounds)
+
 	var sankeyNode = []opts.SankeyNode{
 		{Name: "Applied", Value: fmt.Sprintf("%f", ac), Depth: opts.Int(0)},
 		{Name: "UnderReview", Value: fmt.Sprintf("%f", urc), Depth: opts.Int(1)},
 		{Name: "Shortlisted", Value: fmt.Sprintf("%f", slc), Depth: opts.Int(2)},
-		{Name: "Rejected", Value: fmt.Sprintf("%f", rc), Depth: opts.Int(3)},
-		{Name: "Offered", Value: fmt.Sprintf("%f", oc), Depth: opts.Int(3)},
-		{Name: "Hired", Value: fmt.Sprintf("%f", hc), Depth: opts.Int(4)},
 	}
+	sankeyNode = append(sankeyNode, stageNodes...)
+	sankeyNode = append(sankeyNode,
+		opts.SankeyNode{Name: "Rejected", Value: fmt.Sprintf("%f", rc), Depth: opts.Int(depth + 1)},
+		opts.SankeyNode{Name: "Offered", Value: fmt.Sprintf("%f", oc), Depth: opts.Int(depth + 1)},
+		opts.SankeyNode{Name: "Hired", Value: fmt.Sprintf("%f", hc), Depth: opts.Int(depth + 2)},
+	)
 
 	var sankeyLink = []opts.SankeyLink{
 		{Source: "Applied", Target: "UnderReview", Value: float32(max(urc, zeroLinkval))},
 		{Source: "UnderReview", Target: "Shortlisted", Value: float32(max(slc, zeroLinkval))},
 		{Source: "UnderReview", Target: "Rejected", Value: float32(max(rcURC, zeroLinkval))},
-		{Source: "Shortlisted", Target: "Offered", Value: float32(max(oc, zeroLinkval))},
-		{Source: "Shortlisted", Target: "Rejected", Value: float32(max(rcSLC, zeroLinkval))},
-		{Source: "Offered", Target: "Hired", Value: float32(max(hc, zeroLinkval))},
 	}
+	sankeyLink = append(sankeyLink, stageLinks...)
+	sankeyLink = append(sankeyLink,
+		opts.SankeyLink{Source: last, Target: "Offered", Value: float32(max(oc, zeroLinkval))},
+		opts.SankeyLink{Source: "Shortlisted", Target: "Rejected", Value: float32(max(rcSLC - rcStages, zeroLinkval))},
+		opts.SankeyLink{Source: "Offered", Target: "Hired", Value: float32(max(hc, zeroLinkval))},
+	)
 
 
 	sankey := charts.NewSankey()
@@ -0,0 +1,38 @@
+package gocharts
+
+import (
+	"fmt"
+
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// StageCount is a stage of a hiring pipeline, with the applications that reached it and the ones rejected at it
+type StageCount struct {
+	Name string
+	Reached int64
+	Rejected int64
+}
+
+// pipeline returns the nodes and links of the stages, in order after the from node at the given depth.
+// Every stage links to the next and its rejections to the rejected node,
+// returns the name of the last node of the pipeline, from if there are no stages, and the rejections in all stages.
+func pipeline(from string, rejected string, depth int, stages []StageCount, zeroLinkval float32) ([]opts.SankeyNode, []opts.SankeyLink, string, float32) {
+
+	nodes := []opts.SankeyNode{}
+	links := []opts.SankeyLink{}
+	rejectedCount := float32(0)
+
+	prev := from
+	for i, stage := range stages {
+		reached := float32(stage.Reached)
+		nodes = append(nodes, opts.SankeyNode{Name: stage.Name, Value: fmt.Sprintf("%f", reached), Depth: opts.Int(depth + i + 1)})
+		links = append(links,
+			opts.SankeyLink{Source: prev, Target: stage.Name, Value: float32(max(reached, zeroLinkval))},
+			opts.SankeyLink{Source: stage.Name, Target: rejected, Value: float32(max(float32(stage.Rejected), zeroLinkval))},
+		)
+		rejectedCount += float32(stage.Rejected)
+		prev = stage.Name
+	}
+
+	return nodes, links, prev, rejectedCount
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
 	"go.mod/internal/services"
 )
 
@@ -35,6 +36,18 @@ func (h *AdminHandler) RegisterRoute(adminRoute *gin.RouterGroup) {
 
 	adminRoute.GET("/verifyst", h.VerifyStudent)
 
+	// give a student extra time, a make-up end time or a fresh attempt for a test
+	adminRoute.POST("/testoverride", h.TestOverride)
+	// get the overrides given for a test
+	adminRoute.GET("/testoverrides", h.TestOverrides)
+	// remove the override of a student for a test
+	adminRoute.GET("/removetestoverride", h.RemoveTestOverride)
+
+	// get the placement policy
+	adminRoute.GET("/placementpolicy", h.PlacementPolicy)
+	// set the placement policy, checked on applications, offers and acceptances
+	adminRoute.POST("/placementpolicy", h.SetPlacementPolicy)
+
 }
 
 
@@ -138,3 +151,139 @@ func (h *AdminHandler) VerifyStudent(ctx *gin.Context) {
 
 }
 
+// TestOverride sets the override of the test window for a student, uses dto.TestOverride
+func (h *AdminHandler) TestOverride(ctx *gin.Context) {
+
+	userid, exists := ctx.Get("ID")
+	if !exists {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "missing user ID",
+		})
+		return
+	}
+
+	data := new(dto.TestOverride)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Override data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	errf := h.AdminService.TestOverride(ctx, userid.(int64), data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Override saved successfully.",
+	})
+}
+
+// TestOverrides responds with the overrides given to the students of a test
+func (h *AdminHandler) TestOverrides(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	overrides, errf := h.AdminService.TestOverrides(ctx, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, overrides)
+}
+
+// RemoveTestOverride removes the override of a student, an attempt in progress goes back to the test's window
+func (h *AdminHandler) RemoveTestOverride(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	studentid := ctx.Query("studentid")
+	if testid == "" || studentid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or student ID in request url.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	errf := h.AdminService.RemoveTestOverride(ctx, testid, studentid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Override removed successfully.",
+	})
+}
+
+// PlacementPolicy responds with the current placement policy
+func (h *AdminHandler) PlacementPolicy(ctx *gin.Context) {
+
+	policy, errf := h.AdminService.PlacementPolicy(ctx)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, policy)
+}
+
+// SetPlacementPolicy replaces the placement policy, uses dto.PlacementPolicy
+func (h *AdminHandler) SetPlacementPolicy(ctx *gin.Context) {
+
+	data := new(dto.PlacementPolicy)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Placement policy is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	errf := h.AdminService.SetPlacementPolicy(ctx, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Placement policy saved successfully.",
+	})
+}
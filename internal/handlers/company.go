@@ -39,6 +39,14 @@ func (h *CompanyHandler) RegisterRoute(companyRoute *gin.RouterGroup) {
 	companyRoute.GET("/newjob", h.NewJob)
 	// post new job form
 	companyRoute.POST("/newjobpost", h.NewJobPost)
+	// update a job listing
+	companyRoute.POST("/updatejob", h.UpdateJob)
+	// get the edit history of a job listing
+	companyRoute.GET("/jobversions", h.JobVersions)
+	// set the stages of the hiring pipeline of a job
+	companyRoute.POST("/jobstages", h.SetJobStages)
+	// get the stages of the hiring pipeline of a job
+	companyRoute.GET("/jobstages", h.JobStages)
 
 	// get the template for all applicants
 	companyRoute.GET("/applicants", h.ApplicantsStatic)
@@ -64,6 +72,8 @@ func (h *CompanyHandler) RegisterRoute(companyRoute *gin.RouterGroup) {
 	companyRoute.POST("/offer", h.Offer)
 	// schedule interview for given application
 	companyRoute.POST("/scheduleinterview", h.ScheduleInterview)
+	// move given application to the next stage of its job's pipeline
+	companyRoute.POST("/advancestage", h.AdvanceStage)
 	// cancel interview for given application
 	companyRoute.POST("/cancelinterview", h.CancelInterview)
 
@@ -71,6 +81,66 @@ func (h *CompanyHandler) RegisterRoute(companyRoute *gin.RouterGroup) {
 	companyRoute.GET("/newtest", h.NewTestStatic)
 	// post new test data
 	companyRoute.POST("/newtestpost", h.NewTestPost)
+	// get all questions of a test built on the platform
+	companyRoute.GET("/testquestions", h.TestQuestions)
+	// add a question to a test built on the platform
+	companyRoute.POST("/newquestion", h.NewTestQuestion)
+	// update a question of a test built on the platform
+	companyRoute.POST("/updatequestion", h.UpdateTestQuestion)
+	// delete a question of a test built on the platform
+	companyRoute.GET("/deletequestion", h.DeleteTestQuestion)
+	// get the order of questions and options a candidate was served
+	companyRoute.GET("/shuffleaudit", h.ShuffleAudit)
+	// get the proctoring event timeline of a result of a test
+	companyRoute.GET("/proctortimeline", h.ProctorTimeline)
+	// give a student extra time, a make-up end time or a fresh attempt for a test
+	companyRoute.POST("/testoverride", h.TestOverride)
+	// get the overrides given for a test
+	companyRoute.GET("/testoverrides", h.TestOverrides)
+	// remove the override of a student for a test
+	companyRoute.GET("/removetestoverride", h.RemoveTestOverride)
+	// evaluate an ended test again, with corrections to its answer key
+	companyRoute.POST("/reevaluate", h.Reevaluate)
+	// get the versions of the result draft of a test
+	companyRoute.GET("/resultversions", h.ResultVersions)
+	// get the students whose pass / fail changed between two versions of the result
+	companyRoute.GET("/resultdiff", h.ResultDiff)
+	// get a version of the result draft of a test
+	companyRoute.GET("/resultdraft", h.ResultDraft)
+	// check if the form of a responder link has been shared with the test provider
+	companyRoute.GET("/formstatus", h.FormStatus)
+	// preview a test as the students see it, the responses are saved in a dry run
+	companyRoute.POST("/testpreview", h.TestPreview)
+	// get the score of the dry run of a test by its answer key
+	companyRoute.GET("/dryrunscore", h.DryRunScore)
+	// get the item analysis of every question of an evaluated test
+	companyRoute.GET("/itemanalysis", h.ItemAnalysis)
+	// get all sections of a test
+	companyRoute.GET("/testsections", h.TestSections)
+	// add a section to a test
+	companyRoute.POST("/newsection", h.NewTestSection)
+	// update a section of a test
+	companyRoute.POST("/updatesection", h.UpdateTestSection)
+	// delete a section of a test, its questions move to the first section
+	companyRoute.GET("/deletesection", h.DeleteTestSection)
+	// get the questions in the company's question bank
+	companyRoute.GET("/bankquestions", h.BankQuestions)
+	// add a question to the question bank
+	companyRoute.POST("/newbankquestion", h.NewBankQuestion)
+	// update a question in the question bank
+	companyRoute.POST("/updatebankquestion", h.UpdateBankQuestion)
+	// delete a question from the question bank
+	companyRoute.GET("/deletebankquestion", h.DeleteBankQuestion)
+	// get the draw rules of a test
+	companyRoute.GET("/drawrules", h.DrawRules)
+	// add a rule that draws questions from the bank to a test
+	companyRoute.POST("/newdrawrule", h.NewDrawRule)
+	// update a draw rule of a test
+	companyRoute.POST("/updatedrawrule", h.UpdateDrawRule)
+	// delete a draw rule of a test
+	companyRoute.GET("/deletedrawrule", h.DeleteDrawRule)
+	// draw the pools of a test from the question bank again
+	companyRoute.GET("/refreshdrawpool", h.RefreshDrawPool)
 
 	// get the scheduled events template
 	companyRoute.GET("/scheduled", h.ScheduledStatic)
@@ -85,9 +155,22 @@ func (h *CompanyHandler) RegisterRoute(companyRoute *gin.RouterGroup) {
 	companyRoute.GET("/completeddata", h.CompletedData)
 	// post the new test cut off
 	companyRoute.POST("/editcutoff", h.EditCutOff)
+	// post the new marking scheme (negative marking, partial credit, min score)
+	companyRoute.POST("/editmarking", h.EditMarkingScheme)
+
+	// get the responses waiting for manual grading
+	companyRoute.GET("/gradingqueue", h.GradingQueue)
+	// grade a subjective response
+	companyRoute.POST("/graderesponse", h.GradeResponse)
 
 	// publish individual results
 	companyRoute.GET("/publishresults", h.PublishTestResults)
+	// turn the shortlisting of the applications by the cutoff of a test on or off
+	companyRoute.POST("/autoshortlist", h.AutoShortlist)
+	// get the applications that the cutoff of a test would shortlist or reject
+	companyRoute.GET("/shortlistpreview", h.ShortlistPreview)
+	// undo the shortlisting of the applications by the cutoff of a test
+	companyRoute.GET("/undoshortlist", h.UndoShortlist)
 
 	// get profile template
 	companyRoute.GET("/profile", h.GetProfile)
@@ -266,7 +349,7 @@ func (h *CompanyHandler) NewJobPost(ctx *gin.Context) {
 		return
 	}
 
-	errf = h.CompanyService.NewJobPost(ctx, jobdata, userID)
+	jobID, errf := h.CompanyService.NewJobPost(ctx, jobdata, userID)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -277,7 +360,133 @@ func (h *CompanyHandler) NewJobPost(ctx *gin.Context) {
 	}
 	
 	// TODO: the user can just go back and submit form again which is dangerous
-	ctx.Status(http.StatusOK)
+	ctx.JSON(http.StatusOK, gin.H{
+		"JobID": jobID,
+	})
+}
+// UpdateJob replaces the listing of a job, the applicants are notified of changes to the salary, location or eligibility
+func (h *CompanyHandler) UpdateJob(ctx *gin.Context) {
+
+	jobdata := new(dto.UpdateJobData)
+
+	err := ctx.Bind(jobdata)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Invalid or incomplete form.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	version, errf := h.CompanyService.UpdateJob(ctx, jobdata, userID)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"Version": version,
+	})
+}
+// SetJobStages replaces the stages of the hiring pipeline of a job, in the order they are given
+func (h *CompanyHandler) SetJobStages(ctx *gin.Context) {
+
+	data := new(dto.JobStages)
+
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Invalid or incomplete form.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	stages, errf := h.CompanyService.SetJobStages(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stages)
+}
+// JobStages responds with the stages of the hiring pipeline of a job, in order
+func (h *CompanyHandler) JobStages(ctx *gin.Context) {
+
+	jobid := ctx.Query("jobid")
+	if jobid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing job ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	stages, errf := h.CompanyService.JobStages(ctx, jobid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stages)
+}
+// JobVersions responds with the edit history of a job, the latest version first
+func (h *CompanyHandler) JobVersions(ctx *gin.Context) {
+
+	jobid := ctx.Query("jobid")
+	if jobid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing job ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	versions, errf := h.CompanyService.JobVersions(ctx, userID, jobid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versions)
 }
 // ApplicantsStatic returns the MyApplicants template for company role
 func (h *CompanyHandler) ApplicantsStatic(ctx *gin.Context) {
@@ -495,6 +704,40 @@ func (h *CompanyHandler) ShortList(ctx *gin.Context) {
 		"status": "Application shortlisted successfully",
 	})
 }
+// AdvanceStage moves a shortlisted application to the next stage of its job's pipeline
+func (h *CompanyHandler) AdvanceStage(ctx *gin.Context) {
+
+	applicationid := ctx.Query("applicationid")
+	stageid := ctx.Query("stageid")
+	if applicationid == "" || stageid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing application ID or stage ID parameter in request url.", 
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.AdvanceStage(ctx, userID, applicationid, stageid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Application moved to the stage successfully",
+	})
+}
 // Reject changes the application status to 'Rejected', also changes interview status to 'Completed'.
 func (h *CompanyHandler) Reject(ctx *gin.Context) {
 
@@ -688,7 +931,7 @@ func (h *CompanyHandler) NewTestPost(ctx *gin.Context) {
 		return
 	}
 
-	errf = h.CompanyService.NewTestPost(ctx, userID, newtestData)
+	testID, errf := h.CompanyService.NewTestPost(ctx, userID, newtestData)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -700,28 +943,49 @@ func (h *CompanyHandler) NewTestPost(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"status": "New test posted successfully.",
+		"testid": testID,
 	})
 }
-// ScheduledStatic responds with the 'Scheduled' page for company role
-func (h *CompanyHandler) ScheduledStatic(ctx *gin.Context) {
+// TestQuestions responds with all the questions of a test built on the platform, in order
+func (h *CompanyHandler) TestQuestions(ctx *gin.Context) {
 
-	filePath := config.CompPaths.CompanyScheduledTemplatePath
-	errf := h.checkFile(ctx, filePath)
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
 	if errf != nil {
-		ctx.Status(http.StatusInternalServerError)
+		ctx.JSON(http.StatusBadRequest, errf)
 		return
 	}
 
-	ctx.File(filePath)
+	questions, errf := h.CompanyService.TestQuestions(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, questions)
 }
-// ScheduledData returns 'Scheduled' events for company role, needs 'eventtype' as param
-func (h *CompanyHandler) ScheduledData(ctx *gin.Context) {
+// NewTestQuestion adds a question to a test built on the platform, uses dto.TestQuestionData
+func (h *CompanyHandler) NewTestQuestion(ctx *gin.Context) {
 
-	eventtype := ctx.Query("eventtype")
-	if eventtype == "" {
+	data := new(dto.TestQuestionData)
+	err := ctx.Bind(data)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errs.Error{
-			Type: errs.MissingRequiredField,
-			Message: "Missing event type in request url.",
+			Type: errs.IncompleteForm,
+			Message: "Question data is incomplete or invalid.",
 			ToRespondWith: true,
 		})
 		return
@@ -733,7 +997,7 @@ func (h *CompanyHandler) ScheduledData(ctx *gin.Context) {
 		return
 	}
 
-	uData, errf := h.CompanyService.ScheduledData(ctx, userID, eventtype)
+	questionID, errf := h.CompanyService.NewTestQuestion(ctx, userID, data)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -743,17 +1007,20 @@ func (h *CompanyHandler) ScheduledData(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, uData)
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Question added successfully.",
+		"questionid": questionID,
+	})
 }
-// UpdateInterview updates the interview details for given interview_ID, uses dto.UpdateInterview
-func (h *CompanyHandler) UpdateInterview(ctx *gin.Context) {
-	
-	data := new(dto.UpdateInterview)
+// UpdateTestQuestion replaces a question of a test built on the platform, uses dto.TestQuestionData
+func (h *CompanyHandler) UpdateTestQuestion(ctx *gin.Context) {
+
+	data := new(dto.TestQuestionData)
 	err := ctx.Bind(data)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errs.Error{
 			Type: errs.IncompleteForm,
-			Message: "Update Interview form data is incomplete or invalid.",
+			Message: "Question data is incomplete or invalid.",
 			ToRespondWith: true,
 		})
 		return
@@ -765,7 +1032,7 @@ func (h *CompanyHandler) UpdateInterview(ctx *gin.Context) {
 		return
 	}
 
-	errf = h.CompanyService.UpdateInterview(ctx, userID, data)
+	errf = h.CompanyService.UpdateTestQuestion(ctx, userID, data)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -776,32 +1043,55 @@ func (h *CompanyHandler) UpdateInterview(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"status": "Interview details updated successfully.",
+		"status": "Question updated successfully.",
 	})
 }
-// CompletedStatic returns the 'Completed' page for company role
-func (h *CompanyHandler) CompletedStatic(ctx *gin.Context) {
+// DeleteTestQuestion removes a question from a test built on the platform
+func (h *CompanyHandler) DeleteTestQuestion(ctx *gin.Context) {
 
-	filePath := config.CompPaths.CompanyCompletedTemplatePath
-	errf := h.checkFile(ctx, filePath)
+	testid := ctx.Query("testid")
+	questionid := ctx.Query("questionid")
+	if testid == "" || questionid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or question ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
 	if errf != nil {
-		ctx.Status(http.StatusInternalServerError)
+		ctx.JSON(http.StatusBadRequest, errf)
 		return
 	}
 
-	ctx.File(filePath)
+	errf = h.CompanyService.DeleteTestQuestion(ctx, userID, testid, questionid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Question deleted successfully.",
+	})
 }
-// CompletedData returns the 'Completed' events for company role
-func (h *CompanyHandler) CompletedData(ctx *gin.Context) {
-	
-	eventtype := ctx.Query("eventtype")
-	if eventtype == "" {
+// ShuffleAudit responds with the order of the questions and options a candidate was served, for a result of the test
+func (h *CompanyHandler) ShuffleAudit(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	resultid := ctx.Query("resultid")
+	if testid == "" || resultid == "" {
 		ctx.JSON(http.StatusBadRequest, errs.Error{
 			Type: errs.MissingRequiredField,
-			Message: "Missing event type in request url.",
+			Message: "Missing test ID or result ID in request url.",
 			ToRespondWith: true,
 		})
-		return
+		return 
 	}
 
 	userID, errf := h.extractUserID(ctx)
@@ -810,7 +1100,7 @@ func (h *CompanyHandler) CompletedData(ctx *gin.Context) {
 		return
 	}
 
-	uData, errf := h.CompanyService.CompletedData(ctx, userID, eventtype)
+	audit, errf := h.CompanyService.ShuffleAudit(ctx, userID, testid, resultid)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -820,21 +1110,20 @@ func (h *CompanyHandler) CompletedData(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, uData)
+	ctx.JSON(http.StatusOK, audit)
 }
-// EditCutOff edits test cutoff threshold, and then starts the 'Draft Result' process 
-// this can later be used to edit more details of the test
-func (h *CompanyHandler) EditCutOff(ctx *gin.Context) {
-	
-	newData := new(dto.UpdateTest)
-	err := ctx.Bind(newData)
-	if err != nil {
+// ProctorTimeline responds with the proctoring events of an attempt in the order they happened, for a result of the test
+func (h *CompanyHandler) ProctorTimeline(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	resultid := ctx.Query("resultid")
+	if testid == "" || resultid == "" {
 		ctx.JSON(http.StatusBadRequest, errs.Error{
-			Type: errs.IncompleteForm,
-			Message: "Edit cutoff form data is incomplete or invalid.",
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or result ID in request url.",
 			ToRespondWith: true,
 		})
-		return
+		return 
 	}
 
 	userID, errf := h.extractUserID(ctx)
@@ -843,7 +1132,7 @@ func (h *CompanyHandler) EditCutOff(ctx *gin.Context) {
 		return
 	}
 
-	errf = h.CompanyService.EditCutOff(ctx, userID, newData)
+	timeline, errf := h.CompanyService.ProctorTimeline(ctx, userID, testid, resultid)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -853,21 +1142,20 @@ func (h *CompanyHandler) EditCutOff(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"status": "Cutoff has been updated successfully.",
-	})
+	ctx.JSON(http.StatusOK, timeline)
 }
-// PublishTestResults starts the process of publishing test results for given test_id, returns immediately
-func (h *CompanyHandler) PublishTestResults(ctx *gin.Context) {
+// TestOverride sets the override of the test window for a student, uses dto.TestOverride
+func (h *CompanyHandler) TestOverride(ctx *gin.Context) {
 
-	testid := ctx.Query("testid")
-	if testid == "" {
+	data := new(dto.TestOverride)
+	err := ctx.Bind(data)
+	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errs.Error{
-			Type: errs.MissingRequiredField,
-			Message: "Missing test ID in request url.",
+			Type: errs.IncompleteForm,
+			Message: "Override data is incomplete or invalid.",
 			ToRespondWith: true,
 		})
-		return 
+		return
 	}
 
 	userID, errf := h.extractUserID(ctx)
@@ -876,7 +1164,7 @@ func (h *CompanyHandler) PublishTestResults(ctx *gin.Context) {
 		return
 	}
 
-	errf = h.CompanyService.PublishTestResults(ctx, userID, testid)
+	errf = h.CompanyService.TestOverride(ctx, userID, data)
 	if errf != nil {
 		if errf.ToRespondWith {
 			ctx.JSON(http.StatusBadRequest, errf)
@@ -887,7 +1175,1148 @@ func (h *CompanyHandler) PublishTestResults(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
-		"status": "Started publishing results.",
+		"status": "Override saved successfully.",
+	})
+}
+// TestOverrides responds with the overrides given to the students of a test
+func (h *CompanyHandler) TestOverrides(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	overrides, errf := h.CompanyService.TestOverrides(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, overrides)
+}
+// RemoveTestOverride removes the override of a student, an attempt in progress goes back to the test's window
+func (h *CompanyHandler) RemoveTestOverride(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	studentid := ctx.Query("studentid")
+	if testid == "" || studentid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or student ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.RemoveTestOverride(ctx, userID, testid, studentid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Override removed successfully.",
+	})
+}
+// Reevaluate marks the responses of an ended test again and generates a new version of its result draft, uses dto.Reevaluation
+func (h *CompanyHandler) Reevaluate(ctx *gin.Context) {
+
+	data := new(dto.Reevaluation)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Re-evaluation data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.Reevaluate(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "The test is being re-evaluated. The new result draft will be emailed.",
+	})
+}
+// ResultVersions responds with every version of the result draft of a test, the latest first
+func (h *CompanyHandler) ResultVersions(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	versions, errf := h.CompanyService.ResultVersions(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, versions)
+}
+// ResultDiff responds with the students whose pass / fail changed between two versions of the result,
+// the versions are the query params from and to, the last two versions if they are not set
+func (h *CompanyHandler) ResultDiff(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	diff, errf := h.CompanyService.ResultDiff(ctx, userID, testid, ctx.Query("from"), ctx.Query("to"))
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, diff)
+}
+// ResultDraft responds with a version of the result draft of a test, the latest if the version is not set
+func (h *CompanyHandler) ResultDraft(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	filePath, errf := h.CompanyService.ResultDraftPath(ctx, userID, testid, ctx.Query("version"))
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	errf = h.checkFile(ctx, filePath)
+	if errf != nil {
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store, no-cache, must-revalidate, proxy-revalidate, max-age=0")
+	ctx.File(filePath)
+}
+// FormStatus responds with the access of the test provider to the form of the responder link in the query param link
+func (h *CompanyHandler) FormStatus(ctx *gin.Context) {
+
+	link := ctx.Query("link")
+	if link == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing responder link in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	status, errf := h.CompanyService.FormStatus(ctx, link)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+// TestPreview responds with an item of a test as TakeTest would serve it to a student, the item is the query param itemid
+// the cover starts a new dry run, the response in the body is saved in the dry run and not in the test results
+func (h *CompanyHandler) TestPreview(ctx *gin.Context) {
+
+	data := new(dto.TestResponse)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Response data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	testid := ctx.Query("testid")
+	currentItemId := ctx.Query("itemid")
+	if testid == "" || currentItemId == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or item ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	item, errf := h.CompanyService.TestPreview(ctx, userID, testid, currentItemId, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+// DryRunScore responds with the score of the company's dry run of a test, question by question
+func (h *CompanyHandler) DryRunScore(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	score, errf := h.CompanyService.DryRunScore(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, score)
+}
+// ItemAnalysis responds with the difficulty, discrimination, median time and option picks of every question of a test
+func (h *CompanyHandler) ItemAnalysis(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	items, errf := h.CompanyService.ItemAnalysis(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+}
+// TestSections responds with all the sections of a test, in order
+func (h *CompanyHandler) TestSections(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	sections, errf := h.CompanyService.TestSections(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sections)
+}
+// NewTestSection adds a section to a test, uses dto.TestSectionData
+func (h *CompanyHandler) NewTestSection(ctx *gin.Context) {
+
+	data := new(dto.TestSectionData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Section data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	sectionID, errf := h.CompanyService.NewTestSection(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Section added successfully.",
+		"sectionid": sectionID,
+	})
+}
+// UpdateTestSection replaces a section of a test, uses dto.TestSectionData
+func (h *CompanyHandler) UpdateTestSection(ctx *gin.Context) {
+
+	data := new(dto.TestSectionData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Section data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.UpdateTestSection(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Section updated successfully.",
+	})
+}
+// DeleteTestSection removes a section from a test, its questions are kept
+func (h *CompanyHandler) DeleteTestSection(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	sectionid := ctx.Query("sectionid")
+	if testid == "" || sectionid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or section ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.DeleteTestSection(ctx, userID, testid, sectionid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Section deleted successfully.",
+	})
+}
+// BankQuestions responds with the questions in the company's question bank, optionally filtered by the 'tag' and 'difficulty' in the url
+func (h *CompanyHandler) BankQuestions(ctx *gin.Context) {
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	questions, errf := h.CompanyService.BankQuestions(ctx, userID, ctx.Query("tag"), ctx.Query("difficulty"))
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, questions)
+}
+// NewBankQuestion adds a question to the company's question bank, uses dto.BankQuestionData
+func (h *CompanyHandler) NewBankQuestion(ctx *gin.Context) {
+
+	data := new(dto.BankQuestionData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Question data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	questionID, errf := h.CompanyService.NewBankQuestion(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Question added to the bank successfully.",
+		"questionid": questionID,
+	})
+}
+// UpdateBankQuestion replaces a question in the company's question bank, uses dto.BankQuestionData
+func (h *CompanyHandler) UpdateBankQuestion(ctx *gin.Context) {
+
+	data := new(dto.BankQuestionData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Question data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.UpdateBankQuestion(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Bank question updated successfully.",
+	})
+}
+// DeleteBankQuestion removes a question from the company's question bank
+func (h *CompanyHandler) DeleteBankQuestion(ctx *gin.Context) {
+
+	questionid := ctx.Query("questionid")
+	if questionid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing question ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.DeleteBankQuestion(ctx, userID, questionid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Bank question deleted successfully.",
+	})
+}
+// DrawRules responds with the draw rules of a test and the size of their pools
+func (h *CompanyHandler) DrawRules(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	rules, errf := h.CompanyService.DrawRules(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+// NewDrawRule adds a rule to a test that draws questions from the bank, uses dto.DrawRuleData
+func (h *CompanyHandler) NewDrawRule(ctx *gin.Context) {
+
+	data := new(dto.DrawRuleData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Draw rule data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	ruleID, errf := h.CompanyService.NewDrawRule(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Draw rule added successfully.",
+		"ruleid": ruleID,
+	})
+}
+// UpdateDrawRule replaces a draw rule of a test, uses dto.DrawRuleData
+func (h *CompanyHandler) UpdateDrawRule(ctx *gin.Context) {
+
+	data := new(dto.DrawRuleData)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Draw rule data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.UpdateDrawRule(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Draw rule updated successfully.",
+	})
+}
+// DeleteDrawRule removes a draw rule from a test along with its drawn questions
+func (h *CompanyHandler) DeleteDrawRule(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	ruleid := ctx.Query("ruleid")
+	if testid == "" || ruleid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID or rule ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.DeleteDrawRule(ctx, userID, testid, ruleid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Draw rule deleted successfully.",
+	})
+}
+// RefreshDrawPool draws the pools of a test from the question bank again, to pick up edits of the bank
+func (h *CompanyHandler) RefreshDrawPool(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.RefreshDrawPool(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Question pool refreshed successfully.",
+	})
+}
+// ScheduledStatic responds with the 'Scheduled' page for company role
+func (h *CompanyHandler) ScheduledStatic(ctx *gin.Context) {
+
+	filePath := config.CompPaths.CompanyScheduledTemplatePath
+	errf := h.checkFile(ctx, filePath)
+	if errf != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.File(filePath)
+}
+// ScheduledData returns 'Scheduled' events for company role, needs 'eventtype' as param
+func (h *CompanyHandler) ScheduledData(ctx *gin.Context) {
+
+	eventtype := ctx.Query("eventtype")
+	if eventtype == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing event type in request url.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	uData, errf := h.CompanyService.ScheduledData(ctx, userID, eventtype)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, uData)
+}
+// UpdateInterview updates the interview details for given interview_ID, uses dto.UpdateInterview
+func (h *CompanyHandler) UpdateInterview(ctx *gin.Context) {
+	
+	data := new(dto.UpdateInterview)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Update Interview form data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.UpdateInterview(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Interview details updated successfully.",
+	})
+}
+// CompletedStatic returns the 'Completed' page for company role
+func (h *CompanyHandler) CompletedStatic(ctx *gin.Context) {
+
+	filePath := config.CompPaths.CompanyCompletedTemplatePath
+	errf := h.checkFile(ctx, filePath)
+	if errf != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.File(filePath)
+}
+// CompletedData returns the 'Completed' events for company role
+func (h *CompanyHandler) CompletedData(ctx *gin.Context) {
+	
+	eventtype := ctx.Query("eventtype")
+	if eventtype == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing event type in request url.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	uData, errf := h.CompanyService.CompletedData(ctx, userID, eventtype)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, uData)
+}
+// EditCutOff edits test cutoff threshold, and then starts the 'Draft Result' process 
+// this can later be used to edit more details of the test
+func (h *CompanyHandler) EditCutOff(ctx *gin.Context) {
+	
+	newData := new(dto.UpdateTest)
+	err := ctx.Bind(newData)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Edit cutoff form data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.EditCutOff(ctx, userID, newData)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Cutoff has been updated successfully.",
+	})
+}
+// EditMarkingScheme edits the negative marking, partial credit and min score of a test, and then starts the 'Draft Result' process again
+func (h *CompanyHandler) EditMarkingScheme(ctx *gin.Context) {
+
+	newData := new(dto.UpdateMarkingScheme)
+	err := ctx.Bind(newData)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Edit marking scheme form data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.EditMarkingScheme(ctx, userID, newData)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Marking scheme has been updated successfully.",
+	})
+}
+// PublishTestResults starts the process of publishing test results for given test_id, returns immediately
+func (h *CompanyHandler) PublishTestResults(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.PublishTestResults(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Started publishing results.",
+	})
+}
+// AutoShortlist turns the shortlisting of the applications by the cutoff on or off for a test,
+// the applications are moved once the results of the test are published
+func (h *CompanyHandler) AutoShortlist(ctx *gin.Context) {
+
+	data := new(dto.AutoShortlist)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Auto shortlist form data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.AutoShortlist(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Automatic shortlisting has been updated successfully.",
+	})
+}
+// ShortlistPreview responds with the applications that the cutoff would shortlist or reject, by the latest version of the result
+func (h *CompanyHandler) ShortlistPreview(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	preview, errf := h.CompanyService.ShortlistPreview(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preview)
+}
+// UndoShortlist moves the applications shortlisted or rejected by the cutoff of a test back to their previous status
+func (h *CompanyHandler) UndoShortlist(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	undone, errf := h.CompanyService.UndoShortlist(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "The shortlisting has been undone.",
+		"undone": undone,
+	})
+}
+// GradingQueue responds with the responses of a test that are waiting for manual grading
+func (h *CompanyHandler) GradingQueue(ctx *gin.Context) {
+
+	testid := ctx.Query("testid")
+	if testid == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing test ID in request url.",
+			ToRespondWith: true,
+		})
+		return 
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	queue, errf := h.CompanyService.GradingQueue(ctx, userID, testid)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, queue)
+}
+// GradeResponse assigns points and a comment to a subjective response, uses dto.GradeResponse
+func (h *CompanyHandler) GradeResponse(ctx *gin.Context) {
+
+	data := new(dto.GradeResponse)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Grading data is incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := h.extractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.CompanyService.GradeResponse(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "Response graded successfully.",
 	})
 }
 // GetProfile returns the 'MyProfile' page for company role
@@ -33,12 +33,15 @@ func (h *StudentHandler) RegisterRoute(studentRoute *gin.RouterGroup) {
 
 	// get the template for jobs list
 	studentRoute.GET("/jobslist", h.JobsList)
-	// get list of applicable jobs as JSON
+	// get list of applicable jobs as JSON, marked by eligibility, ?eligible=true leaves out the ineligible
 	studentRoute.GET("/alljobs", h.ApplicableJobs)
 
 	// post and apply to a job
 	studentRoute.POST("/applytojob", h.ApplyToJob)
 	studentRoute.GET("/cancelapplication", h.CancelApplication)
+	// accept or decline the offer of a job, checked against the placement policy
+	studentRoute.POST("/acceptoffer", h.AcceptOffer)
+	studentRoute.POST("/declineoffer", h.DeclineOffer)
 
 	// get template
 	studentRoute.GET("/myappsstatic", h.MyAppsStatic)
@@ -56,6 +59,8 @@ func (h *StudentHandler) RegisterRoute(studentRoute *gin.RouterGroup) {
 	studentRoute.POST("/taketestdata", h.TakeTest)
 	// submit test responses
 	studentRoute.GET("/submittest", h.SubmitTest) // TODO:
+	// record the proctoring events of the client while a test is open
+	studentRoute.POST("/proctorevents", h.ProctorEvents)
 
 	// get the completed page template
 	studentRoute.GET("/completed", h.CompletedStatic)
@@ -196,8 +201,11 @@ func (h *StudentHandler) ApplicableJobs(ctx *gin.Context) {
 		return
 	}
 
+	// the jobs the user is not eligible for are marked, or left out with eligible=true
+	eligibleOnly := ctx.Query("eligible") == "true"
+
 	// call the service that sends all job listings that the user has not yet applied for
-	alljobs, err := h.StudentService.GetApplicableJobs(ctx, jobType)
+	alljobs, err := h.StudentService.GetApplicableJobs(ctx, jobType, eligibleOnly)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -228,11 +236,13 @@ func (h *StudentHandler) ApplyToJob(ctx *gin.Context) {
 		return
 	}
 	// call service to add application to the database
-	err := h.StudentService.NewApplication(ctx, userID.(int64), jobId)
-	if err != nil {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+	errf := h.StudentService.NewApplication(ctx, userID.(int64), jobId)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
 		return
 	}
 	// 200OK code 
@@ -240,6 +250,44 @@ func (h *StudentHandler) ApplyToJob(ctx *gin.Context) {
 		"status": "applied to job successfully",
 	})
 }
+// AcceptOffer accepts the offer of a job, the application moves to Hired
+func (h *StudentHandler) AcceptOffer(ctx *gin.Context) {
+	h.respondToOffer(ctx, true)
+}
+// DeclineOffer declines the offer of a job, declined offers count towards the debarment of the placement policy
+func (h *StudentHandler) DeclineOffer(ctx *gin.Context) {
+	h.respondToOffer(ctx, false)
+}
+func (h *StudentHandler) respondToOffer(ctx *gin.Context, accept bool) {
+
+	jobID := ctx.Query("jobid")
+	if jobID == "" {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Missing job ID in request url.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := ctxutils.ExtractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusBadRequest, errf)
+		return
+	}
+
+	errf = h.StudentService.RespondToOffer(ctx, userID, jobID, accept)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
 func (h *StudentHandler) CancelApplication(ctx *gin.Context) {
 	// get user id from context
 	userID, exists := ctx.Get("ID")
@@ -411,6 +459,42 @@ func (h *StudentHandler) SubmitTest(ctx *gin.Context) {
 	ctx.Status(http.StatusOK)
 }
 
+// ProctorEvents records the events observed by the client during an open attempt of a test, uses dto.ProctorEvents
+func (h *StudentHandler) ProctorEvents(ctx *gin.Context) {
+
+	data := new(dto.ProctorEvents)
+	err := ctx.Bind(data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "Proctoring events are incomplete or invalid.",
+			ToRespondWith: true,
+		})
+		return
+	}
+
+	userID, errf := ctxutils.ExtractUserID(ctx)
+	if errf != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, errf)
+		return
+	}
+
+	errf = h.StudentService.ProctorEvents(ctx, userID, data)
+	if errf != nil {
+		if errf.ToRespondWith {
+			ctx.JSON(http.StatusBadRequest, errf)
+		} else {
+			ctx.Set("error", errf.Message)
+			ctx.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"Status": "Events recorded successfully.",
+	})
+}
+
 func (h *StudentHandler) CompletedStatic(ctx *gin.Context) {
 	ctx.File("./template/student/completed.html")
 }
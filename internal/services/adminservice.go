@@ -7,7 +7,11 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
 	"go.mod/internal/apicalls"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
 	"go.mod/internal/notify"
 	sqlc "go.mod/internal/sqlc/generate"
 )
@@ -16,12 +20,14 @@ type AdminService struct {
 	queries *sqlc.Queries
 	GAPIService *apicalls.Caller
 	Notify *notify.Notify
+	RedisClient *redis.Client
 }
-func NewAdminService(queriespool *sqlc.Queries, gapiService *apicalls.Caller, notifyService *notify.Notify) *AdminService {
+func NewAdminService(queriespool *sqlc.Queries, gapiService *apicalls.Caller, notifyService *notify.Notify, redisClient *redis.Client) *AdminService {
 	return &AdminService{
 		queries: queriespool,
 		GAPIService: gapiService,
 		Notify: notifyService,
+		RedisClient: redisClient,
 	}
 }
 
@@ -93,4 +99,89 @@ func (a *AdminService) VerifyStudent(ctx *gin.Context, userid string) (error) {
 	return nil
 }
 
+// the admin can override the test window of a student for any test, the same as the company of the test
+func (a *AdminService) TestOverride(ctx *gin.Context, userID int64, data *dto.TestOverride) (*errs.Error) {
+	return setTestOverride(ctx, a.queries, a.RedisClient, userID, data)
+}
+
+func (a *AdminService) TestOverrides(ctx *gin.Context, testid string) (*[]sqlc.ListTestOverridesRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	overrides, err := a.queries.ListTestOverrides(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test overrides : " + err.Error(),
+		}
+	}
+
+	return &overrides, nil
+}
+
+func (a *AdminService) RemoveTestOverride(ctx *gin.Context, testid string, studentid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	studentID, err := strconv.ParseInt(studentid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid student id.",
+			ToRespondWith: true,
+		}
+	}
 
+	return removeTestOverride(ctx, a.queries, a.RedisClient, testID, studentID)
+}
+
+// PlacementPolicy returns the current placement policy, with no rules set if the admins have not set one
+func (a *AdminService) PlacementPolicy(ctx *gin.Context) (*sqlc.PlacementPolicyRow, *errs.Error) {
+
+	policy, errf := placementPolicy(ctx, a.queries)
+	if errf != nil {
+		return nil, errf
+	}
+
+	return &policy, nil
+}
+
+// SetPlacementPolicy replaces the placement policy, it applies to the applications, offers and acceptances from then on
+func (a *AdminService) SetPlacementPolicy(ctx *gin.Context, data *dto.PlacementPolicy) (*errs.Error) {
+
+	if data.DreamMinSalary < 0 || data.MaxActiveApplications < 0 || data.DebarAfterDeclines < 0 {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "The rules of the placement policy cannot be negative.",
+			ToRespondWith: true,
+		}
+	}
+
+	err := a.queries.SetPlacementPolicy(ctx, sqlc.SetPlacementPolicyParams{
+		BlockAfterAccept: data.BlockAfterAccept,
+		DreamMinSalary: pgtype.Float8{Float64: data.DreamMinSalary, Valid: data.DreamMinSalary > 0},
+		MaxActiveApplications: pgtype.Int4{Int32: data.MaxActiveApplications, Valid: data.MaxActiveApplications > 0},
+		DebarAfterDeclines: pgtype.Int4{Int32: data.DebarAfterDeclines, Valid: data.DebarAfterDeclines > 0},
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to set placement policy : " + err.Error(),
+		}
+	}
+
+	return nil
+}
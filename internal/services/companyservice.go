@@ -8,6 +8,7 @@ import (
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -25,21 +26,23 @@ import (
 	"go.mod/internal/notify"
 	sqlc "go.mod/internal/sqlc/generate"
 	"go.mod/internal/utils"
+	"go.mod/internal/utils/testforms"
+	"go.mod/internal/utils/ctxutils"
 	"go.mod/internal/utils/testresgen"
 )
 
 
 type CompanyService struct {
 	queries *sqlc.Queries
-	GAPIService *apicalls.Caller
+	Tests apicalls.TestProvider
 	RedisClient *redis.Client
 	Notify *notify.Notify
 }
 
-func NewCompanyService(queriespool *sqlc.Queries, gapiService *apicalls.Caller, redisClient *redis.Client, notifyService *notify.Notify) *CompanyService {
+func NewCompanyService(queriespool *sqlc.Queries, testProvider apicalls.TestProvider, redisClient *redis.Client, notifyService *notify.Notify) *CompanyService {
 	return &CompanyService{
 		queries: queriespool,
-		GAPIService: gapiService,
+		Tests: testProvider,
 		RedisClient: redisClient,
 		Notify: notifyService,
 	}
@@ -58,93 +61,322 @@ func (c *CompanyService) DashboardData(ctx *gin.Context, userID int64) (*sqlc.Co
 	return &data, nil
 }
 
-func (c *CompanyService) NewJobPost(ctx *gin.Context, jobdata *dto.NewJobData, userID int64) (*errs.Error) {	
-	// split skills into []text
-	skills := strings.Split(jobdata.SkillsRequired, ",")
-	for i, skill := range skills {
-		// trim off spaces
-		skills[i] = strings.TrimSpace(skill)
-	}
+// NewJobPost validates and posts a new job listing, its first version is kept in the edit history of the job
+func (c *CompanyService) NewJobPost(ctx *gin.Context, jobdata *dto.NewJobData, userID int64) (int64, *errs.Error) {
 
-	// create map of extra params // flexiblity
-	extras := make(map[string]interface{})
-	for key, values := range ctx.Request.Form {
-		if _, exists := map[string]bool{
-			"JobId": true,
-			"JobLocation": true,
-			"JobTitle": true,
-			"JobDescription": true,
-			"JobType": true,
-			"JobSalary": true,
-			"SkillsRequired": true,
-			"JobPosition": true,
-		}[key]; !exists {
-			if len(values) > 0 {
-				extras[key] = values[0]
-			}
+	// an edit sent to the new job post would post a copy of the job
+	if _, exists := ctx.Request.Form["JobId"]; exists {
+		return 0, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "A new job cannot have a Job ID, edit the job to change it.",
+			ToRespondWith: true,
 		}
 	}
+	job, errf := validateJob(ctx, jobdata)
+	if errf != nil {
+		return 0, errf
+	}
+	errf = pastDeadline(job.deadline)
+	if errf != nil {
+		return 0, errf
+	}
 
-	extraJson, err := json.Marshal(extras)
+	jobID, err := c.queries.InsertNewJob(ctx, sqlc.InsertNewJobParams{
+		DataUrl: pgtype.Text{String: "", Valid: true},
+		UserID: userID,
+		Title: jobdata.JobTitle,
+		Location: jobdata.JobLocation,
+		Type: jobdata.JobType,
+		Salary: jobdata.JobSalary,
+		Skills: job.skills,
+		Position: jobdata.JobPosition,
+		Extras: job.extras,
+		Description: pgtype.Text{String: jobdata.JobDescription, Valid: true},
+		MinCgpa: job.criteria.MinCgpa,
+		EligibleDepartments: job.criteria.Departments,
+		EligibleCourses: job.criteria.Courses,
+		EligibleYears: job.criteria.Years,
+		EligibleGenders: job.criteria.Genders,
+		ApplicationDeadline: job.deadline,
+	})
 	if err != nil {
-		return &errs.Error{
+		return 0, &errs.Error{
 			Type: errs.Internal,
-			Message: "Failed to marshal extra params : " + err.Error(),
+			Message: "Failed to insert new job data : " + err.Error(),
+		}
+	}
+
+	return jobID, nil
+}
+
+// UpdateJob replaces the listing of a job of the user and keeps the new version in the edit history of the job.
+// The students who applied and are not rejected are notified if the salary, location or eligibility changed.
+func (c *CompanyService) UpdateJob(ctx *gin.Context, jobdata *dto.UpdateJobData, userID int64) (int32, *errs.Error) {
+
+	if jobdata.JobId <= 0 {
+		return 0, &errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Job ID is required.",
+			ToRespondWith: true,
 		}
 	}
-	// TODO: also used to update the job, can be a vulnerability
-	if (jobdata.JobId != 0) {
-		err = c.queries.UpdateJob(ctx, sqlc.UpdateJobParams{
-			Location: jobdata.JobLocation,
-			Title: jobdata.JobTitle,
-			Description: pgtype.Text{String: jobdata.JobDescription, Valid: true},
-			Type: jobdata.JobType,
-			Salary: jobdata.JobSalary,
-			Skills: skills,
-			Position: jobdata.JobPosition,
-			Extras: extraJson,
+	job, errf := validateJob(ctx, &jobdata.NewJobData)
+	if errf != nil {
+		return 0, errf
+	}
+	// a deadline that has passed can be sent back unchanged with the rest of the edit
+	if job.deadlineSet {
+		current, err := c.queries.JobDeadline(ctx, sqlc.JobDeadlineParams{
 			JobID: jobdata.JobId,
 			UserID: userID,
 		})
 		if err != nil {
 			if err.Error() == errs.NoRowsMatch {
-				return &errs.Error{
+				return 0, &errs.Error{
 					Type: errs.Unauthorized,
 					Message: "You are not allowed to alter this job, or it does not exist.",
 					ToRespondWith: true,
 				}
 			}
-			return &errs.Error{
+			return 0, &errs.Error{
 				Type: errs.Internal,
-				Message: "Failed to update job listing : " + err.Error(),
+				Message: "Failed to get job deadline : " + err.Error(),
+			}
+		}
+		if current.Valid != job.deadline.Valid || !current.Time.Equal(job.deadline.Time) {
+			errf = pastDeadline(job.deadline)
+			if errf != nil {
+				return 0, errf
 			}
 		}
-
-		return nil
 	}
 
-	// TODO: need to better validate incoming data 
-	// add job data to db
-	err = c.queries.InsertNewJob(ctx, sqlc.InsertNewJobParams{
-		DataUrl: pgtype.Text{String: "", Valid: true},
-		UserID: userID,
-		Title: jobdata.JobTitle,
+	updated, err := c.queries.UpdateJob(ctx, sqlc.UpdateJobParams{
 		Location: jobdata.JobLocation,
+		Title: jobdata.JobTitle,
+		Description: pgtype.Text{String: jobdata.JobDescription, Valid: true},
 		Type: jobdata.JobType,
 		Salary: jobdata.JobSalary,
-		Skills: skills,
+		Skills: job.skills,
 		Position: jobdata.JobPosition,
-		Extras: extraJson,
-		Description: pgtype.Text{String: jobdata.JobDescription, Valid: true},
+		Extras: job.extras,
+		JobID: jobdata.JobId,
+		UserID: userID,
+		MinCgpa: job.criteria.MinCgpa,
+		EligibleDepartments: job.criteria.Departments,
+		EligibleCourses: job.criteria.Courses,
+		EligibleYears: job.criteria.Years,
+		EligibleGenders: job.criteria.Genders,
+		ApplicationDeadline: job.deadline,
+		DeadlineSet: job.deadlineSet,
 	})
 	if err != nil {
-		return &errs.Error{
+		if err.Error() == errs.NoRowsMatch {
+			return 0, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not allowed to alter this job, or it does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return 0, &errs.Error{
 			Type: errs.Internal,
-			Message: "Failed to insert new job data : " + err.Error(),
+			Message: "Failed to update job listing : " + err.Error(),
 		}
 	}
 
-	return nil
+	changes := []string{}
+	if updated.SalaryChanged {
+		changes = append(changes, "salary")
+	}
+	if updated.LocationChanged {
+		changes = append(changes, "location")
+	}
+	if updated.EligibilityChanged {
+		changes = append(changes, "eligibility")
+	}
+	if len(changes) == 0 {
+		return updated.Version, nil
+	}
+
+	applicants, err := c.queries.JobApplicantUsers(ctx, jobdata.JobId)
+	if err != nil {
+		// the job has already been updated, its applicants are only not notified
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to get the applicants of job ID : %d to notify its update : %v", jobdata.JobId, err.Error()),
+		})
+		return updated.Version, nil
+	}
+	for _, applicant := range applicants {
+		errf := c.Notify.NewNotification(ctx, applicant, &dto.NotificationData{
+			Title: "Job Updated",
+			Description: fmt.Sprintf("The %s of %s (Job ID: %d), which you applied to, has changed.", strings.Join(changes, ", "), jobdata.JobTitle, jobdata.JobId),
+		})
+		if errf != nil {
+			// the job has already been updated, a failed notification does not stop the others
+			ctxutils.NewError(&dto.ErrorData{
+				Critical: fmt.Sprintf("Failed to notify the update of job ID : %d to user ID : %d : %v", jobdata.JobId, applicant, errf.Message),
+			})
+		}
+	}
+
+	return updated.Version, nil
+}
+
+// JobVersions returns the edit history of a job of the user, the latest version first
+func (c *CompanyService) JobVersions(ctx *gin.Context, userID int64, jobid string) (*[]sqlc.JobVersionsRow, *errs.Error) {
+
+	jobID, err := strconv.ParseInt(jobid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid job id.",
+			ToRespondWith: true,
+		}
+	}
+
+	versions, err := c.queries.JobVersions(ctx, sqlc.JobVersionsParams{
+		JobID: jobID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get job versions : " + err.Error(),
+		}
+	}
+	if len(versions) == 0 {
+		return nil, &errs.Error{
+			Type: errs.NotFound,
+			Message: "No edit history for this job, or it belongs to a different user.",
+			ToRespondWith: true,
+		}
+	}
+
+	return &versions, nil
+}
+
+// a job posting in the form it is stored
+type jobFields struct {
+	skills []string
+	extras []byte
+	criteria jobCriteria
+	deadline pgtype.Timestamptz
+	// false if the deadline was left out of the form, an edit keeps the deadline of the job then
+	deadlineSet bool
+}
+
+// validateJob checks a job posting, trims its fields in place and returns the rest of it as it is stored.
+// The form fields that are not part of the posting are kept as its extras.
+func validateJob(ctx *gin.Context, jobdata *dto.NewJobData) (*jobFields, *errs.Error) {
+
+	required := []struct {
+		name string
+		value *string
+	}{
+		{"Job title", &jobdata.JobTitle},
+		{"Job location", &jobdata.JobLocation},
+		{"Job type", &jobdata.JobType},
+		{"Job salary", &jobdata.JobSalary},
+		{"Job position", &jobdata.JobPosition},
+	}
+	for _, field := range required {
+		*field.value = strings.TrimSpace(*field.value)
+		if *field.value == "" {
+			return nil, &errs.Error{
+				Type: errs.MissingRequiredField,
+				Message: field.name + " is required.",
+				ToRespondWith: true,
+			}
+		}
+		if len(*field.value) > config.JobFieldMaxLength {
+			return nil, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("%s cannot be longer than %d characters.", field.name, config.JobFieldMaxLength),
+				ToRespondWith: true,
+			}
+		}
+	}
+	// All is the filter for every type of job
+	if strings.EqualFold(jobdata.JobType, "All") {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid job type.",
+			ToRespondWith: true,
+		}
+	}
+
+	// create map of extra params // flexiblity
+	extras := make(map[string]interface{})
+	for key, values := range ctx.Request.Form {
+		if _, exists := map[string]bool{
+			"JobId": true,
+			"JobLocation": true,
+			"JobTitle": true,
+			"JobDescription": true,
+			"JobType": true,
+			"JobSalary": true,
+			"SkillsRequired": true,
+			"JobPosition": true,
+			"MinCGPA": true,
+			"EligibleDepartments": true,
+			"EligibleCourses": true,
+			"EligibleYears": true,
+			"EligibleGenders": true,
+			"ApplicationDeadline": true,
+		}[key]; !exists {
+			if len(values) > 0 {
+				extras[key] = values[0]
+			}
+		}
+	}
+	extraJson, err := json.Marshal(extras)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to marshal extra params : " + err.Error(),
+		}
+	}
+
+	criteria, errf := eligibilityCriteria(jobdata)
+	if errf != nil {
+		return nil, errf
+	}
+	// the deadline is only checked to be in the future where it is set or changed, see NewJobPost and UpdateJob
+	_, deadlineSet := ctx.Request.Form["ApplicationDeadline"]
+	deadline := pgtype.Timestamptz{}
+	jobdata.ApplicationDeadline = strings.TrimSpace(jobdata.ApplicationDeadline)
+	if jobdata.ApplicationDeadline != "" {
+		parsed, err := utils.ParseLocalTime(jobdata.ApplicationDeadline)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: "Invalid application deadline.",
+				ToRespondWith: true,
+			}
+		}
+		deadline = pgtype.Timestamptz{Time: parsed, Valid: true}
+	}
+
+	return &jobFields{
+		skills: splitList(jobdata.SkillsRequired),
+		extras: extraJson,
+		criteria: criteria,
+		deadline: deadline,
+		deadlineSet: deadlineSet,
+	}, nil
+}
+
+// pastDeadline is the error for a deadline that is set or changed to a time that has passed
+func pastDeadline(deadline pgtype.Timestamptz) *errs.Error {
+
+	if !deadline.Valid || deadline.Time.After(time.Now()) {
+		return nil
+	}
+	return &errs.Error{
+		Type: errs.InvalidFormat,
+		Message: "The application deadline must be in the future.",
+		ToRespondWith: true,
+	}
 }
 
 func (c *CompanyService) ApplicantsData(ctx *gin.Context, userID int64, jobid string, appid string) (*[]sqlc.GetApplicantsRow, *errs.Error){
@@ -545,6 +777,30 @@ func (c *CompanyService) Offer(ctx *gin.Context, userID int64, applicationid str
 		} 
 	}
 
+	// a job with a pipeline only offers the applications that completed it
+	errf := c.offerable(ctx, userID, applicationId)
+	if errf != nil {
+		return errf
+	}
+	placement, err := c.queries.ApplicationPlacement(ctx, applicationId)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get application placement data : " + err.Error(),
+		}
+	}
+	refusals, errf := placementRefusals(ctx, c.queries, placement.UserID, placement.Salary, false)
+	if errf != nil {
+		return errf
+	}
+	if len(refusals) > 0 {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "The placement policy does not allow offering this student. " + strings.Join(refusals, " "),
+			ToRespondWith: true,
+		}
+	}
+
 	// TODO: atomicity problem 
 	// update interview status to 'Completed'
 	err = c.queries.InterviewStatusTo(ctx, sqlc.InterviewStatusToParams{
@@ -586,7 +842,7 @@ func (c *CompanyService) Offer(ctx *gin.Context, userID int64, applicationid str
 	}
 	go utils.SendEmailHTMLWithAttachmentFileHeader(template, []string{offerData.StudentEmail}, offerLetter)
 
-	errf := c.Notify.NewNotification(ctx, studentUserID, &dto.NotificationData{
+	errf = c.Notify.NewNotification(ctx, studentUserID, &dto.NotificationData{
 		Title: "Offered !!",
 		Description: fmt.Sprintf("Congratulations! New job offer received. (ID: %s)", applicationid),
 	})
@@ -660,188 +916,1879 @@ func (c *CompanyService) CancelInterview(ctx *gin.Context, userID int64, applica
 	return nil
 }
 
-const (
-	GForm = "GForms"
-	CSVJSON = "CSVJSON"
-	Manual = "Manual"
-)
-
-func (c *CompanyService) NewTestPost(ctx *gin.Context, userID int64, newtestData *dto.NewTestPost) (*errs.Error) {
+func (c *CompanyService) NewTestPost(ctx *gin.Context, userID int64, newtestData *dto.NewTestPost) (int64, *errs.Error) {
 
 	var formID string
 	var errf *errs.Error
+	var imported []dto.TestQuestionData
+
+	minScore, errf := markingScheme(newtestData.NegativeMarking, newtestData.MinScore)
+	if errf != nil {
+		return 0, errf
+	}
+	lateEntry, errf := testWindow(newtestData)
+	if errf != nil {
+		return 0, errf
+	}
 
 	switch newtestData.UploadMethod {
-	case GForm :
+	case config.TestUploadGForm :
 		gformData := new(dto.NewTestGForms)
 		err := ctx.Bind(gformData)
 		if err != nil {
-			return &errs.Error{
+			return 0, &errs.Error{
 				Type: errs.IncompleteForm,
 				Message: "Failed to bind GForm : " + err.Error(),
 			}
 		}
 		formID, errf = c.NewTestPostGForm(ctx, gformData)
 		if errf != nil {
-			return errf
+			return 0, errf
+		}
+	case config.TestUploadCSVJSON:
+		fileHeader, err := ctx.FormFile("QuestionsFile")
+		if err != nil {
+			return 0, &errs.Error{
+				Type: errs.MissingRequiredField,
+				Message: "Missing the CSV / JSON questions file.",
+				ToRespondWith: true,
+			}
+		}
+		// the whole file is validated before the test is created
+		var lineErrs []string
+		imported, lineErrs = testforms.ParseFile(fileHeader)
+		if lineErrs != nil {
+			return 0, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: "Invalid questions file :\n" + strings.Join(lineErrs, "\n"),
+				ToRespondWith: true,
+			}
 		}
-	case CSVJSON:
-		// TODO: 
-	case Manual:
-		// TODO:
+		formID = fmt.Sprintf("%s-%d-%d", config.TestUploadCSVJSON, userID, time.Now().UnixNano())
+	case config.TestUploadManual:
+		// there is no external file, the questions are added later through the question builder
+		// the file id still has to be unique
+		formID = fmt.Sprintf("%s-%d-%d", config.TestUploadManual, userID, time.Now().UnixNano())
 	default:
 	}	
 
-	err := c.queries.NewTest(ctx, sqlc.NewTestParams{
-		TestName: newtestData.Name,
-		Description: pgtype.Text{String: newtestData.Description, Valid: true},
-		Duration: newtestData.Duration,
-		QCount: newtestData.QuestionCount,
-		EndTime: pgtype.Timestamptz{Time: newtestData.EndDateTime, Valid: true},
-		Type: newtestData.Type,
-		UploadMethod: newtestData.UploadMethod,
-		JobID: pgtype.Int8{Int64: newtestData.BindedJobId, Valid: true},
-		UserID: userID,
-		FileID: formID,
-		Threshold: int32(newtestData.Threshold),
-	})
-	if err != nil {
-		var pgerr *pgconn.PgError
-		// TODO: this can also fail, have a fallback (else statement)
-		if errors.As(err, &pgerr) {
-			if pgerr.Code == errs.UniqueViolation {
+	// the test and its imported questions are inserted together, a failed question does not leave a partial test
+	var testID int64
+	errf = withTx(ctx, c.queries, func(qtx *sqlc.Queries) (*errs.Error) {
+		var err error
+		testID, err = qtx.NewTest(ctx, sqlc.NewTestParams{
+			TestName: newtestData.Name,
+			Description: pgtype.Text{String: newtestData.Description, Valid: true},
+			Duration: newtestData.Duration,
+			QCount: newtestData.QuestionCount,
+			EndTime: pgtype.Timestamptz{Time: newtestData.EndDateTime, Valid: true},
+			Type: newtestData.Type,
+			UploadMethod: newtestData.UploadMethod,
+			JobID: pgtype.Int8{Int64: newtestData.BindedJobId, Valid: true},
+			UserID: userID,
+			FileID: formID,
+			Threshold: int32(newtestData.Threshold),
+			NegativeMarking: int32(newtestData.NegativeMarking),
+			PartialCredit: newtestData.PartialCredit,
+			MinScore: minScore,
+			Shuffle: newtestData.Shuffle,
+			StartTime: pgtype.Timestamptz{Time: newtestData.StartDateTime, Valid: true},
+			LateEntry: lateEntry,
+			GracePeriod: newtestData.GracePeriod,
+		})
+		if err != nil {
+			var pgerr *pgconn.PgError
+			if errors.As(err, &pgerr) && pgerr.Code == errs.UniqueViolation {
 				return &errs.Error{
 					Type: errs.UniqueViolation,
 					Message: "The test already exists ! You cannot create multiple tests with the same test file.",
 					ToRespondWith: true,
 				}
-			} 
+			}
 			return &errs.Error{
 				Type: errs.Internal,
 				Message: "Failed to insert new test in db : " + err.Error(),
 			}
 		}
-	}
 
-	allEmails, err := c.queries.GetAllApplicantsEmailsForJob(ctx, newtestData.BindedJobId)
-	if err != nil {
-		return &errs.Error{
-			Type: errs.Internal,
-			Message: "Failed to get all emails of applicants for job to send new test email to : " + err.Error(),
+		if len(imported) > 0 {
+			return insertImportedQuestions(ctx, qtx, testID, imported)
 		}
-	} else {
-
-		jobDetails, err := c.queries.GetJobDetails(ctx, newtestData.BindedJobId)
-		if err != nil {
-			return &errs.Error{
-				Type: errs.Internal,
-				Message: "Failed to get job details : " + err.Error(),
-			}
-		} else {
+		return nil
+	})
+	if errf != nil {
+		return 0, errf
+	}
 
-			newtestData.JobTitle = jobDetails.Title
-			newtestData.CompanyName = jobDetails.CompanyName
-			newtestData.FormattedEndDate = newtestData.EndDateTime.Format("2006-01-02")
-			newtestData.FormattedEndTime = newtestData.EndDateTime.Format("15:04")
+	// the content of the test and its media are cached in the background, so the first students do not wait for the downloads
+	warmTestContent(c.queries, c.Tests, c.RedisClient, testID, newtestData.UploadMethod, formID)
 
-			template, err := utils.DynamicHTML("./template/emails/newTestEmail.html", newtestData)
-			if err != nil {
-				return &errs.Error{
-					Type: errs.Internal,
-					Message: "Failed to generate template for new test email : " + err.Error(),
-				}
-			} else {
-				go utils.SendEmailHTML(template, allEmails)
-			}
-		}
-	}
+	// a test built on the platform is announced once it has its first question
+	errf = c.announceTest(ctx, testID)
 
-	return errf
+	return testID, errf
 }
 
 func (c *CompanyService) NewTestPostGForm(ctx *gin.Context, gformData *dto.NewTestGForms) (string, *errs.Error) {
 
-	// get the raw responders link from the link provided by the user 
-	paramIndex := strings.Index(gformData.ResponderLink, "?")
-	if paramIndex != -1 {
-		gformData.ResponderLink = gformData.ResponderLink[:paramIndex]
-	}
-	// check if we already have the form metadata in the Redis Cache
-	exists, err := c.RedisClient.Exists(ctx, gformData.ResponderLink).Result()
-	// if not
-	if exists == 0 {
-		// call drive change api to get file changes from the last start token
-		newList, err := c.GAPIService.DriveChanges()
-		if err != nil {
-			return "", &errs.Error{
-				Type: errs.Internal,
-				Message: "Failed to get GDrive changes : " + err.Error(),
-			}
-		}
-		changes := newList.Changes
-
-		// loop over every changed object
-		for _, change := range changes {
-			// check if it is not null and has a mimetype of apps.form
-			if change.File != nil && change.File.MimeType == "application/vnd.google-apps.form" {
-				// get the metadata from the forms api
-				formData, err := c.GAPIService.GetFormMetadata(change.FileId)
-				if err != nil {
-					return "", &errs.Error{
-						Type: errs.Internal,
-						Message: "Failed to get GForm metadata : " + err.Error(),
-					}
-				}
-				// Set the key:value in the Redis Cache {responderUri : formId}
-				err = c.RedisClient.Set(ctx, formData.ResponderUri, formData.FormId, 0).Err()
-				if err != nil {
-					return "", &errs.Error{
-						Type: errs.Internal,
-						Message: "Failed to insert into redis : " + err.Error(),
-					}
-				}
-
-				// Do this for every form 
-				// We do not consider if we have already found our result
-				// We continue for every updated form to keep Cache up to date
-				// This can be changed later
-				//TODO:
-			}
-		}
-	} else if err != nil {
-		return "", &errs.Error{
-			Type: errs.Internal,
-			Message: "Failed to get from redis : " + err.Error(),
-		}
-	}
-
-	formID, err := c.RedisClient.Get(ctx, gformData.ResponderLink).Result()
-	if err == redis.Nil {
-		// the result still does not exist
+	gformData.ResponderLink = responderLink(gformData.ResponderLink)
+	// resolve the form id through the test provider
+	formID, err := c.Tests.ResolveFormID(ctx, gformData.ResponderLink)
+	if errors.Is(err, apicalls.ErrFormNotShared) {
 		// the user has not provided you with the access
 		return "", &errs.Error{
 			Type: errs.IncompleteAction,
-			Message: "The 'Editor Access' has not been shared with the given collaborator email.",
+			Message: "The 'Editor Access' has not been shared with the given collaborator email. A form shared in the last minute may not be picked up yet.",
 			ToRespondWith: true,
 		}
 	} else if err != nil {
 		return "", &errs.Error{
 			Type: errs.Internal,
-			Message: "Failed to get from redis : " + err.Error(),
+			Message: "Failed to resolve the form id : " + err.Error(),
 		}	
 	}
 	
 	return formID, nil
 }
 
-func (c *CompanyService) ScheduledData(ctx *gin.Context, userID int64, eventtype string) (*dto.Upcoming, *errs.Error) {
-	// switch between event types
-	switch eventtype {
-	case "interviews":
-		uInts, err := c.queries.ScheduledInterviewsCompany(ctx, userID)
-		if err != nil {
-			return nil, &errs.Error{
-				Type: errs.Internal,
+// FormStatus returns if the test provider can access the form of a responder link, before a test is created from it
+// the forms shared with the collaborator email are picked up in the background within a minute
+func (c *CompanyService) FormStatus(ctx *gin.Context, link string) (*dto.FormStatus, *errs.Error) {
+
+	status := &dto.FormStatus{
+		ResponderLink: responderLink(link),
+	}
+	_, err := c.Tests.ResolveFormID(ctx, status.ResponderLink)
+	if errors.Is(err, apicalls.ErrFormNotShared) {
+		status.Status = config.FormNotShared
+		status.Message = "The 'Editor Access' has not been shared with the given collaborator email, or it was shared in the last minute."
+		return status, nil
+	} else if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to resolve the form id : " + err.Error(),
+		}
+	}
+
+	status.Status = config.FormAccessGranted
+	status.Message = "The form can be used for a test."
+	return status, nil
+}
+
+// responderLink returns the raw responders link from the link provided by the user, without its params
+func responderLink(link string) string {
+	paramIndex := strings.Index(link, "?")
+	if paramIndex != -1 {
+		return link[:paramIndex]
+	}
+	return link
+}
+
+// insertImportedQuestions stores the questions parsed from a CSV / JSON file for a newly created test, in the transaction of the test
+func insertImportedQuestions(ctx *gin.Context, queries *sqlc.Queries, testID int64, questions []dto.TestQuestionData) (*errs.Error) {
+
+	for _, q := range questions {
+		_, err := queries.InsertTestQuestion(ctx, sqlc.InsertTestQuestionParams{
+			TestID: testID,
+			Position: q.Position,
+			Type: q.Type,
+			Title: q.Title,
+			Description: pgtype.Text{String: q.Description, Valid: q.Description != ""},
+			Options: q.Options,
+			CorrectAnswer: q.CorrectAnswer,
+			Points: q.Points,
+		})
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to insert imported test question : " + err.Error(),
+			}
+		}
+	}
+
+	err := queries.SyncTestQCount(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update question count : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// announceTest emails the applicants of the job about the test, once.
+// A test built on the platform has no questions when it is created, it is announced when it gets its first question.
+func (c *CompanyService) announceTest(ctx *gin.Context, testID int64) (*errs.Error) {
+
+	test, err := c.queries.AnnounceTest(ctx, testID)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			// already announced, or nothing to announce yet
+			return nil
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to mark the test as announced : " + err.Error(),
+		}
+	}
+
+	allEmails, err := c.queries.GetAllApplicantsEmailsForJob(ctx, test.JobID.Int64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get all emails of applicants for job to send new test email to : " + err.Error(),
+		}
+	}
+	jobDetails, err := c.queries.GetJobDetails(ctx, test.JobID.Int64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get job details : " + err.Error(),
+		}
+	}
+
+	emailData := dto.NewTestPost{
+		Name: test.TestName,
+		Description: test.Description.String,
+		Duration: test.Duration,
+		QuestionCount: test.QCount,
+		Type: test.Type,
+		EndDateTime: test.EndTime.Time,
+		StartDateTime: test.StartTime.Time,
+		GracePeriod: test.GracePeriod,
+		BindedJobId: test.JobID.Int64,
+		JobTitle: jobDetails.Title,
+		CompanyName: jobDetails.CompanyName,
+		FormattedEndDate: test.EndTime.Time.Format("2006-01-02"),
+		FormattedEndTime: test.EndTime.Time.Format("15:04"),
+		FormattedStartDate: test.StartTime.Time.Format("2006-01-02"),
+		FormattedStartTime: test.StartTime.Time.Format("15:04"),
+	}
+	if test.LateEntry.Valid {
+		emailData.LateEntry = &test.LateEntry.Int64
+	}
+
+	template, err := utils.DynamicHTML("./template/emails/newTestEmail.html", emailData)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to generate template for new test email : " + err.Error(),
+		}
+	}
+	go utils.SendEmailHTML(template, allEmails)
+
+	return nil
+}
+
+// editableTest checks if the test belongs to the user and if its questions can still be changed.
+// Only tests built on the platform can be edited, and only before anyone has attempted them.
+func (c *CompanyService) editableTest(ctx *gin.Context, userID int64, testID int64) (*errs.Error) {
+
+	testData, errf := c.unattemptedTest(ctx, userID, testID)
+	if errf != nil {
+		return errf
+	}
+
+	if testData.UploadMethod == config.TestUploadGForm {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "The questions of a Google Forms test are edited in the form itself.",
+			ToRespondWith: true,
+		}
+	}
+
+	return nil
+}
+
+// unattemptedTest checks if the test belongs to the user and if nobody has attempted it yet, the test data is returned.
+// The sections of a test can be changed for every upload method until then.
+func (c *CompanyService) unattemptedTest(ctx *gin.Context, userID int64, testID int64) (*sqlc.EditableTestDataRow, *errs.Error) {
+
+	testData, err := c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	if testData.Attempts > 0 || testData.EndTime.Time.Before(time.Now()) {
+		return nil, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The test has already been attempted or has ended. It cannot be changed now.",
+			ToRespondWith: true,
+		}
+	}
+
+	return &testData, nil
+}
+
+// questionSection checks that the section of a question belongs to its test, 0 means no section
+func (c *CompanyService) questionSection(ctx *gin.Context, testID int64, sectionID int64) (pgtype.Int8, *errs.Error) {
+
+	if sectionID == 0 {
+		return pgtype.Int8{Valid: false}, nil
+	}
+
+	sections, err := c.queries.ListTestSections(ctx, testID)
+	if err != nil {
+		return pgtype.Int8{}, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test sections : " + err.Error(),
+		}
+	}
+	for _, s := range sections {
+		if s.SectionID == sectionID {
+			return pgtype.Int8{Int64: sectionID, Valid: true}, nil
+		}
+	}
+
+	return pgtype.Int8{}, &errs.Error{
+		Type: errs.NotFound,
+		Message: "The section does not exist in this test.",
+		ToRespondWith: true,
+	}
+}
+
+// questionsChanged keeps the question count in sync and removes the cached test data after an edit,
+// the test is announced if this gave it its first question
+func (c *CompanyService) questionsChanged(ctx *gin.Context, testID int64) (*errs.Error) {
+
+	err := c.queries.SyncTestQCount(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update question count : " + err.Error(),
+		}
+	}
+
+	errf := c.sectionsChanged(ctx, testID)
+	if errf != nil {
+		return errf
+	}
+
+	return c.announceTest(ctx, testID)
+}
+
+// sectionsChanged removes the cached test data after an edit of its sections, the items are cached with their sections
+func (c *CompanyService) sectionsChanged(ctx *gin.Context, testID int64) (*errs.Error) {
+
+	err := c.RedisClient.Del(ctx, fmt.Sprintf("%dorder", testID), fmt.Sprintf("%ddata", testID), fmt.Sprintf("%dsections", testID), fmt.Sprintf("%dquestions", testID), fmt.Sprintf("%drules", testID)).Err()
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to clear cached test data : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (c *CompanyService) TestQuestions(ctx *gin.Context, userID int64, testid string) (*[]sqlc.GetTestQuestionsRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	questions, err := c.queries.GetTestQuestions(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test questions : " + err.Error(),
+		}
+	}
+
+	return &questions, nil
+}
+
+func (c *CompanyService) NewTestQuestion(ctx *gin.Context, userID int64, data *dto.TestQuestionData) (int64, *errs.Error) {
+
+	errf := c.editableTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return 0, errf
+	}
+
+	err := testforms.Validate(data)
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+
+	sectionID, errf := c.questionSection(ctx, data.TestID, data.SectionID)
+	if errf != nil {
+		return 0, errf
+	}
+
+	questionID, err := c.queries.InsertTestQuestion(ctx, sqlc.InsertTestQuestionParams{
+		TestID: data.TestID,
+		Position: data.Position,
+		Type: data.Type,
+		Title: data.Title,
+		Description: pgtype.Text{String: data.Description, Valid: data.Description != ""},
+		Options: data.Options,
+		CorrectAnswer: data.CorrectAnswer,
+		Points: data.Points,
+		SectionID: sectionID,
+	})
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to insert test question : " + err.Error(),
+		}
+	}
+
+	return questionID, c.questionsChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) UpdateTestQuestion(ctx *gin.Context, userID int64, data *dto.TestQuestionData) (*errs.Error) {
+
+	errf := c.editableTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return errf
+	}
+
+	err := testforms.Validate(data)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+
+	sectionID, errf := c.questionSection(ctx, data.TestID, data.SectionID)
+	if errf != nil {
+		return errf
+	}
+
+	_, err = c.queries.UpdateTestQuestion(ctx, sqlc.UpdateTestQuestionParams{
+		QuestionID: data.QuestionID,
+		TestID: data.TestID,
+		Position: data.Position,
+		Type: data.Type,
+		Title: data.Title,
+		Description: pgtype.Text{String: data.Description, Valid: data.Description != ""},
+		Options: data.Options,
+		CorrectAnswer: data.CorrectAnswer,
+		Points: data.Points,
+		SectionID: sectionID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The question does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update test question : " + err.Error(),
+		}
+	}
+
+	return c.questionsChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) DeleteTestQuestion(ctx *gin.Context, userID int64, testid string, questionid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	questionID, err := strconv.ParseInt(questionid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid question id.",
+			ToRespondWith: true,
+		}
+	}
+
+	errf := c.editableTest(ctx, userID, testID)
+	if errf != nil {
+		return errf
+	}
+
+	_, err = c.queries.DeleteTestQuestion(ctx, sqlc.DeleteTestQuestionParams{
+		QuestionID: questionID,
+		TestID: testID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The question does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete test question : " + err.Error(),
+		}
+	}
+
+	return c.questionsChanged(ctx, testID)
+}
+
+func (c *CompanyService) ShuffleAudit(ctx *gin.Context, userID int64, testid string, resultid string) (*dto.ShuffleAudit, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	resultID, err := strconv.ParseInt(resultid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid result id.",
+			ToRespondWith: true,
+		}
+	}
+
+	auditData, err := c.queries.ShuffleAuditData(ctx, sqlc.ShuffleAuditDataParams{
+		TestID: testID,
+		ResultID: resultID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. Or the result does not belong to this test.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	// the order is rebuilt from the form the same way the test is served, see StudentService.TakeTest
+	form, err := testforms.Questions(ctx, c.queries, c.Tests, testID, auditData.UploadMethod, auditData.FileID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test questions : " + err.Error(),
+		}
+	}
+
+	order := make([]string, 0, len(form.Items))
+	questions := map[string]bool{}
+	for _, item := range form.Items {
+		order = append(order, item.ItemId)
+		if item.QuestionItem != nil {
+			questions[item.ItemId] = true
+		}
+	}
+
+	user := strconv.FormatInt(auditData.UserID, 10)
+
+	// the questions drawn from the question bank for the candidate
+	if auditData.UploadMethod == config.TestUploadManual || auditData.UploadMethod == config.TestUploadCSVJSON {
+		rules, err := c.queries.ListDrawRules(ctx, testID)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to get draw rules : " + err.Error(),
+			}
+		}
+		if len(rules) > 0 {
+			counts := make(map[int64]int32, len(rules))
+			for _, r := range rules {
+				counts[r.RuleID] = r.Count
+			}
+			testQuestions, err := c.queries.GetTestQuestions(ctx, testID)
+			if err != nil {
+				return nil, &errs.Error{
+					Type: errs.Internal,
+					Message: "Failed to get test questions : " + err.Error(),
+				}
+			}
+			itemRules := map[string]int64{}
+			for _, q := range testQuestions {
+				if q.RuleID.Valid {
+					itemRules[q.ItemID] = q.RuleID.Int64
+				}
+			}
+			order = testforms.DrawOrder(order, itemRules, counts, testforms.Seed(auditData.ShuffleSeed, user, "draw"))
+		}
+	}
+
+	audit := &dto.ShuffleAudit{
+		Shuffled: auditData.Shuffle,
+		UserID: auditData.UserID,
+		Order: order,
+		Options: map[string][]string{},
+	}
+	if auditData.Shuffle {
+		audit.Order = testforms.ShuffleOrder(order, apicalls.SectionIndex(form), questions, testforms.Seed(auditData.ShuffleSeed, user))
+	}
+	for _, item := range form.Items {
+		if item.QuestionItem == nil || item.QuestionItem.Question == nil || item.QuestionItem.Question.ChoiceQuestion == nil {
+			continue
+		}
+		if !slices.Contains(audit.Order, item.ItemId) {
+			continue
+		}
+		if auditData.Shuffle {
+			testforms.ShuffleOptions(item, testforms.Seed(auditData.ShuffleSeed, user, item.ItemId))
+		}
+		options := []string{}
+		for _, o := range item.QuestionItem.Question.ChoiceQuestion.Options {
+			options = append(options, o.Value)
+		}
+		audit.Options[item.ItemId] = options
+	}
+
+	return audit, nil
+}
+
+func (c *CompanyService) ProctorTimeline(ctx *gin.Context, userID int64, testid string, resultid string) (*dto.ProctorTimeline, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	resultID, err := strconv.ParseInt(resultid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid result id.",
+			ToRespondWith: true,
+		}
+	}
+
+	attempt, err := c.queries.ProctorAttemptData(ctx, sqlc.ProctorAttemptDataParams{
+		TestID: testID,
+		ResultID: resultID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. Or the result does not belong to this test.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get attempt data : " + err.Error(),
+		}
+	}
+
+	events, err := c.queries.ProctorTimeline(ctx, resultID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get proctoring events : " + err.Error(),
+		}
+	}
+
+	counts := map[string]int64{}
+	for _, e := range events {
+		counts[e.EventType]++
+	}
+
+	return &dto.ProctorTimeline{
+		StudentName: attempt.StudentName,
+		RollNumber: attempt.RollNumber,
+		StartTime: attempt.StartTime,
+		EndTime: attempt.EndTime,
+		Counts: counts,
+		Events: events,
+	}, nil
+}
+
+func (c *CompanyService) TestOverride(ctx *gin.Context, userID int64, data *dto.TestOverride) (*errs.Error) {
+
+	_, err := c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	return setTestOverride(ctx, c.queries, c.RedisClient, userID, data)
+}
+
+func (c *CompanyService) TestOverrides(ctx *gin.Context, userID int64, testid string) (*[]sqlc.ListTestOverridesRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	overrides, err := c.queries.ListTestOverrides(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test overrides : " + err.Error(),
+		}
+	}
+
+	return &overrides, nil
+}
+
+func (c *CompanyService) RemoveTestOverride(ctx *gin.Context, userID int64, testid string, studentid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	studentID, err := strconv.ParseInt(studentid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid student id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	return removeTestOverride(ctx, c.queries, c.RedisClient, testID, studentID)
+}
+
+func (c *CompanyService) Reevaluate(ctx *gin.Context, userID int64, data *dto.Reevaluation) (*errs.Error) {
+
+	data.Reason = strings.TrimSpace(data.Reason)
+	if data.Reason == "" {
+		return &errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "A reason is required for the re-evaluation.",
+			ToRespondWith: true,
+		}
+	}
+	if len(data.Reason) > config.ReevaluationReasonMaxLength {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: fmt.Sprintf("The reason cannot be longer than %d characters.", config.ReevaluationReasonMaxLength),
+			ToRespondWith: true,
+		}
+	}
+
+	testData, err := c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	// only a test that has ended, with no attempts in progress, is evaluated
+	_, err = c.queries.TestAuthorization(ctx, sqlc.TestAuthorizationParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: "The test has not ended yet or has attempts in progress. It can be re-evaluated once they are over.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to check test state : " + err.Error(),
+		}
+	}
+
+	published, err := c.queries.IsTestPublished(ctx, data.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to check if test is published : " + err.Error(),
+		}
+	}
+	if published {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The results of this test are already published. It cannot be re-evaluated now.",
+			ToRespondWith: true,
+		}
+	}
+
+	if len(data.AnswerKey) > 0 {
+		errf := c.fixAnswerKey(ctx, data.TestID, testData.UploadMethod, data.AnswerKey)
+		if errf != nil {
+			return errf
+		}
+	}
+
+	// the responses are marked again, the draft is sent to the company as a new version of the result
+	go func() {
+		testresgen.GenerateCumulativeTestResult(c.queries, c.Tests, data.TestID, data.Reason)
+	} ()
+
+	return nil
+}
+
+// fixAnswerKey replaces the correct answers and points of the questions of a test built on the platform.
+// Every fix is validated as the whole question before any of them is saved.
+func (c *CompanyService) fixAnswerKey(ctx *gin.Context, testID int64, uploadMethod string, fixes []dto.AnswerKeyFix) (*errs.Error) {
+
+	if uploadMethod == config.TestUploadGForm {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "The answer key of a Google Forms test is fixed in the form itself. Re-evaluate it without an answer key.",
+			ToRespondWith: true,
+		}
+	}
+
+	questions, err := c.queries.GetTestQuestions(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test questions : " + err.Error(),
+		}
+	}
+
+	fixed := make([]dto.TestQuestionData, 0, len(fixes))
+	for _, f := range fixes {
+		i := slices.IndexFunc(questions, func(q sqlc.GetTestQuestionsRow) bool {
+			return q.QuestionID == f.QuestionID
+		})
+		if i < 0 {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: fmt.Sprintf("The question %d does not exist in this test.", f.QuestionID),
+				ToRespondWith: true,
+			}
+		}
+		q := questions[i]
+		data := dto.TestQuestionData{
+			QuestionID: q.QuestionID,
+			TestID: testID,
+			Position: q.Position,
+			Type: q.Type,
+			Title: q.Title,
+			Description: q.Description.String,
+			Options: q.Options,
+			CorrectAnswer: f.CorrectAnswer,
+			Points: f.Points,
+		}
+		err = testforms.Validate(&data)
+		if err != nil {
+			return &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("Question %d : %v", f.QuestionID, err),
+				ToRespondWith: true,
+			}
+		}
+		fixed = append(fixed, data)
+	}
+
+	for _, data := range fixed {
+		_, err = c.queries.UpdateAnswerKey(ctx, sqlc.UpdateAnswerKeyParams{
+			QuestionID: data.QuestionID,
+			TestID: testID,
+			CorrectAnswer: data.CorrectAnswer,
+			Points: data.Points,
+		})
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to update answer key : " + err.Error(),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *CompanyService) ResultVersions(ctx *gin.Context, userID int64, testid string) (*[]sqlc.ListResultVersionsRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	versions, err := c.queries.ListResultVersions(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get result versions : " + err.Error(),
+		}
+	}
+
+	return &versions, nil
+}
+
+func (c *CompanyService) ResultDiff(ctx *gin.Context, userID int64, testid string, from string, to string) (*dto.ResultDiff, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	// the last two versions are compared by default
+	versions, err := c.queries.LatestResultVersions(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get result versions : " + err.Error(),
+		}
+	}
+	diff := &dto.ResultDiff{}
+	if len(versions) == 2 {
+		diff.From, diff.To = versions[1], versions[0]
+	}
+	var errf *errs.Error
+	diff.From, errf = resultVersion(from, diff.From)
+	if errf != nil {
+		return nil, errf
+	}
+	diff.To, errf = resultVersion(to, diff.To)
+	if errf != nil {
+		return nil, errf
+	}
+	if diff.From == 0 || diff.To == 0 {
+		return nil, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The test needs at least two versions of its result to compare.",
+			ToRespondWith: true,
+		}
+	}
+	for _, version := range []int32{diff.From, diff.To} {
+		_, err = c.queries.ResultVersionExists(ctx, sqlc.ResultVersionExistsParams{
+			TestID: testID,
+			Version: version,
+		})
+		if err != nil {
+			if err.Error() == errs.NoRowsMatch {
+				return nil, &errs.Error{
+					Type: errs.NotFound,
+					Message: fmt.Sprintf("The version %d of the result does not exist.", version),
+					ToRespondWith: true,
+				}
+			}
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to get result version : " + err.Error(),
+			}
+		}
+	}
+
+	diff.Changes, err = c.queries.ResultVersionDiff(ctx, sqlc.ResultVersionDiffParams{
+		TestID: testID,
+		FromVersion: diff.From,
+		ToVersion: diff.To,
+	})
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to compare result versions : " + err.Error(),
+		}
+	}
+
+	return diff, nil
+}
+
+// resultVersion parses the version of a result from the request, def if it is empty
+func resultVersion(param string, def int32) (int32, *errs.Error) {
+
+	if param == "" {
+		return def, nil
+	}
+	version, err := strconv.ParseInt(param, 10, 32)
+	if err != nil || version < 1 {
+		return 0, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid result version.",
+			ToRespondWith: true,
+		}
+	}
+
+	return int32(version), nil
+}
+
+// ResultDraftPath returns the path to a version of the test's result draft, the latest if the version is empty
+func (c *CompanyService) ResultDraftPath(ctx *gin.Context, userID int64, testid string, version string) (string, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return "", &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return "", &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return "", &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	versions, err := c.queries.LatestResultVersions(ctx, testID)
+	if err != nil {
+		return "", &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get result versions : " + err.Error(),
+		}
+	}
+	if len(versions) == 0 {
+		return "", &errs.Error{
+			Type: errs.NotFound,
+			Message: "The result of this test has not been generated yet.",
+			ToRespondWith: true,
+		}
+	}
+	v, errf := resultVersion(version, versions[0])
+	if errf != nil {
+		return "", errf
+	}
+	_, err = c.queries.ResultVersionExists(ctx, sqlc.ResultVersionExistsParams{
+		TestID: testID,
+		Version: v,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return "", &errs.Error{
+				Type: errs.NotFound,
+				Message: fmt.Sprintf("The version %d of the result does not exist.", v),
+				ToRespondWith: true,
+			}
+		}
+		return "", &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get result version : " + err.Error(),
+		}
+	}
+
+	return testresgen.ResultVersionPath(testID, v), nil
+}
+
+// ItemAnalysis returns the analysis of every question of a test, from the responses as they were last evaluated
+func (c *CompanyService) ItemAnalysis(ctx *gin.Context, userID int64, testid string) (*[]dto.ItemAnalysis, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	// the points of the responses are only set once the test is evaluated
+	versions, err := c.queries.LatestResultVersions(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get result versions : " + err.Error(),
+		}
+	}
+	if len(versions) == 0 {
+		return nil, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The test has not been evaluated yet.",
+			ToRespondWith: true,
+		}
+	}
+
+	items, err := testresgen.ItemAnalysis(ctx, c.queries, c.Tests, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get item analysis : " + err.Error(),
+		}
+	}
+
+	return &items, nil
+}
+
+func (c *CompanyService) TestSections(ctx *gin.Context, userID int64, testid string) (*[]sqlc.ListTestSectionsRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	sections, err := c.queries.ListTestSections(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test sections : " + err.Error(),
+		}
+	}
+
+	return &sections, nil
+}
+
+func (c *CompanyService) NewTestSection(ctx *gin.Context, userID int64, data *dto.TestSectionData) (int64, *errs.Error) {
+
+	testData, errf := c.unattemptedTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return 0, errf
+	}
+
+	duration, minScore, errf := c.validateSection(ctx, testData, data)
+	if errf != nil {
+		return 0, errf
+	}
+
+	sectionID, err := c.queries.InsertTestSection(ctx, sqlc.InsertTestSectionParams{
+		TestID: data.TestID,
+		Name: data.Name,
+		Position: data.Position,
+		Duration: duration,
+		MinScore: minScore,
+	})
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == errs.UniqueViolation {
+			return 0, &errs.Error{
+				Type: errs.ObjectExists,
+				Message: "A section with this name already exists in the test.",
+				ToRespondWith: true,
+			}
+		}
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to insert test section : " + err.Error(),
+		}
+	}
+
+	return sectionID, c.sectionsChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) UpdateTestSection(ctx *gin.Context, userID int64, data *dto.TestSectionData) (*errs.Error) {
+
+	testData, errf := c.unattemptedTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return errf
+	}
+
+	duration, minScore, errf := c.validateSection(ctx, testData, data)
+	if errf != nil {
+		return errf
+	}
+
+	_, err := c.queries.UpdateTestSection(ctx, sqlc.UpdateTestSectionParams{
+		SectionID: data.SectionID,
+		TestID: data.TestID,
+		Name: data.Name,
+		Position: data.Position,
+		Duration: duration,
+		MinScore: minScore,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The section does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == errs.UniqueViolation {
+			return &errs.Error{
+				Type: errs.ObjectExists,
+				Message: "A section with this name already exists in the test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update test section : " + err.Error(),
+		}
+	}
+
+	return c.sectionsChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) DeleteTestSection(ctx *gin.Context, userID int64, testid string, sectionid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	sectionID, err := strconv.ParseInt(sectionid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid section id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, errf := c.unattemptedTest(ctx, userID, testID)
+	if errf != nil {
+		return errf
+	}
+
+	// the questions of the section are not deleted, they move to the first section
+	_, err = c.queries.DeleteTestSection(ctx, sqlc.DeleteTestSectionParams{
+		SectionID: sectionID,
+		TestID: testID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The section does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete test section : " + err.Error(),
+		}
+	}
+
+	return c.sectionsChanged(ctx, testID)
+}
+
+// validateSection checks a section before it is stored and returns its duration and cutoff as they are stored.
+// The section timers together cannot be longer than the test itself.
+func (c *CompanyService) validateSection(ctx *gin.Context, testData *sqlc.EditableTestDataRow, data *dto.TestSectionData) (pgtype.Int8, pgtype.Int4, *errs.Error) {
+
+	data.Name = strings.TrimSpace(data.Name)
+	if data.Name == "" {
+		return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "Section name is required.",
+			ToRespondWith: true,
+		}
+	}
+	if data.Position < 0 {
+		return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Section position cannot be negative.",
+			ToRespondWith: true,
+		}
+	}
+
+	minScore := pgtype.Int4{Valid: false}
+	if data.MinScore != nil {
+		if *data.MinScore < 0 {
+			return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+				Type: errs.PreconditionFailed,
+				Message: "Section cutoff cannot be negative.",
+				ToRespondWith: true,
+			}
+		}
+		minScore = pgtype.Int4{Int32: int32(*data.MinScore), Valid: true}
+	}
+
+	duration := pgtype.Int8{Valid: false}
+	if data.Duration == nil {
+		return duration, minScore, nil
+	}
+	if *data.Duration <= 0 {
+		return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Section duration must be at least 1 minute.",
+			ToRespondWith: true,
+		}
+	}
+	duration = pgtype.Int8{Int64: *data.Duration, Valid: true}
+
+	sections, err := c.queries.ListTestSections(ctx, data.TestID)
+	if err != nil {
+		return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test sections : " + err.Error(),
+		}
+	}
+	total := *data.Duration
+	for _, s := range sections {
+		if s.SectionID != data.SectionID {
+			total += s.Duration.Int64
+		}
+	}
+	if total > testData.Duration {
+		return pgtype.Int8{}, pgtype.Int4{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: fmt.Sprintf("The section durations add up to %d minutes, the test is only %d minutes long.", total, testData.Duration),
+			ToRespondWith: true,
+		}
+	}
+
+	return duration, minScore, nil
+}
+
+func (c *CompanyService) BankQuestions(ctx *gin.Context, userID int64, tag string, difficulty string) (*[]sqlc.ListBankQuestionsRow, *errs.Error) {
+
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	err := testforms.ValidateDifficulty(difficulty)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+
+	questions, err := c.queries.ListBankQuestions(ctx, sqlc.ListBankQuestionsParams{
+		UserID: userID,
+		Tag: pgtype.Text{String: tag, Valid: tag != ""},
+		Difficulty: pgtype.Text{String: difficulty, Valid: difficulty != ""},
+	})
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get bank questions : " + err.Error(),
+		}
+	}
+
+	return &questions, nil
+}
+
+func (c *CompanyService) NewBankQuestion(ctx *gin.Context, userID int64, data *dto.BankQuestionData) (int64, *errs.Error) {
+
+	err := testforms.ValidateBank(data)
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+
+	questionID, err := c.queries.InsertBankQuestion(ctx, sqlc.InsertBankQuestionParams{
+		UserID: userID,
+		Type: data.Type,
+		Title: data.Title,
+		Description: pgtype.Text{String: data.Description, Valid: data.Description != ""},
+		Options: data.Options,
+		CorrectAnswer: data.CorrectAnswer,
+		Tags: data.Tags,
+		Difficulty: data.Difficulty,
+	})
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to insert bank question : " + err.Error(),
+		}
+	}
+
+	return questionID, nil
+}
+
+// the tests that already drew from the bank keep their copy of the question, until their pool is refreshed
+func (c *CompanyService) UpdateBankQuestion(ctx *gin.Context, userID int64, data *dto.BankQuestionData) (*errs.Error) {
+
+	err := testforms.ValidateBank(data)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.UpdateBankQuestion(ctx, sqlc.UpdateBankQuestionParams{
+		QuestionID: data.QuestionID,
+		UserID: userID,
+		Type: data.Type,
+		Title: data.Title,
+		Description: pgtype.Text{String: data.Description, Valid: data.Description != ""},
+		Options: data.Options,
+		CorrectAnswer: data.CorrectAnswer,
+		Tags: data.Tags,
+		Difficulty: data.Difficulty,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The question does not exist in your question bank.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update bank question : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (c *CompanyService) DeleteBankQuestion(ctx *gin.Context, userID int64, questionid string) (*errs.Error) {
+
+	questionID, err := strconv.ParseInt(questionid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid question id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.DeleteBankQuestion(ctx, sqlc.DeleteBankQuestionParams{
+		QuestionID: questionID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The question does not exist in your question bank.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete bank question : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (c *CompanyService) DrawRules(ctx *gin.Context, userID int64, testid string) (*[]sqlc.ListDrawRulesRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	rules, err := c.queries.ListDrawRules(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get draw rules : " + err.Error(),
+		}
+	}
+
+	return &rules, nil
+}
+
+func (c *CompanyService) NewDrawRule(ctx *gin.Context, userID int64, data *dto.DrawRuleData) (int64, *errs.Error) {
+
+	errf := c.editableTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return 0, errf
+	}
+
+	difficulty, sectionID, errf := c.validateDrawRule(ctx, data)
+	if errf != nil {
+		return 0, errf
+	}
+
+	ruleID, err := c.queries.InsertDrawRule(ctx, sqlc.InsertDrawRuleParams{
+		TestID: data.TestID,
+		Tags: data.Tags,
+		Difficulty: difficulty,
+		Count: data.Count,
+		Points: data.Points,
+		Position: data.Position,
+		SectionID: sectionID,
+	})
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to insert draw rule : " + err.Error(),
+		}
+	}
+
+	return ruleID, c.drawRulesChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) UpdateDrawRule(ctx *gin.Context, userID int64, data *dto.DrawRuleData) (*errs.Error) {
+
+	errf := c.editableTest(ctx, userID, data.TestID)
+	if errf != nil {
+		return errf
+	}
+
+	difficulty, sectionID, errf := c.validateDrawRule(ctx, data)
+	if errf != nil {
+		return errf
+	}
+
+	_, err := c.queries.UpdateDrawRule(ctx, sqlc.UpdateDrawRuleParams{
+		RuleID: data.RuleID,
+		TestID: data.TestID,
+		Tags: data.Tags,
+		Difficulty: difficulty,
+		Count: data.Count,
+		Points: data.Points,
+		Position: data.Position,
+		SectionID: sectionID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The draw rule does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update draw rule : " + err.Error(),
+		}
+	}
+
+	return c.drawRulesChanged(ctx, data.TestID)
+}
+
+func (c *CompanyService) DeleteDrawRule(ctx *gin.Context, userID int64, testid string, ruleid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+	ruleID, err := strconv.ParseInt(ruleid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid rule id.",
+			ToRespondWith: true,
+		}
+	}
+
+	errf := c.editableTest(ctx, userID, testID)
+	if errf != nil {
+		return errf
+	}
+
+	// the drawn questions of the rule are deleted with it
+	_, err = c.queries.DeleteDrawRule(ctx, sqlc.DeleteDrawRuleParams{
+		RuleID: ruleID,
+		TestID: testID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The draw rule does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete draw rule : " + err.Error(),
+		}
+	}
+
+	return c.drawRulesChanged(ctx, testID)
+}
+
+// the pool of a test is copied from the bank when its rules change, this picks up later edits of the bank
+func (c *CompanyService) RefreshDrawPool(ctx *gin.Context, userID int64, testid string) (*errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	errf := c.editableTest(ctx, userID, testID)
+	if errf != nil {
+		return errf
+	}
+
+	return c.drawRulesChanged(ctx, testID)
+}
+
+// validateDrawRule checks a draw rule before it is stored and returns its difficulty and section as they are stored
+func (c *CompanyService) validateDrawRule(ctx *gin.Context, data *dto.DrawRuleData) (pgtype.Text, pgtype.Int8, *errs.Error) {
+
+	if data.Count < 1 {
+		return pgtype.Text{}, pgtype.Int8{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "A draw rule must draw at least 1 question.",
+			ToRespondWith: true,
+		}
+	}
+	if data.Points < 0 || data.Position < 0 {
+		return pgtype.Text{}, pgtype.Int8{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Points and position cannot be negative.",
+			ToRespondWith: true,
+		}
+	}
+
+	err := testforms.ValidateDifficulty(data.Difficulty)
+	if err != nil {
+		return pgtype.Text{}, pgtype.Int8{}, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: err.Error(),
+			ToRespondWith: true,
+		}
+	}
+	data.Tags = testforms.Tags(data.Tags)
+
+	sectionID, errf := c.questionSection(ctx, data.TestID, data.SectionID)
+	if errf != nil {
+		return pgtype.Text{}, pgtype.Int8{}, errf
+	}
+
+	return pgtype.Text{String: data.Difficulty, Valid: data.Difficulty != ""}, sectionID, nil
+}
+
+// drawRulesChanged copies the pools of the test's draw rules from the bank again, then syncs the test like any question edit
+func (c *CompanyService) drawRulesChanged(ctx *gin.Context, testID int64) (*errs.Error) {
+
+	err := c.queries.SyncDrawPool(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to draw questions from the bank : " + err.Error(),
+		}
+	}
+
+	return c.questionsChanged(ctx, testID)
+}
+
+func (c *CompanyService) ScheduledData(ctx *gin.Context, userID int64, eventtype string) (*dto.Upcoming, *errs.Error) {
+	// switch between event types
+	switch eventtype {
+	case "interviews":
+		uInts, err := c.queries.ScheduledInterviewsCompany(ctx, userID)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
 				Message: err.Error(),
 			}
 		}
@@ -993,9 +2940,28 @@ func (c *CompanyService) EditCutOff(ctx *gin.Context, userID int64, newData *dto
 		}
 	}
 
-	// lets try the new/refactored version
+	// the draft is generated again with the new cutoff only if the test has ended with no attempts in progress,
+	// and its attempts have been compared for similar answers.
+	// Until then only the cutoff is stored, the poller generates the first draft with it
+	state, err := c.queries.TestAuthorization(ctx, sqlc.TestAuthorizationParams{
+		TestID: newData.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to check test state : " + err.Error(),
+		}
+	}
+	if !state.SimilarityChecked {
+		return nil
+	}
+
 	go func() {
-		testresgen.GenerateCumulativeTestResult(c.queries, c.GAPIService, newData.TestID)
+		testresgen.GenerateCumulativeTestResult(c.queries, c.Tests, newData.TestID, config.ResultReasonCutoff)
 	} ()
 
 
@@ -1004,6 +2970,151 @@ func (c *CompanyService) EditCutOff(ctx *gin.Context, userID int64, newData *dto
 	return nil
 }
 
+func (c *CompanyService) EditMarkingScheme(ctx *gin.Context, userID int64, newData *dto.UpdateMarkingScheme) (*errs.Error) {
+
+	minScore, errf := markingScheme(newData.NegativeMarking, newData.MinScore)
+	if errf != nil {
+		return errf
+	}
+
+	// the test has to belong to the user before anything about it is told
+	_, err := c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: newData.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	published, err := c.queries.IsTestPublished(ctx, newData.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: err.Error(),
+		}
+	}
+	if published {
+		return &errs.Error{
+			Type: errs.CheckViolation,
+			Message: "This test is already published. Cannot edit now.",
+			ToRespondWith: true,
+		}
+	}
+
+	rows, err := c.queries.UpdateMarkingScheme(ctx, sqlc.UpdateMarkingSchemeParams{
+		TestID: newData.TestID,
+		UserID: userID,
+		NegativeMarking: int32(newData.NegativeMarking),
+		PartialCredit: newData.PartialCredit,
+		MinScore: minScore,
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update marking scheme : " + err.Error(),
+		}
+	}
+	if rows == 0 {
+		return &errs.Error{
+			Type: errs.Unauthorized,
+			Message: "You are not authorized to edit this test.",
+			ToRespondWith: true,
+		}
+	}
+
+	// the responses are marked again with the new scheme only if the test has ended with no attempts in progress,
+	// and its attempts have been compared for similar answers.
+	// Until then the poller generates the first draft, with the new scheme
+	state, err := c.queries.TestAuthorization(ctx, sqlc.TestAuthorizationParams{
+		TestID: newData.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to check test state : " + err.Error(),
+		}
+	}
+	if !state.SimilarityChecked {
+		return nil
+	}
+
+	go func() {
+		testresgen.GenerateCumulativeTestResult(c.queries, c.Tests, newData.TestID, config.ResultReasonMarkingScheme)
+	} ()
+
+	return nil
+}
+
+// testWindow validates the window a test is open in and returns the late entry limit as it is stored.
+// The test opens when it is created if it has no start time.
+func testWindow(newtestData *dto.NewTestPost) (pgtype.Int8, *errs.Error) {
+
+	if newtestData.StartDateTime.IsZero() {
+		newtestData.StartDateTime = time.Now()
+	}
+	if !newtestData.EndDateTime.After(newtestData.StartDateTime) {
+		return pgtype.Int8{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "The test must end after it starts.",
+			ToRespondWith: true,
+		}
+	}
+	if newtestData.GracePeriod < 0 {
+		return pgtype.Int8{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Grace period cannot be negative.",
+			ToRespondWith: true,
+		}
+	}
+	if newtestData.LateEntry == nil {
+		return pgtype.Int8{Valid: false}, nil
+	}
+	if *newtestData.LateEntry <= 0 {
+		return pgtype.Int8{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Late entry limit must be at least 1 minute.",
+			ToRespondWith: true,
+		}
+	}
+	return pgtype.Int8{Int64: *newtestData.LateEntry, Valid: true}, nil
+}
+
+// markingScheme validates the marking scheme of a test and returns the min score as it is stored
+func markingScheme(negativeMarking int64, minScore *int64) (pgtype.Int4, *errs.Error) {
+
+	if negativeMarking < 0 || negativeMarking > 100 {
+		return pgtype.Int4{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Negative marking must be between 0 and 100 percent of the question's points.",
+			ToRespondWith: true,
+		}
+	}
+	if minScore == nil {
+		return pgtype.Int4{Valid: false}, nil
+	}
+	if *minScore > 0 {
+		return pgtype.Int4{}, &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "Minimum score cannot be more than 0.",
+			ToRespondWith: true,
+		}
+	}
+	return pgtype.Int4{Int32: int32(*minScore), Valid: true}, nil
+}
 
 func (c *CompanyService) PublishTestResults(ctx *gin.Context, userID int64, testid string) (*errs.Error) {
 
@@ -1042,12 +3153,30 @@ func (c *CompanyService) PublishTestResults(ctx *gin.Context, userID int64, test
 		}
 	}
 
+	// the individual results need the final scores
+	pending, err := c.queries.PendingGradingCount(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: err.Error(),
+		}
+	}
+	if pending > 0 {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: fmt.Sprintf("%d responses are still waiting for manual grading. Grade them before publishing.", pending),
+			ToRespondWith: true,
+		}
+	}
+
+	// the request is over by the time the results are published, the copy of its context is safe to use after it
+	bgCtx := ctx.Copy()
 	go func() {
-		err = testresgen.PublishTestResults(c.queries, c.GAPIService, testID)
+		err = testresgen.PublishTestResults(c.queries, c.Tests, testID)
 		if err != nil {
 			fmt.Println(err)
 		} else {
-			err := c.queries.UpdateTest(ctx, sqlc.UpdateTestParams{
+			err := c.queries.UpdateTest(bgCtx, sqlc.UpdateTestParams{
 				TestID: testID,
 				UserID: userID,
 				Threshold: pgtype.Int4{Valid: false},
@@ -1057,6 +3186,13 @@ func (c *CompanyService) PublishTestResults(ctx *gin.Context, userID int64, test
 				fmt.Println(err)
 			}
 			fmt.Printf("Test results for %d have been published.", testID)
+			// the applications are moved by the cutoff if the test has automatic shortlisting on
+			errf := c.shortlistByCutoff(bgCtx, testID)
+			if errf != nil {
+				ctxutils.NewError(&dto.ErrorData{
+					Critical: fmt.Sprintf("Failed to shortlist applications for test ID : %d : %v", testID, errf.Message),
+				})
+			}
 		}
 	} ()
 
@@ -1064,6 +3200,183 @@ func (c *CompanyService) PublishTestResults(ctx *gin.Context, userID int64, test
 }
 
 
+func (c *CompanyService) GradingQueue(ctx *gin.Context, userID int64, testid string) (*[]dto.UngradedResponse, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	// the test has to belong to the user and has to have ended
+	_, err = c.queries.TestAuthorization(ctx, sqlc.TestAuthorizationParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test has not ended yet.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to authorize test : " + err.Error(),
+		}
+	}
+
+	queue, err := c.queries.GradingQueue(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get grading queue : " + err.Error(),
+		}
+	}
+
+	ungraded := []dto.UngradedResponse{}
+	if len(queue) == 0 {
+		return &ungraded, nil
+	}
+
+	// the question titles are not stored with the responses, get them from the test itself
+	testData, err := c.queries.TestData(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+	form, err := testforms.Questions(ctx, c.queries, c.Tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test questions : " + err.Error(),
+		}
+	}
+	titles := map[string]string{}
+	for _, item := range form.Items {
+		titles[item.ItemId] = item.Title
+	}
+
+	for _, q := range queue {
+		ungraded = append(ungraded, dto.UngradedResponse{
+			GradingQueueRow: q,
+			QuestionTitle: titles[q.QuestionID],
+		})
+	}
+
+	return &ungraded, nil
+}
+
+func (c *CompanyService) GradeResponse(ctx *gin.Context, userID int64, data *dto.GradeResponse) (*errs.Error) {
+
+	_, err := c.queries.TestAuthorization(ctx, sqlc.TestAuthorizationParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test has not ended yet.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to authorize test : " + err.Error(),
+		}
+	}
+
+	published, err := c.queries.IsTestPublished(ctx, data.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: err.Error(),
+		}
+	}
+	if published {
+		return &errs.Error{
+			Type: errs.CheckViolation,
+			Message: "This test is already published. Responses cannot be graded now.",
+			ToRespondWith: true,
+		}
+	}
+
+	response, err := c.queries.ResponseForGrading(ctx, sqlc.ResponseForGradingParams{
+		ResponseID: data.ResponseID,
+		TestID: data.TestID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The response does not exist in this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get response : " + err.Error(),
+		}
+	}
+	if !response.NeedsGrading {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "This response is evaluated automatically and cannot be graded manually.",
+			ToRespondWith: true,
+		}
+	}
+	if data.Points < 0 || data.Points > response.MaxPoints.Int32 {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: fmt.Sprintf("Points must be between 0 and %d.", response.MaxPoints.Int32),
+			ToRespondWith: true,
+		}
+	}
+
+	err = c.queries.GradeResponse(ctx, sqlc.GradeResponseParams{
+		ResponseID: data.ResponseID,
+		Points: pgtype.Int4{Int32: data.Points, Valid: true},
+		GraderComment: pgtype.Text{String: data.Comment, Valid: data.Comment != ""},
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to grade response : " + err.Error(),
+		}
+	}
+
+	err = c.queries.RecomputeTestScores(ctx, data.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to recompute test scores : " + err.Error(),
+		}
+	}
+
+	// the cumulative result waits for the grading, generate it once the last response is graded
+	pending, err := c.queries.PendingGradingCount(ctx, data.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get pending grading count : " + err.Error(),
+		}
+	}
+	if pending == 0 {
+		go func() {
+			testresgen.GenerateCumulativeTestResult(c.queries, c.Tests, data.TestID, config.ResultReasonGraded)
+		} ()
+	}
+
+	return nil
+}
+
 func (s *CompanyService) ProfileData(ctx *gin.Context, userID int64) (*dto.CompanyProfileData, *errs.Error) {
 	
 
@@ -1080,9 +3393,24 @@ func (s *CompanyService) ProfileData(ctx *gin.Context, userID int64) (*dto.Compa
 			Message: err.Error(),
 		}
 	}
+	stageCounts, err := s.queries.ApplicantStageCounts(ctx, userID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: err.Error(),
+		}
+	}
+	stages := make(map[int64][]gocharts.StageCount)
+	for _, sc := range stageCounts {
+		stages[sc.JobID] = append(stages[sc.JobID], gocharts.StageCount{
+			Name: sc.Name,
+			Reached: sc.Reached,
+			Rejected: sc.RejectedCount,
+		})
+	}
 	var sankeyCharts []*charts.Sankey
 	for _, o := range overData {
-		sankeyChrt := gocharts.SankeyApplicants(&o)
+		sankeyChrt := gocharts.SankeyApplicants(&o, stages[o.JobID])
 		sankeyCharts = append(sankeyCharts, sankeyChrt)
 	}
 
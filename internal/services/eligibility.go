@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+// the eligibility of a job is checked against the student's profile when applying, and when the jobs are listed
+// an empty list of a criterion allows anyone, the values are compared without case
+
+type jobCriteria struct {
+	MinCgpa pgtype.Float8
+	Departments []string
+	Courses []string
+	Years []string
+	Genders []string
+}
+
+// ineligibility returns the reasons the student does not meet the criteria of a job, none if eligible
+func ineligibility(job jobCriteria, student sqlc.StudentEligibilityRow) []string {
+
+	reasons := []string{}
+	if job.MinCgpa.Valid {
+		if !student.Cgpa.Valid {
+			reasons = append(reasons, fmt.Sprintf("A minimum CGPA of %.2f is required, your CGPA is not on your profile.", job.MinCgpa.Float64))
+		} else if student.Cgpa.Float64 < job.MinCgpa.Float64 {
+			reasons = append(reasons, fmt.Sprintf("A minimum CGPA of %.2f is required, yours is %.2f.", job.MinCgpa.Float64, student.Cgpa.Float64))
+		}
+	}
+	if !allowed(job.Departments, student.Department) {
+		reasons = append(reasons, "Open only to the departments : " + strings.Join(job.Departments, ", ") + ".")
+	}
+	if !allowed(job.Courses, student.Course) {
+		reasons = append(reasons, "Open only to the courses : " + strings.Join(job.Courses, ", ") + ".")
+	}
+	if !allowed(job.Years, student.YearOfStudy) {
+		reasons = append(reasons, "Open only to the years of study : " + strings.Join(job.Years, ", ") + ".")
+	}
+	if config.JobGenderCriteriaAllowed && !allowed(job.Genders, student.Gender) {
+		reasons = append(reasons, "Open only to : " + strings.Join(job.Genders, ", ") + ".")
+	}
+
+	return reasons
+}
+
+func allowed(list []string, value string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(list, func(v string) bool {
+		return strings.EqualFold(v, strings.TrimSpace(value))
+	})
+}
+
+// eligibilityCriteria validates the eligibility of a job posting and returns it in the form stored on the job
+func eligibilityCriteria(jobdata *dto.NewJobData) (jobCriteria, *errs.Error) {
+
+	criteria := jobCriteria{
+		Departments: splitList(jobdata.EligibleDepartments),
+		Courses: splitList(jobdata.EligibleCourses),
+		Years: splitList(jobdata.EligibleYears),
+		Genders: splitList(jobdata.EligibleGenders),
+	}
+	if jobdata.MinCGPA < 0 || jobdata.MinCGPA > 10 {
+		return criteria, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "The minimum CGPA must be between 0 and 10.",
+			ToRespondWith: true,
+		}
+	}
+	if jobdata.MinCGPA > 0 {
+		criteria.MinCgpa = pgtype.Float8{Float64: jobdata.MinCGPA, Valid: true}
+	}
+	if len(criteria.Genders) > 0 && !config.JobGenderCriteriaAllowed {
+		return criteria, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Jobs cannot be restricted by gender.",
+			ToRespondWith: true,
+		}
+	}
+
+	return criteria, nil
+}
+
+// splitList splits a comma separated list, dropping the empty values
+func splitList(s string) []string {
+	list := []string{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
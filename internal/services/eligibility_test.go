@@ -0,0 +1,62 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+func TestIneligibility(t *testing.T) {
+
+	student := sqlc.StudentEligibilityRow{
+		Cgpa: pgtype.Float8{Float64: 7.5, Valid: true},
+		Department: "CSE",
+		Course: "BTech",
+		YearOfStudy: "4",
+		Gender: "Female",
+	}
+	noCgpa := student
+	noCgpa.Cgpa = pgtype.Float8{}
+
+	tests := []struct {
+		name string
+		job jobCriteria
+		student sqlc.StudentEligibilityRow
+		// a part of every reason, in order
+		reasons []string
+	}{
+		{"no criteria", jobCriteria{}, student, nil},
+		{"meets every criterion", jobCriteria{
+			MinCgpa: pgtype.Float8{Float64: 7.5, Valid: true},
+			Departments: []string{"ECE", "CSE"},
+			Courses: []string{"BTech"},
+			Years: []string{"3", "4"},
+			Genders: []string{"Female"},
+		}, student, nil},
+		{"compared without case", jobCriteria{Departments: []string{"cse"}, Courses: []string{"btech"}}, student, nil},
+		{"cgpa below the minimum", jobCriteria{MinCgpa: pgtype.Float8{Float64: 8, Valid: true}}, student, []string{"A minimum CGPA of 8.00 is required, yours is 7.50."}},
+		{"cgpa not on the profile", jobCriteria{MinCgpa: pgtype.Float8{Float64: 6, Valid: true}}, noCgpa, []string{"your CGPA is not on your profile"}},
+		{"every other criterion", jobCriteria{
+			Departments: []string{"ECE"},
+			Courses: []string{"MTech"},
+			Years: []string{"1", "2"},
+			Genders: []string{"Male"},
+		}, student, []string{"departments : ECE.", "courses : MTech.", "years of study : 1, 2.", "Open only to : Male."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ineligibility(tt.job, tt.student)
+			if len(got) != len(tt.reasons) {
+				t.Fatalf("got reasons %q, want %q", got, tt.reasons)
+			}
+			for i := range tt.reasons {
+				if !strings.Contains(got[i], tt.reasons[i]) {
+					t.Errorf("reason %d : got %q, want it to contain %q", i, got[i], tt.reasons[i])
+				}
+			}
+		})
+	}
+}
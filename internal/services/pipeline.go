@@ -0,0 +1,318 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+// a job can define the ordered stages of its hiring pipeline, e.g. Aptitude Test, GD, Technical 1, Technical 2, HR
+// the stages are run while an application is shortlisted, one stage at a time and in order,
+// a job with stages can only offer the applications that have reached its last stage
+// a rejected application keeps the stage it was rejected at
+
+// the names of the other nodes of the applicants' sankey chart, a stage cannot take them
+var reservedStageNames = []string{"total applicants", "reviewed", "shortlisted", "rejected", "offered", "hired"}
+
+// SetJobStages replaces the stages of a job of the user, no stages removes its pipeline.
+// The stages cannot be changed once an application has entered one of them.
+func (c *CompanyService) SetJobStages(ctx *gin.Context, userID int64, data *dto.JobStages) (*[]sqlc.InsertJobStagesRow, *errs.Error) {
+
+	names := []string{}
+	for _, name := range data.Stages {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if len(name) > config.JobFieldMaxLength {
+			return nil, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("A stage name cannot be longer than %d characters.", config.JobFieldMaxLength),
+				ToRespondWith: true,
+			}
+		}
+		if slices.Contains(reservedStageNames, strings.ToLower(name)) {
+			return nil, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("%s is an application status, it cannot be a stage.", name),
+				ToRespondWith: true,
+			}
+		}
+		if slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+			return nil, &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("The stage %s is repeated.", name),
+				ToRespondWith: true,
+			}
+		}
+		names = append(names, name)
+	}
+	if len(names) > config.JobStagesMax {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: fmt.Sprintf("A job cannot have more than %d stages.", config.JobStagesMax),
+			ToRespondWith: true,
+		}
+	}
+
+	// the job row is locked while its stages are replaced, so two edits of the stages do not interleave
+	var stages []sqlc.InsertJobStagesRow
+	errf := withTx(ctx, c.queries, func(qtx *sqlc.Queries) *errs.Error {
+		inProgress, err := qtx.JobStagesEditable(ctx, sqlc.JobStagesEditableParams{
+			JobID: data.JobID,
+			UserID: userID,
+		})
+		if err != nil {
+			if err.Error() == errs.NoRowsMatch {
+				return &errs.Error{
+					Type: errs.Unauthorized,
+					Message: "You are not allowed to alter this job, or it does not exist.",
+					ToRespondWith: true,
+				}
+			}
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to get job stages : " + err.Error(),
+			}
+		}
+		if inProgress > 0 {
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: fmt.Sprintf("%d applications are already in the pipeline of this job. Cannot change its stages now.", inProgress),
+				ToRespondWith: true,
+			}
+		}
+
+		err = qtx.DeleteJobStages(ctx, data.JobID)
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to delete job stages : " + err.Error(),
+			}
+		}
+		stages, err = qtx.InsertJobStages(ctx, sqlc.InsertJobStagesParams{
+			JobID: data.JobID,
+			Names: names,
+		})
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to insert job stages : " + err.Error(),
+			}
+		}
+		return nil
+	})
+	if errf != nil {
+		return nil, errf
+	}
+	if stages == nil {
+		stages = []sqlc.InsertJobStagesRow{}
+	}
+
+	return &stages, nil
+}
+
+// JobStages returns the stages of a job in order, anyone can see the pipeline of a job
+func (c *CompanyService) JobStages(ctx *gin.Context, jobid string) (*[]sqlc.JobStagesRow, *errs.Error) {
+
+	jobID, err := strconv.ParseInt(jobid, 10, 64)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid job id.",
+			ToRespondWith: true,
+		}
+	}
+
+	stages, err := c.queries.JobStages(ctx, jobID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get job stages : " + err.Error(),
+		}
+	}
+	if stages == nil {
+		stages = []sqlc.JobStagesRow{}
+	}
+
+	return &stages, nil
+}
+
+// AdvanceStage moves a shortlisted application to a stage of its job's pipeline, the stage has to be the next one after its current stage
+func (c *CompanyService) AdvanceStage(ctx *gin.Context, userID int64, applicationid string, stageid string) (*errs.Error) {
+
+	applicationID, err := strconv.ParseInt(applicationid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid application id.",
+			ToRespondWith: true,
+		}
+	}
+	stageID, err := strconv.ParseInt(stageid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid stage id.",
+			ToRespondWith: true,
+		}
+	}
+
+	// the job row is locked while the application moves, so its stages cannot be replaced in between
+	var stage sqlc.JobStagesRow
+	var stages []sqlc.JobStagesRow
+	var studentUserID int64
+	errf := withTx(ctx, c.queries, func(qtx *sqlc.Queries) *errs.Error {
+		_, err := qtx.LockApplicationJob(ctx, applicationID)
+		if err != nil {
+			if err.Error() == errs.NoRowsMatch {
+				return &errs.Error{
+					Type: errs.NotFound,
+					Message: "This application does not exist.",
+					ToRespondWith: true,
+				}
+			}
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to lock the job of the application : " + err.Error(),
+			}
+		}
+
+		var application *sqlc.ApplicationStageRow
+		var errf *errs.Error
+		application, stages, errf = c.applicationPipeline(ctx, qtx, userID, applicationID)
+		if errf != nil {
+			return errf
+		}
+		if application.Status != "ShortListed" {
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: fmt.Sprintf("The application is %s. Only shortlisted applications move through the stages.", application.Status),
+				ToRespondWith: true,
+			}
+		}
+		i := slices.IndexFunc(stages, func(s sqlc.JobStagesRow) bool { return s.StageID == stageID })
+		if i < 0 {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "This stage is not a part of the pipeline of the application's job.",
+				ToRespondWith: true,
+			}
+		}
+		stage = stages[i]
+		if stage.Position != application.Position + 1 {
+			current := "has not entered the pipeline"
+			if application.Position > 0 {
+				current = "is at " + stages[application.Position - 1].Name
+			}
+			next := "it has completed the last stage"
+			if int(application.Position) < len(stages) {
+				next = "the next stage is " + stages[application.Position].Name
+			}
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: fmt.Sprintf("Cannot move the application to %s. The application %s, %s.", stage.Name, current, next),
+				ToRespondWith: true,
+			}
+		}
+
+		// the stage is checked again with the update, it could have moved since
+		studentUserID, err = qtx.SetApplicationStage(ctx, sqlc.SetApplicationStageParams{
+			StageID: stage.StageID,
+			ApplicationID: applicationID,
+			CurrentStageID: application.StageID,
+		})
+		if err != nil {
+			if err.Error() == errs.NoRowsMatch {
+				return &errs.Error{
+					Type: errs.InvalidState,
+					Message: "The application has changed since, try again.",
+					ToRespondWith: true,
+				}
+			}
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to change application stage : " + err.Error(),
+			}
+		}
+		return nil
+	})
+	if errf != nil {
+		return errf
+	}
+
+	errf = c.Notify.NewNotification(ctx, studentUserID, &dto.NotificationData{
+		Title: "Application Progressed",
+		Description: fmt.Sprintf("Your application (ID: %d) has moved to %s (stage %d of %d).", applicationID, stage.Name, stage.Position, len(stages)),
+	})
+	if errf != nil {
+		return errf
+	}
+
+	return nil
+}
+
+// offerable checks that the application has reached the last stage of its job's pipeline, if the job has one
+func (c *CompanyService) offerable(ctx *gin.Context, userID int64, applicationID int64) (*errs.Error) {
+
+	application, stages, errf := c.applicationPipeline(ctx, c.queries, userID, applicationID)
+	if errf != nil {
+		return errf
+	}
+	if len(stages) > 0 && int(application.Position) < len(stages) {
+		remaining := []string{}
+		for _, s := range stages[application.Position:] {
+			remaining = append(remaining, s.Name)
+		}
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The application has not completed the pipeline of this job. Remaining stages : " + strings.Join(remaining, ", ") + ".",
+			ToRespondWith: true,
+		}
+	}
+
+	return nil
+}
+
+// applicationPipeline returns the application with its current stage and the stages of its job, the job has to belong to the user
+func (c *CompanyService) applicationPipeline(ctx *gin.Context, queries *sqlc.Queries, userID int64, applicationID int64) (*sqlc.ApplicationStageRow, []sqlc.JobStagesRow, *errs.Error) {
+
+	application, err := queries.ApplicationStage(ctx, applicationID)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil, nil, &errs.Error{
+				Type: errs.NotFound,
+				Message: "This application does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return nil, nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get application stage : " + err.Error(),
+		}
+	}
+	if application.UserID != userID {
+		return nil, nil, &errs.Error{
+			Type: errs.Unauthorized,
+			Message: "The given user ID is not authorized to access requested application.",
+			ToRespondWith: true,
+		}
+	}
+
+	stages, err := queries.JobStages(ctx, application.JobID)
+	if err != nil {
+		return nil, nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get job stages : " + err.Error(),
+		}
+	}
+
+	return &application, stages, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	errs "go.mod/internal/const"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+// the placement policy is set by the admins and checked when a student applies, when a company offers and when a student accepts an offer
+// a student can be blocked from further jobs after accepting an offer, except for one dream job that pays at least the dream salary,
+// can have only so many active applications, and is debarred after declining so many offers
+// a rule that is not set does not apply, no policy at all allows everything
+
+// the salary of a job is free text, its amount is the first number in it in rupees a year, the unit the dream salary is set in
+// the digits may be grouped by commas, e.g. 1200000 of "12,00,000" or "1,200,000",
+// and the number may be scaled by its unit, e.g. 1250000 of "12.5 LPA" or "₹12.5L", 12000000 of "1.2 cr", 40000 of "40k".
+// The unit of a range is the one after it, e.g. 300000 of "3-5 LPA", and a salary paid by the month is made yearly
+var salaryAmountRegex = regexp.MustCompile(`(?i)(\d+(?:,\d+)*(?:\.\d+)?)(?:\s*(?:-|to)\s*\d+(?:,\d+)*(?:\.\d+)?)?\s*(lpa|lakhs?|lacs?|l|crores?|cr|k)?\b`)
+
+var salaryMonthlyRegex = regexp.MustCompile(`(?i)month|\bp\.?m\b`)
+
+// the factor of every unit of a salary, to rupees
+var salaryUnits = map[string]float64{
+	"lpa": 1e5,
+	"lakh": 1e5,
+	"lakhs": 1e5,
+	"lac": 1e5,
+	"lacs": 1e5,
+	"l": 1e5,
+	"crore": 1e7,
+	"crores": 1e7,
+	"cr": 1e7,
+	"k": 1e3,
+}
+
+// salaryAmount returns the yearly amount of a salary in rupees, false if it has no number
+func salaryAmount(salary string) (float64, bool) {
+	match := salaryAmountRegex.FindStringSubmatch(salary)
+	if match == nil {
+		return 0, false
+	}
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	if factor, ok := salaryUnits[strings.ToLower(match[2])]; ok {
+		amount *= factor
+	}
+	if salaryMonthlyRegex.MatchString(salary) {
+		amount *= 12
+	}
+	return amount, true
+}
+
+// placementPolicy returns the current policy, the zero policy if the admins have not set one
+func placementPolicy(ctx context.Context, queries *sqlc.Queries) (sqlc.PlacementPolicyRow, *errs.Error) {
+
+	policy, err := queries.PlacementPolicy(ctx)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return sqlc.PlacementPolicyRow{}, nil
+		}
+		return sqlc.PlacementPolicyRow{}, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get placement policy : " + err.Error(),
+		}
+	}
+
+	return policy, nil
+}
+
+// placementRefusals checks the policy for a student and a job by its salary, returns the rules that refuse it, none if allowed.
+// The cap on active applications only applies to new applications.
+func placementRefusals(ctx context.Context, queries *sqlc.Queries, userID int64, salary string, newApplication bool) ([]string, *errs.Error) {
+
+	policy, errf := placementPolicy(ctx, queries)
+	if errf != nil {
+		return nil, errf
+	}
+	standing, err := queries.PlacementStanding(ctx, userID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get placement standing : " + err.Error(),
+		}
+	}
+
+	return policyRefusals(policy, standing, salary, newApplication), nil
+}
+
+// policyRefusals checks the rules of the policy against the standing of a student, see placementRefusals
+func policyRefusals(policy sqlc.PlacementPolicyRow, standing sqlc.PlacementStandingRow, salary string, newApplication bool) []string {
+
+	reasons := []string{}
+	if policy.DebarAfterDeclines.Valid && policy.DebarAfterDeclines.Int32 > 0 && standing.DeclinedOffers >= int64(policy.DebarAfterDeclines.Int32) {
+		reasons = append(reasons, fmt.Sprintf("%d offers have been declined, the placement policy debars a student after %d declined offers.", standing.DeclinedOffers, policy.DebarAfterDeclines.Int32))
+	}
+	if newApplication && policy.MaxActiveApplications.Valid && policy.MaxActiveApplications.Int32 > 0 && standing.ActiveApplications >= int64(policy.MaxActiveApplications.Int32) {
+		reasons = append(reasons, fmt.Sprintf("%d applications are active, the placement policy allows at most %d at a time.", standing.ActiveApplications, policy.MaxActiveApplications.Int32))
+	}
+	if policy.BlockAfterAccept && len(standing.AcceptedSalaries) > 0 {
+		if !policy.DreamMinSalary.Valid {
+			reasons = append(reasons, "An offer has already been accepted, the placement policy allows one offer per student.")
+		} else if slices.ContainsFunc(standing.AcceptedSalaries, func(s string) bool { return dreamJob(policy, s) }) {
+			reasons = append(reasons, "A dream offer has already been accepted, the placement policy allows no further offers.")
+		} else if !dreamJob(policy, salary) {
+			reasons = append(reasons, fmt.Sprintf("An offer has already been accepted, the placement policy only allows dream jobs, with a salary of at least %.0f rupees a year, after that.", policy.DreamMinSalary.Float64))
+		}
+	}
+
+	return reasons
+}
+
+// dreamJob checks if the salary of a job is in the dream bracket of the policy
+func dreamJob(policy sqlc.PlacementPolicyRow, salary string) bool {
+	if !policy.DreamMinSalary.Valid {
+		return false
+	}
+	amount, ok := salaryAmount(salary)
+	return ok && amount >= policy.DreamMinSalary.Float64
+}
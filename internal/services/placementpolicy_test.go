@@ -0,0 +1,100 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+func TestSalaryAmount(t *testing.T) {
+
+	tests := []struct {
+		salary string
+		amount float64
+		ok bool
+	}{
+		{"12.5 LPA", 1250000, true},
+		{"12 LPA", 1200000, true},
+		{"₹8.5L", 850000, true},
+		{"8.5 lakhs per annum", 850000, true},
+		{"10 Lacs", 1000000, true},
+		{"1.2 cr", 12000000, true},
+		{"1 Crore", 10000000, true},
+		{"1200000", 1200000, true},
+		{"12,00,000", 1200000, true},
+		{"1,200,000", 1200000, true},
+		{"1,200,000 per annum", 1200000, true},
+		{"INR 8,50,000.50", 850000.5, true},
+		{"3-5 LPA", 300000, true},
+		{"3 to 5 lpa", 300000, true},
+		{"Rs. 40000 per month", 480000, true},
+		{"40k/month", 480000, true},
+		{"25000 pm", 300000, true},
+		{"12 leaves", 12, true},
+		{"Negotiable", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.salary, func(t *testing.T) {
+			amount, ok := salaryAmount(tt.salary)
+			if amount != tt.amount || ok != tt.ok {
+				t.Errorf("got %g %v, want %g %v", amount, ok, tt.amount, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPolicyRefusals(t *testing.T) {
+
+	dreamPolicy := sqlc.PlacementPolicyRow{
+		BlockAfterAccept: true,
+		DreamMinSalary: pgtype.Float8{Float64: 1500000, Valid: true},
+	}
+
+	tests := []struct {
+		name string
+		policy sqlc.PlacementPolicyRow
+		standing sqlc.PlacementStandingRow
+		salary string
+		newApplication bool
+		// a part of every reason, in order
+		reasons []string
+	}{
+		{"no policy", sqlc.PlacementPolicyRow{}, sqlc.PlacementStandingRow{ActiveApplications: 20, DeclinedOffers: 5, AcceptedSalaries: []string{"10 LPA"}}, "12 LPA", true, nil},
+		{"debarred after declines", sqlc.PlacementPolicyRow{DebarAfterDeclines: pgtype.Int4{Int32: 2, Valid: true}}, sqlc.PlacementStandingRow{DeclinedOffers: 2}, "12 LPA", false, []string{"2 offers have been declined"}},
+		{"under the declines", sqlc.PlacementPolicyRow{DebarAfterDeclines: pgtype.Int4{Int32: 2, Valid: true}}, sqlc.PlacementStandingRow{DeclinedOffers: 1}, "12 LPA", false, nil},
+		{"too many active applications", sqlc.PlacementPolicyRow{MaxActiveApplications: pgtype.Int4{Int32: 3, Valid: true}}, sqlc.PlacementStandingRow{ActiveApplications: 3}, "12 LPA", true, []string{"3 applications are active"}},
+		{"the cap only applies to new applications", sqlc.PlacementPolicyRow{MaxActiveApplications: pgtype.Int4{Int32: 3, Valid: true}}, sqlc.PlacementStandingRow{ActiveApplications: 3}, "12 LPA", false, nil},
+		{"a rule at 0 does not apply", sqlc.PlacementPolicyRow{MaxActiveApplications: pgtype.Int4{Int32: 0, Valid: true}}, sqlc.PlacementStandingRow{ActiveApplications: 3}, "12 LPA", true, nil},
+		{"one offer per student", sqlc.PlacementPolicyRow{BlockAfterAccept: true}, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"6 LPA"}}, "20 LPA", false, []string{"allows one offer per student"}},
+		{"nothing accepted yet", sqlc.PlacementPolicyRow{BlockAfterAccept: true}, sqlc.PlacementStandingRow{}, "20 LPA", false, nil},
+		{"a dream job after an offer", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"6,00,000"}}, "18,00,000", false, nil},
+		{"not a dream job after an offer", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"6,00,000"}}, "9,00,000", false, []string{"only allows dream jobs"}},
+		{"a dream job in lakhs", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"6 LPA"}}, "₹18L", false, nil},
+		{"not a dream job in lakhs", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"6 LPA"}}, "12 LPA", false, []string{"at least 1500000 rupees a year"}},
+		{"a dream offer accepted in crores", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"1.2 cr"}}, "30 LPA", false, []string{"A dream offer has already been accepted"}},
+		{"a dream offer accepted", dreamPolicy, sqlc.PlacementStandingRow{AcceptedSalaries: []string{"20,00,000"}}, "30,00,000", false, []string{"A dream offer has already been accepted"}},
+		{"every rule", sqlc.PlacementPolicyRow{
+			BlockAfterAccept: true,
+			MaxActiveApplications: pgtype.Int4{Int32: 1, Valid: true},
+			DebarAfterDeclines: pgtype.Int4{Int32: 1, Valid: true},
+		}, sqlc.PlacementStandingRow{ActiveApplications: 1, DeclinedOffers: 1, AcceptedSalaries: []string{"6 LPA"}}, "6 LPA", true, []string{"declined", "active", "one offer per student"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policyRefusals(tt.policy, tt.standing, tt.salary, tt.newApplication)
+			if len(got) != len(tt.reasons) {
+				t.Fatalf("got reasons %q, want %q", got, tt.reasons)
+			}
+			for i := range tt.reasons {
+				if !strings.Contains(got[i], tt.reasons[i]) {
+					t.Errorf("reason %d : got %q, want it to contain %q", i, got[i], tt.reasons[i])
+				}
+			}
+		})
+	}
+}
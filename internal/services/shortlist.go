@@ -0,0 +1,292 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils"
+	"go.mod/internal/utils/ctxutils"
+)
+
+// a test can shortlist the applications of its job by the cutoff when its results are published
+// the applications still in review are moved, the ones who passed in the latest version of the result to ShortListed
+// and the rest, including the ones who did not attempt the test, to Rejected
+// the batch keeps the previous status of every application, it can be undone as long as the status was not changed since
+
+// AutoShortlist turns the automatic shortlisting of a test on or off, until its results are published
+func (c *CompanyService) AutoShortlist(ctx *gin.Context, userID int64, data *dto.AutoShortlist) *errs.Error {
+
+	_, err := c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	published, err := c.queries.IsTestPublished(ctx, data.TestID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: err.Error(),
+		}
+	}
+	if published {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "This test is already published. Cannot change the shortlisting now.",
+			ToRespondWith: true,
+		}
+	}
+
+	err = c.queries.SetAutoShortlist(ctx, sqlc.SetAutoShortlistParams{
+		TestID: data.TestID,
+		AutoShortlist: data.Enabled,
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update automatic shortlisting : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// ShortlistPreview returns the applications that the cutoff would move, by the latest version of the result
+func (c *CompanyService) ShortlistPreview(ctx *gin.Context, userID int64, testid string) (*dto.ShortlistPreview, *errs.Error) {
+
+	testID, errf := c.shortlistTest(ctx, userID, testid)
+	if errf != nil {
+		return nil, errf
+	}
+
+	enabled, err := c.queries.TestAutoShortlist(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get automatic shortlisting : " + err.Error(),
+		}
+	}
+	candidates, err := c.queries.ShortlistPreview(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get shortlist preview : " + err.Error(),
+		}
+	}
+
+	preview := &dto.ShortlistPreview{
+		Enabled: enabled,
+		Candidates: make([]dto.ShortlistCandidate, 0, len(candidates)),
+	}
+	for _, candidate := range candidates {
+		status := "Rejected"
+		if candidate.Passed {
+			status = "ShortListed"
+			preview.Shortlisted++
+		} else {
+			preview.Rejected++
+		}
+		preview.Candidates = append(preview.Candidates, dto.ShortlistCandidate{
+			ShortlistPreviewRow: candidate,
+			NewStatus: status,
+		})
+	}
+
+	return preview, nil
+}
+
+// UndoShortlist moves the applications of the latest batch of a test back to their previous status.
+// The applications whose status was changed after the batch are left as they are, returns the number of applications moved back.
+func (c *CompanyService) UndoShortlist(ctx *gin.Context, userID int64, testid string) (int, *errs.Error) {
+
+	testID, errf := c.shortlistTest(ctx, userID, testid)
+	if errf != nil {
+		return 0, errf
+	}
+
+	batch, err := c.queries.LatestShortlistBatch(ctx, testID)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return 0, &errs.Error{
+				Type: errs.NotFound,
+				Message: "The applications of this test have not been shortlisted automatically.",
+				ToRespondWith: true,
+			}
+		}
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get shortlist batch : " + err.Error(),
+		}
+	}
+	if batch.UndoneAt.Valid {
+		return 0, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The shortlisting of this test has already been undone.",
+			ToRespondWith: true,
+		}
+	}
+
+	undone, err := c.queries.UndoShortlistBatch(ctx, batch.BatchID)
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to undo shortlist batch : " + err.Error(),
+		}
+	}
+
+	for _, u := range undone {
+		errf := c.Notify.NewNotification(ctx, u.UserID, &dto.NotificationData{
+			Title: "Application Status Updated",
+			Description: fmt.Sprintf("The decision on your application (ID: %d) has been withdrawn, it is back to %s.", u.ApplicationID, u.Status),
+		})
+		if errf != nil {
+			// the status has already been reverted, a failed notification does not stop the others
+			ctxutils.NewError(&dto.ErrorData{
+				Critical: fmt.Sprintf("Failed to notify the undone status of application ID : %d : %v", u.ApplicationID, errf.Message),
+			})
+		}
+	}
+
+	return len(undone), nil
+}
+
+// shortlistByCutoff moves the applications of the test's job by its cutoff and notifies every student,
+// it is called once the results of the test are published, if the test has automatic shortlisting on.
+// Unlike ShortList, which only moves an application that is UnderReview, the cutoff also decides the Applied ones,
+// the candidates take the test before the company reviews them, so most of them are still Applied
+func (c *CompanyService) shortlistByCutoff(ctx *gin.Context, testID int64) *errs.Error {
+
+	enabled, err := c.queries.TestAutoShortlist(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get automatic shortlisting : " + err.Error(),
+		}
+	}
+	if !enabled {
+		return nil
+	}
+
+	// the batch and its changes are made together, a test has a single batch so a second call moves nothing
+	var changes []sqlc.ApplyShortlistBatchRow
+	errf := withTx(ctx, c.queries, func(qtx *sqlc.Queries) *errs.Error {
+		batchID, err := qtx.NewShortlistBatch(ctx, testID)
+		if err != nil {
+			if err.Error() == errs.NoRowsMatch {
+				return nil
+			}
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to create shortlist batch : " + err.Error(),
+			}
+		}
+		changes, err = qtx.ApplyShortlistBatch(ctx, sqlc.ApplyShortlistBatchParams{
+			TestID: testID,
+			BatchID: batchID,
+		})
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: "Failed to shortlist applications : " + err.Error(),
+			}
+		}
+		return nil
+	})
+	if errf != nil {
+		return errf
+	}
+
+	for _, change := range changes {
+		title := "Application Rejected"
+		description := fmt.Sprintf("Your application (ID: %d) has been Rejected.", change.ApplicationID)
+		if change.Status == "ShortListed" {
+			title = "Application Shortlisted"
+			description = fmt.Sprintf("Your application (ID: %d) has been shortlisted.", change.ApplicationID)
+		}
+
+		errf := c.Notify.NewNotification(ctx, change.UserID, &dto.NotificationData{
+			Title: title,
+			Description: description,
+		})
+		if errf != nil {
+			// the status has already changed, a failed notification does not stop the others
+			ctxutils.NewError(&dto.ErrorData{
+				Critical: fmt.Sprintf("Failed to notify the status of application ID : %d : %v", change.ApplicationID, errf.Message),
+			})
+		}
+
+		emailData := struct {
+			StudentName string
+			CompanyName string
+			JobTitle string
+			ApplicationID int64
+			Status string
+		} {
+			change.StudentName,
+			change.CompanyName,
+			change.Title,
+			change.ApplicationID,
+			change.Status,
+		}
+		template, err := utils.DynamicHTML("./template/emails/applicationStatus.html", emailData)
+		if err != nil {
+			// the status has already changed, a failed email does not stop the others
+			ctxutils.NewError(&dto.ErrorData{
+				Critical: fmt.Sprintf("Failed to generate application status email for application ID : %d : %v", change.ApplicationID, err.Error()),
+			})
+			continue
+		}
+		go utils.SendEmailHTML(template, []string{change.StudentEmail})
+	}
+
+	return nil
+}
+
+// shortlistTest parses the test id and checks that the test belongs to the user
+func (c *CompanyService) shortlistTest(ctx *gin.Context, userID int64, testid string) (int64, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return 0, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = c.queries.EditableTestData(ctx, sqlc.EditableTestDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return 0, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return 0, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	return testID, nil
+}
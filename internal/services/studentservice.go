@@ -1,7 +1,6 @@
 package services
 
 import (
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -25,20 +24,21 @@ import (
 	"go.mod/internal/notify"
 	sqlc "go.mod/internal/sqlc/generate"
 	"go.mod/internal/utils"
+	"go.mod/internal/utils/testforms"
 	"google.golang.org/api/forms/v1"
 )
 
 type StudentService struct {
 	queries *sqlc.Queries
 	RedisClient *redis.Client
-	ApiCalls *apicalls.Caller
+	Tests apicalls.TestProvider
 	Notify *notify.Notify
 }
-func NewStudentService(queriespool *sqlc.Queries, redisclient *redis.Client, apicalls *apicalls.Caller, notifyService *notify.Notify) *StudentService {
+func NewStudentService(queriespool *sqlc.Queries, redisclient *redis.Client, testProvider apicalls.TestProvider, notifyService *notify.Notify) *StudentService {
 	return &StudentService{
 		queries: queriespool,
 		RedisClient: redisclient,
-		ApiCalls: apicalls,
+		Tests: testProvider,
 		Notify: notifyService,
 	}
 }
@@ -57,7 +57,9 @@ func (c *StudentService) DashboardData(ctx *gin.Context, userID int64) (*sqlc.St
 }
 
 
-func (s *StudentService) GetApplicableJobs(ctx *gin.Context, jobType string) (*[]sqlc.GetApplicableJobsTypeFilterRow, error) {
+// GetApplicableJobs lists the jobs the user has not applied to, each marked with whether the user is eligible for it.
+// The ineligible jobs are left out if eligibleOnly is set.
+func (s *StudentService) GetApplicableJobs(ctx *gin.Context, jobType string, eligibleOnly bool) (*[]dto.ApplicableJob, error) {
 
 	// TODO: apply all filters here
 	userID, exists := ctx.Get("ID")
@@ -72,25 +74,127 @@ func (s *StudentService) GetApplicableJobs(ctx *gin.Context, jobType string) (*[
 	if err != nil {
 		return nil, errors.New("unable to get all jobs from database")
 	}
+	student, err := s.queries.StudentEligibility(ctx, userID.(int64))
+	if err != nil {
+		return nil, errors.New("unable to get student profile from database")
+	}
+
+	jobs := make([]dto.ApplicableJob, 0, len(allapplicablejobsData))
+	for _, job := range allapplicablejobsData {
+		reasons := ineligibility(jobCriteria{
+			MinCgpa: job.MinCgpa,
+			Departments: job.EligibleDepartments,
+			Courses: job.EligibleCourses,
+			Years: job.EligibleYears,
+			Genders: job.EligibleGenders,
+		}, student)
+		if eligibleOnly && len(reasons) > 0 {
+			continue
+		}
+		jobs = append(jobs, dto.ApplicableJob{
+			GetApplicableJobsTypeFilterRow: job,
+			Eligible: len(reasons) == 0,
+			IneligibleReasons: reasons,
+		})
+	}
 
-	return &allapplicablejobsData, nil
+	return &jobs, nil
 }
 
-func (s *StudentService) NewApplication(ctx *gin.Context, userId int64, jobid string) (error) {
+// NewApplication applies the user to a job, if the job is open, its deadline has not passed,
+// the user meets its eligibility and the placement policy allows it
+func (s *StudentService) NewApplication(ctx *gin.Context, userId int64, jobid string) (*errs.Error) {
 
 	jobID, err := strconv.ParseInt(jobid, 10, 64)
 	if err != nil {
-		return errors.New("unable to parse job id from string to int")
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid job id.",
+			ToRespondWith: true,
+		}
 	}
 
-	err = s.queries.InsertNewApplication(ctx, sqlc.InsertNewApplicationParams{
+	job, err := s.queries.JobEligibility(ctx, jobID)
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "This job does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get job eligibility : " + err.Error(),
+		}
+	}
+	if !job.ActiveStatus {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "This job is no longer accepting applications.",
+			ToRespondWith: true,
+		}
+	}
+	if job.ApplicationDeadline.Valid && !job.ApplicationDeadline.Time.After(time.Now()) {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The application deadline for this job has passed.",
+			ToRespondWith: true,
+		}
+	}
+	student, err := s.queries.StudentEligibility(ctx, userId)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get student profile : " + err.Error(),
+		}
+	}
+	reasons := ineligibility(jobCriteria{
+		MinCgpa: job.MinCgpa,
+		Departments: job.EligibleDepartments,
+		Courses: job.EligibleCourses,
+		Years: job.EligibleYears,
+		Genders: job.EligibleGenders,
+	}, student)
+	if len(reasons) > 0 {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "You are not eligible for this job. " + strings.Join(reasons, " "),
+			ToRespondWith: true,
+		}
+	}
+
+	refusals, errf := placementRefusals(ctx, s.queries, userId, job.Salary, true)
+	if errf != nil {
+		return errf
+	}
+	if len(refusals) > 0 {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: "The placement policy does not allow this application. " + strings.Join(refusals, " "),
+			ToRespondWith: true,
+		}
+	}
+
+	// the job is checked again with the insert, it could have closed since
+	inserted, err := s.queries.InsertNewApplication(ctx, sqlc.InsertNewApplicationParams{
 		JobID: jobID,
 		UserID: userId,
 		DataUrl: pgtype.Text{String: "", Valid: true},
 	})
 	if err != nil {
 		fmt.Println(err)
-		return errors.New("unable to insert new application into database")
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Unable to insert new application into database : " + err.Error(),
+		}
+	}
+	if inserted == 0 {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The application deadline for this job has passed.",
+			ToRespondWith: true,
+		}
 	}
 
 	return nil
@@ -114,6 +218,84 @@ func (s *StudentService) CancelApplication(ctx *gin.Context, userID int64, jobid
 	return nil
 }
 
+// RespondToOffer accepts or declines the offer of a job the user applied to, accepting is checked against the placement policy.
+// An accepted offer moves the application to Hired, a declined one to Rejected, the company is notified either way.
+func (s *StudentService) RespondToOffer(ctx *gin.Context, userID int64, jobid string, accept bool) (*errs.Error) {
+
+	jobID, err := strconv.ParseInt(jobid, 10, 64)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid job id.",
+			ToRespondWith: true,
+		}
+	}
+
+	offered, err := s.queries.OfferedApplication(ctx, sqlc.OfferedApplicationParams{
+		JobID: jobID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "You have no pending offer for this job.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get offered application : " + err.Error(),
+		}
+	}
+
+	if accept {
+		refusals, errf := placementRefusals(ctx, s.queries, userID, offered.Salary, false)
+		if errf != nil {
+			return errf
+		}
+		if len(refusals) > 0 {
+			return &errs.Error{
+				Type: errs.PreconditionFailed,
+				Message: "The placement policy does not allow accepting this offer. " + strings.Join(refusals, " "),
+				ToRespondWith: true,
+			}
+		}
+	}
+
+	responded, err := s.queries.RespondToOffer(ctx, sqlc.RespondToOfferParams{
+		Accept: accept,
+		ApplicationID: offered.ApplicationID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: "The offer has changed since, try again.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to respond to offer : " + err.Error(),
+		}
+	}
+
+	title, response := "Offer Declined", "declined"
+	if accept {
+		title, response = "Offer Accepted", "accepted"
+	}
+	errf := s.Notify.NewNotification(ctx, responded.UserID, &dto.NotificationData{
+		Title: title,
+		Description: fmt.Sprintf("The offer for %s (application ID: %d) has been %s.", responded.Title, responded.ApplicationID, response),
+	})
+	if errf != nil {
+		return errf
+	}
+
+	return nil
+}
+
 func (s *StudentService) MyApplications(ctx *gin.Context, userId any, status string) (*[]sqlc.GetMyApplicationsStatusFilterRow, error) {
 
 	applicationsData, err := s.queries.GetMyApplicationsStatusFilter(ctx, sqlc.GetMyApplicationsStatusFilterParams{
@@ -201,12 +383,22 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 		}
 	}
 	// compares the returned test end_time with time.Now() and returns error if end_time was in the past
-	if testData.EndTime.Time.Compare(time.Now()) == -1 {
+	// the attempts in progress can still be continued for the grace period after the test closes
+	// an override for the user may move the end time and adds its extra time to the grace period
+	closes, extra := testCloses(&testData)
+	grace := time.Duration(testData.GracePeriod) * time.Minute + extra
+	if closes.Add(grace).Compare(time.Now()) == -1 {
 		return nil, &errs.Error{
 			Type: errs.Unauthorized,
 			Message: "The end time for the test has gone by. You cannot give the test now.",
 		}
 	}
+	if time.Now().Before(testData.StartTime.Time) {
+		return nil, &errs.Error{
+			Type: errs.Unauthorized,
+			Message: fmt.Sprintf("The test has not started yet. It opens at %s.", testData.StartTime.Time.Local().Format("03:04 PM 02-01-2006")),
+		}
+	}
 
 	
 
@@ -216,82 +408,12 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 	itemIdExpire := fmt.Sprintf("%sexpire%d", testid, userID)
 
 	// the user is authenticated and has not completed the test yet
-	// so check if the test data exists in cache
-	exists, err := s.RedisClient.Exists(ctx, itemIdData).Result()
+	// the test content is cached once for all the users, it is usually warmed up when the test is published
+	err = cacheTestContent(ctx, s.queries, s.Tests, s.RedisClient, testID, testData.UploadMethod, testData.FileID)
 	if err != nil {
 		return nil, &errs.Error{
 			Type: errs.Internal,
-			Message: fmt.Sprintf("failed to check cache for test data existence : %v", err),
-		}
-	} else if exists == 0 {
-		// the test data does not exist in cache
-		// call the test api to get complete form data
-		gForm, err := s.ApiCalls.GetCompleteForm(testData.FileID)
-		if err != nil {
-			return nil, &errs.Error{
-				Type: errs.Internal,
-				Message: fmt.Sprintf("failed during API calls : %v", err),
-			}
-		}
-		// loop over every item in the response
-		for _, b := range gForm.Items {
-			// check if the test has any media like images
-			// if yes, get them and convert to []byte then encode in base64
-			// set the image attribute back to this media file
-			var attr *forms.Image
-			if (b.ImageItem != nil || (b.QuestionItem != nil && b.QuestionItem.Image != nil)) {
-				switch {
-				case b.ImageItem != nil:
-					attr = b.ImageItem.Image
-				case b.QuestionItem != nil && b.QuestionItem.Image != nil:
-					attr = b.QuestionItem.Image
-				}
-				// TODO: this should ideally be done concurrently, for a real test with even 30 images thats like 45s of buffering for the user
-				fileByte, err := utils.GetFileFromPath(attr.ContentUri , config.TempFileStorage)
-				if err != nil {
-					return nil, &errs.Error{
-						Type: errs.Internal,
-						Message: fmt.Sprintf("failed to get file from url : %v", err),
-					}
-				}
-				encodedImage := base64.StdEncoding.EncodeToString(fileByte)
-				attr.ContentUri = encodedImage
-			}
-
-			// the answers are also included in the response
-			// so remove them and set their fields to NULL
-			qItem := b.QuestionItem
-			if (qItem != nil && qItem.Question != nil && qItem.Question.Grading != nil) {
-				qItem.Question.Grading = nil
-			}
-
-			// a list of the items is maintained in cache too
-			// push the current items id to the list
-			err = s.RedisClient.RPush(ctx, itemIdOrder, b.ItemId).Err()
-			if err != nil {
-				return nil, &errs.Error{
-					Type: errs.Internal,
-					Message: fmt.Sprintf("failed to push itemid to cache list : %v", err),
-				}
-			}
-
-			// marshal the item
-			values, err := json.Marshal(b)
-			if err != nil {
-				return nil, &errs.Error{
-					Type: errs.Internal,
-					Message: fmt.Sprintf("failed to marshal item : %v", err),
-				}
-			}
-			// a hash is maintained for each test, key is testid and the fields are itemid
-			// set the marshalled in the hash
-			err = s.RedisClient.HSet(ctx, itemIdData, b.ItemId, values).Err()
-			if err != nil {
-				return nil, &errs.Error{
-					Type: errs.Internal,
-					Message: fmt.Sprintf("failed to set item in hash in cache : %v", err),
-				}
-			}
+			Message: fmt.Sprintf("failed to cache test data : %v", err),
 		}
 	}
 
@@ -307,11 +429,19 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 	if err != nil {
 		if err.Error() == errs.NoRowsMatch {
 			// the user has not yet given the test
+			// a new attempt can only be started while the test is open and before the late entry limit
+			if errf := entryClosed(&testData, time.Now()); errf != nil {
+				return nil, errf
+			}
 			// add a new entry with the now timestamp
 			err := s.queries.NewTestResult(ctx, sqlc.NewTestResultParams{
 				UserID: userID,
 				TestID: testID,
 				StartTime: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				// the overrides in effect are recorded with the attempt
+				ExtraMinutes: testData.ExtraMinutes,
+				OverrideEndTime: testData.OverrideEndTime,
+				FreshAttempt: testData.FreshAttempt,
 			})
 			if err != nil {
 				return nil, &errs.Error{
@@ -333,6 +463,12 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 			Message: "The user has already given the test.",
 		}	
 	}
+	// a change of the user's ip during the attempt is recorded as a proctoring event
+	if resultData.StartTime.Valid {
+		if errf := s.proctorIP(ctx, testid, userID, resultData.ResultID); errf != nil {
+			return nil, errf
+		}
+	}
 
 	// here, either the test data has been called and set in the cache or it was already present
 	// either way, we have the complete test data here and its order list 
@@ -344,6 +480,32 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 			Message: fmt.Sprintf("failed to get list of itemid : %v", err),
 		}
 	}
+	if len(keysArray) == 0 {
+		return nil, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The test does not have any questions yet.",
+		}
+	}
+
+	// every user gets their own draw of the questions from the question bank, seeded by the user id
+	keysArray, errf := drawnOrder(ctx, s.queries, s.RedisClient, testid, testID, testData.UploadMethod, keysArray, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10), "draw"))
+	if errf != nil {
+		return nil, errf
+	}
+
+	// every user gets their own order of the questions if the test is shuffled, seeded by the user id
+	if testData.Shuffle {
+		keysArray, errf = shuffledOrder(ctx, s.RedisClient, testid, keysArray, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10)))
+		if errf != nil {
+			return nil, errf
+		}
+	}
+
+	// the test may be split into sections, nil if it is not
+	sections, errf := s.sectionProgress(ctx, testid, testID, userID)
+	if errf != nil {
+		return nil, errf
+	}
 
 	var deserial *forms.Item 
 	var index int
@@ -352,6 +514,43 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 	if currentItemId != "cover" {
 		// update the responses in the db
 		if (response.ItemID != "") {
+			// the server decides when the attempt is over, not the timer on the frontend
+			// the deadline is the start time + duration or the test end time + grace period, whichever is earlier
+			// both are extended by the user's extra time, if any
+			// responses after it are rejected, the attempt is then finalized by the expired attempts poller
+			deadline := resultData.StartTime.Time.Add(time.Duration(testData.Duration) * time.Minute + extra)
+			if closes.Add(grace).Before(deadline) {
+				deadline = closes.Add(grace)
+			}
+			if !resultData.StartTime.Valid || time.Now().After(deadline.Add(config.TestResponseGracePeriod * time.Second)) {
+				return nil, &errs.Error{
+					Type: errs.InvalidState,
+					Message: "The time is up for the test. The response was not saved.",
+				}
+			}
+			// the response must be to a question of the current section, within the section's time
+			if sections != nil && (sections.items[response.ItemID] != sections.current || sections.expired(config.TestResponseGracePeriod * time.Second)) {
+				return nil, &errs.Error{
+					Type: errs.InvalidState,
+					Message: "The section of this question is closed. The response was not saved.",
+				}
+			}
+
+			// the options were served shuffled, the selected values are arranged back in the order of the options
+			if testData.Shuffle {
+				var answered *forms.Item
+				itemBytes, err := s.RedisClient.HGet(ctx, itemIdData, response.ItemID).Bytes()
+				if err != nil && err != redis.Nil {
+					return nil, &errs.Error{
+						Type: errs.Internal,
+						Message: fmt.Sprintf("failed to get item data from cache : %v", err),
+					}
+				}
+				if err == nil && json.Unmarshal(itemBytes, &answered) == nil {
+					response.Response = testforms.OptionOrder(answered, response.Response)
+				}
+			}
+
 			err = s.queries.UpdateResponse(ctx, sqlc.UpdateResponseParams{
 				ResultID: resultData.ResultID,
 				QuestionID: response.ItemID,
@@ -372,6 +571,22 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 			}
 		}
 	}
+	// the user can only move forward through the sections, the requested item may move them to the next section
+	if sections != nil {
+		// the cover resumes the test at the start of the current section
+		if currentItemId == "cover" {
+			for i, k := range keysArray {
+				if sections.items[k] >= sections.current {
+					index = i
+					break
+				}
+			}
+		}
+		index, errf = s.enterSection(ctx, testid, userID, sections, keysArray, index)
+		if errf != nil {
+			return nil, errf
+		}
+	}
 	// get the entire item data from cache in bytes
 	result, err := s.RedisClient.HGet(ctx, itemIdData, keysArray[index]).Bytes()
 	if err != nil {
@@ -388,6 +603,9 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 			Message: fmt.Sprintf("failed to unMarshal item : %v", err),
 		}
 	}
+	if testData.Shuffle {
+		testforms.ShuffleOptions(deserial, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10), deserial.ItemId))
+	}
 	// generate the struct to send as response
 	toSend := dto.TestQuestion{
 		Item: deserial,
@@ -399,6 +617,18 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 	if index + 1 < len(keysArray) {
 		toSend.NextId = keysArray[index + 1]
 	}
+	// the previous item is not sent if it is in a closed section
+	if sections != nil {
+		if toSend.PrevId != "" && sections.items[toSend.PrevId] != sections.current {
+			toSend.PrevId = ""
+		}
+		if int(sections.current) < len(sections.sections) {
+			toSend.SectionName = sections.sections[sections.current].Name
+		}
+		if deadline, ok := sections.deadline(); ok {
+			toSend.SectionTTL = time.Until(deadline) / 1e9
+		}
+	}
 
 
 
@@ -428,7 +658,9 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 		} else if (!resultData.StartTime.Valid) {
 			// start of the test
 			// create new timer with ttl set to duration from db
-			sec := (testData.Duration * 60) * int64(time.Second)	
+			// or the time left until the test closes and its grace period ends, whichever is shorter
+			sec := (testData.Duration * 60) * int64(time.Second) + int64(extra)
+			sec = min(sec, int64(time.Until(closes.Add(grace))))
 			err = s.RedisClient.SetEx(ctx, itemIdExpire, "", time.Duration(sec)).Err()
 			if err != nil {
 				return nil, &errs.Error{
@@ -454,6 +686,271 @@ func (s *StudentService) TakeTest(ctx *gin.Context, userID int64, testid string,
 	return &toSend, nil
 }
 
+// testSections is the progress of a user through the sections of a test
+// current is the index of the section the user is in, -1 before the first item is served
+type testSections struct {
+	sections []sqlc.ListTestSectionsRow
+	items map[string]int32
+	current int32
+	startedAt time.Time
+}
+
+// deadline returns the end of the current section, false if the section has no timer of its own
+func (t *testSections) deadline() (time.Time, bool) {
+	if t.current < 0 || int(t.current) >= len(t.sections) || !t.sections[t.current].Duration.Valid {
+		return time.Time{}, false
+	}
+	return t.startedAt.Add(time.Duration(t.sections[t.current].Duration.Int64) * time.Minute), true
+}
+
+// expired checks if the current section's time is up, with the grace period added
+func (t *testSections) expired(grace time.Duration) bool {
+	deadline, ok := t.deadline()
+	return ok && time.Now().After(deadline.Add(grace))
+}
+
+// testCloses returns when the test closes for the user and the extra time they are given.
+// An override with an end time (a make-up window) replaces the end time of the test for the user.
+func testCloses(testData *sqlc.TakeTestRow) (time.Time, time.Duration) {
+
+	extra := time.Duration(testData.ExtraMinutes) * time.Minute
+	if testData.OverrideEndTime.Valid {
+		return testData.OverrideEndTime.Time, extra
+	}
+	return testData.EndTime.Time, extra
+}
+
+// entryClosed checks if a new attempt of the test can be started at the time, nil if it can.
+// The test must not have closed and the attempt must start within the late entry limit after the test opens.
+// The late entry limit does not apply to a make-up window given by an override.
+func entryClosed(testData *sqlc.TakeTestRow, now time.Time) (*errs.Error) {
+
+	closes, _ := testCloses(testData)
+	if now.After(closes) {
+		return &errs.Error{
+			Type: errs.Unauthorized,
+			Message: "The test has closed. A new attempt cannot be started now.",
+		}
+	}
+	if testData.LateEntry.Valid && !testData.OverrideEndTime.Valid {
+		closes := testData.StartTime.Time.Add(time.Duration(testData.LateEntry.Int64) * time.Minute)
+		if now.After(closes) {
+			return &errs.Error{
+				Type: errs.Unauthorized,
+				Message: fmt.Sprintf("The entry to the test closed at %s, %d minutes after it opened.", closes.Local().Format("03:04 PM 02-01-2006"), testData.LateEntry.Int64),
+			}
+		}
+	}
+
+	return nil
+}
+
+// sectionProgress reads the sections of the test and the user's progress through them from the cache.
+// It returns nil if the test is not split into sections.
+// The progress is a hash with the index of every entered section as the field and the time it was entered as the value.
+func (s *StudentService) sectionProgress(ctx *gin.Context, testid string, testID int64, userID int64) (*testSections, *errs.Error) {
+
+	sections, err := s.queries.ListTestSections(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get test sections : %v", err),
+		}
+	}
+	if len(sections) == 0 {
+		return nil, nil
+	}
+
+	itemSections, errf := itemSections(ctx, s.RedisClient, testid)
+	if errf != nil {
+		return nil, errf
+	}
+	progress, err := s.RedisClient.HGetAll(ctx, fmt.Sprintf("%ssections%d", testid, userID)).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get section progress from cache : %v", err),
+		}
+	}
+
+	state := &testSections{
+		sections: sections,
+		items: itemSections,
+		current: -1,
+	}
+	for section, enteredAt := range progress {
+		sec, err := strconv.ParseInt(section, 10, 32)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("invalid section progress in cache : %v", err),
+			}
+		}
+		unix, err := strconv.ParseInt(enteredAt, 10, 64)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("invalid section progress in cache : %v", err),
+			}
+		}
+		if int32(sec) > state.current {
+			state.current = int32(sec)
+			state.startedAt = time.Unix(unix, 0)
+		}
+	}
+
+	return state, nil
+}
+
+// itemSections reads the section of every item of the test from the cache
+func itemSections(ctx *gin.Context, redisClient *redis.Client, testid string) (map[string]int32, *errs.Error) {
+
+	cached, err := redisClient.HGetAll(ctx, fmt.Sprintf("%ssections", testid)).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get item sections from cache : %v", err),
+		}
+	}
+
+	itemSections := make(map[string]int32, len(cached))
+	for itemID, section := range cached {
+		sec, err := strconv.ParseInt(section, 10, 32)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("invalid item section in cache : %v", err),
+			}
+		}
+		itemSections[itemID] = int32(sec)
+	}
+
+	return itemSections, nil
+}
+
+// drawnOrder returns the items served to the user, Count questions are drawn for every draw rule of the test.
+// The order is returned as it is if the test has no draw rules.
+func drawnOrder(ctx *gin.Context, queries *sqlc.Queries, redisClient *redis.Client, testid string, testID int64, uploadMethod string, keysArray []string, seed uint64) ([]string, *errs.Error) {
+
+	if uploadMethod != config.TestUploadManual && uploadMethod != config.TestUploadCSVJSON {
+		return keysArray, nil
+	}
+
+	rules, err := queries.ListDrawRules(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get draw rules : %v", err),
+		}
+	}
+	if len(rules) == 0 {
+		return keysArray, nil
+	}
+	counts := make(map[int64]int32, len(rules))
+	for _, r := range rules {
+		counts[r.RuleID] = r.Count
+	}
+
+	cached, err := redisClient.HGetAll(ctx, fmt.Sprintf("%srules", testid)).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get item draw rules from cache : %v", err),
+		}
+	}
+	itemRules := make(map[string]int64, len(cached))
+	for itemID, rule := range cached {
+		ruleID, err := strconv.ParseInt(rule, 10, 64)
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("invalid item draw rule in cache : %v", err),
+			}
+		}
+		itemRules[itemID] = ruleID
+	}
+
+	return testforms.DrawOrder(keysArray, itemRules, counts, seed), nil
+}
+
+// shuffledOrder returns the user's order of the items, the questions are shuffled within their sections
+func shuffledOrder(ctx *gin.Context, redisClient *redis.Client, testid string, keysArray []string, seed uint64) ([]string, *errs.Error) {
+
+	itemSections, errf := itemSections(ctx, redisClient, testid)
+	if errf != nil {
+		return nil, errf
+	}
+	questionIDs, err := redisClient.SMembers(ctx, fmt.Sprintf("%squestions", testid)).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get question set from cache : %v", err),
+		}
+	}
+	questions := make(map[string]bool, len(questionIDs))
+	for _, q := range questionIDs {
+		questions[q] = true
+	}
+
+	return testforms.ShuffleOrder(keysArray, itemSections, questions, seed), nil
+}
+
+// enterSection checks the requested item against the user's progress and returns the index of the item to serve.
+// A closed section cannot be requested again, if the current section's time is up the first item of the next section is served instead.
+// Entering a new section closes the previous ones and starts its timer.
+func (s *StudentService) enterSection(ctx *gin.Context, testid string, userID int64, state *testSections, keysArray []string, index int) (int, *errs.Error) {
+
+	section := state.items[keysArray[index]]
+	if section < state.current {
+		return 0, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "This section is closed. You cannot go back to it.",
+		}
+	}
+
+	if section == state.current && state.expired(0) {
+		next := -1
+		for i := index + 1; i < len(keysArray); i++ {
+			if state.items[keysArray[i]] > state.current {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			return 0, &errs.Error{
+				Type: errs.ObjectExists,
+				Message: "The time is up for the last section. The test will now be auto-submitted",
+			}
+		}
+		index = next
+		section = state.items[keysArray[next]]
+	}
+
+	if section > state.current {
+		now := time.Now()
+		itemIdProgress := fmt.Sprintf("%ssections%d", testid, userID)
+		err := s.RedisClient.HSet(ctx, itemIdProgress, section, now.Unix()).Err()
+		if err != nil {
+			return 0, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("failed to set section progress in cache : %v", err),
+			}
+		}
+		// the progress is only needed while the test can still be taken
+		err = s.RedisClient.Expire(ctx, itemIdProgress, config.TestSectionProgressTTL * time.Hour).Err()
+		if err != nil {
+			return 0, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("failed to set expiry of section progress : %v", err),
+			}
+		}
+		state.current = section
+		state.startedAt = now
+	}
+
+	return index, nil
+}
+
 func (s *StudentService) SubmitTest(ctx *gin.Context, userID int64, testid string) (*errs.Error) {
 	// parse test id from string to int64
 	testID, err := strconv.ParseInt(testid, 10, 64)
@@ -473,7 +970,7 @@ func (s *StudentService) SubmitTest(ctx *gin.Context, userID int64, testid strin
 		if (err.Error() == errs.NoRowsMatch) {
 			return &errs.Error{
 				Type: errs.InvalidState,
-				Message: "The user has not started the test yet.",
+				Message: "The user has not started the test yet or it has already been submitted.",
 			}
 		} else {
 			return &errs.Error{
@@ -491,6 +988,118 @@ func (s *StudentService) SubmitTest(ctx *gin.Context, userID int64, testid strin
 	return nil
 }
 
+func (s *StudentService) ProctorEvents(ctx *gin.Context, userID int64, data *dto.ProctorEvents) (*errs.Error) {
+
+	if len(data.Events) == 0 {
+		return &errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "No events to record.",
+			ToRespondWith: true,
+		}
+	}
+	if len(data.Events) > config.ProctorEventsPerRequest {
+		return &errs.Error{
+			Type: errs.PreconditionFailed,
+			Message: fmt.Sprintf("At most %d events can be recorded at once.", config.ProctorEventsPerRequest),
+			ToRespondWith: true,
+		}
+	}
+	for _, e := range data.Events {
+		switch e.Type {
+		case config.ProctorTabSwitch, config.ProctorFocusLoss, config.ProctorFullscreenExit, config.ProctorCopy, config.ProctorPaste:
+		default:
+			return &errs.Error{
+				Type: errs.InvalidFormat,
+				Message: fmt.Sprintf("Invalid event type '%s'.", e.Type),
+				ToRespondWith: true,
+			}
+		}
+	}
+
+	// the events are only recorded while the attempt is open
+	resultID, err := s.queries.OpenTestAttempt(ctx, sqlc.OpenTestAttemptParams{
+		TestID: data.TestID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.InvalidState,
+				Message: "There is no open attempt of this test. The events were not recorded.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get test attempt : %v", err),
+		}
+	}
+
+	errf := s.proctorIP(ctx, strconv.FormatInt(data.TestID, 10), userID, resultID)
+	if errf != nil {
+		return errf
+	}
+
+	for _, e := range data.Events {
+		detail := []rune(strings.TrimSpace(e.Detail))
+		if len(detail) > config.ProctorDetailMaxLength {
+			detail = detail[:config.ProctorDetailMaxLength]
+		}
+		err = s.queries.InsertProctorEvent(ctx, sqlc.InsertProctorEventParams{
+			ResultID: resultID,
+			EventType: e.Type,
+			Detail: pgtype.Text{String: string(detail), Valid: len(detail) > 0},
+			IpAddress: ctx.ClientIP(),
+		})
+		if err != nil {
+			return &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("failed to insert proctoring event : %v", err),
+			}
+		}
+	}
+
+	return nil
+}
+
+// proctorIP records a change of the user's ip during an attempt as a proctoring event, the last ip is kept in the cache
+func (s *StudentService) proctorIP(ctx *gin.Context, testid string, userID int64, resultID int64) (*errs.Error) {
+
+	ip := ctx.ClientIP()
+	last, err := s.RedisClient.SetArgs(ctx, fmt.Sprintf("%sip%d", testid, userID), ip, redis.SetArgs{
+		Get: true,
+		TTL: config.ProctorIPTTL * time.Hour,
+	}).Result()
+	if err == redis.Nil {
+		// the first request of the attempt
+		return nil
+	}
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to set last ip in cache : %v", err),
+		}
+	}
+	if last == ip {
+		return nil
+	}
+
+	err = s.queries.InsertProctorEvent(ctx, sqlc.InsertProctorEventParams{
+		ResultID: resultID,
+		EventType: config.ProctorIPChange,
+		Detail: pgtype.Text{String: fmt.Sprintf("%s -> %s", last, ip), Valid: true},
+		IpAddress: ip,
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to insert proctoring event : %v", err),
+		}
+	}
+
+	return nil
+}
+
 func (s *StudentService) Completed(ctx *gin.Context, userID int64, tab string) (*dto.Completed, error) {
 
 	switch tab {
@@ -546,9 +1155,22 @@ func (s *StudentService) ProfileData(ctx *gin.Context, userID int64) (*dto.Stude
 		return nil, err
 	}
 
+	roundCounts, err := s.queries.ApplicationRoundCounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rounds := make([]gocharts.StageCount, 0, len(roundCounts))
+	for _, r := range roundCounts {
+		rounds = append(rounds, gocharts.StageCount{
+			Name: fmt.Sprintf("Round %d", r.Position),
+			Reached: r.Reached,
+			Rejected: r.RejectedCount,
+		})
+	}
+
 	// let us try to send a 'Sankey' type graph
 	// TODO: this should also return an error
-	sankeyChrt := gocharts.SankeyApplications(&overData)
+	sankeyChrt := gocharts.SankeyApplications(&overData, rounds)
 	
 	return &dto.StudentProfileData{
 		OverData: &overData,
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils"
+	"go.mod/internal/utils/ctxutils"
+	"go.mod/internal/utils/testforms"
+	"google.golang.org/api/forms/v1"
+)
+
+// the content of a test (its items, their order, sections and draw rules) is cached once for all the users
+// it is warmed up in the background when the test is published and built on the first request if it is missing
+// only one request builds it at a time while the others wait for it, and it is written in one transaction
+// so a user never reads a partial cache when many students start the same test at once
+
+// warmTestContent caches the content of a test in the background, the errors are only reported
+func warmTestContent(queries *sqlc.Queries, tests apicalls.TestProvider, redisClient *redis.Client, testID int64, uploadMethod string, fileID string) {
+
+	go func() {
+		err := cacheTestContent(context.Background(), queries, tests, redisClient, testID, uploadMethod, fileID)
+		if err != nil {
+			ctxutils.NewError(&dto.ErrorData{
+				Critical: fmt.Sprintf("Failed to warm up the cache of test ID : %d : %v", testID, err),
+			})
+		}
+	}()
+}
+
+// cacheTestContent builds the cache of a test's content if it does not exist yet.
+// The keys are the same as the ones read in TakeTest, <testid>order, data, sections, questions and rules.
+func cacheTestContent(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, redisClient *redis.Client, testID int64, uploadMethod string, fileID string) error {
+
+	return cacheContent(ctx, queries, tests, redisClient, testID, uploadMethod, fileID, strconv.FormatInt(testID, 10), 0)
+}
+
+// previewCacheKey is the prefix of the cache of a test's preview before the test starts, apart from the students' cache
+func previewCacheKey(testid string) string {
+	return fmt.Sprintf("%spreview", testid)
+}
+
+// cachePreviewContent builds the cache of a test's content for its preview before the test starts.
+// The form can still be edited then, the content is cached apart from the students' cache and expires
+// so the preview shows the edits, the students' cache is never built from a form that may still change.
+func cachePreviewContent(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, redisClient *redis.Client, testID int64, uploadMethod string, fileID string) error {
+
+	return cacheContent(ctx, queries, tests, redisClient, testID, uploadMethod, fileID, previewCacheKey(strconv.FormatInt(testID, 10)), config.PreviewCacheTTL * time.Minute)
+}
+
+// cacheContent builds the cache of a test's content under the key prefix, the keys expire after the ttl if it is set
+func cacheContent(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, redisClient *redis.Client, testID int64, uploadMethod string, fileID string, prefix string, ttl time.Duration) error {
+
+	itemIdOrder := fmt.Sprintf("%sorder", prefix)
+	itemIdData := fmt.Sprintf("%sdata", prefix)
+	itemIdSections := fmt.Sprintf("%ssections", prefix)
+	itemIdQuestions := fmt.Sprintf("%squestions", prefix)
+	itemIdRules := fmt.Sprintf("%srules", prefix)
+	cacheLock := fmt.Sprintf("%slock", prefix)
+
+	// wait for the cache or take the lock to build it
+	deadline := time.Now().Add(config.TestCacheLockTimeout * time.Second)
+	for {
+		exists, err := redisClient.Exists(ctx, itemIdData).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check cache for test data existence : %v", err)
+		}
+		if exists > 0 {
+			return nil
+		}
+
+		locked, err := redisClient.SetNX(ctx, cacheLock, "", config.TestCacheLockTimeout * time.Second).Result()
+		if err != nil {
+			return fmt.Errorf("failed to lock the test cache : %v", err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the test data to be cached")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+	defer redisClient.Del(context.WithoutCancel(ctx), cacheLock)
+
+	// the cache may have been built between the check and the lock
+	exists, err := redisClient.Exists(ctx, itemIdData).Result()
+	if err != nil {
+		return fmt.Errorf("failed to check cache for test data existence : %v", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	// get the form data without the answers, from the test provider or from the db for tests built on the platform
+	gForm, err := testforms.Questions(ctx, queries, tests, testID, uploadMethod, fileID)
+	if err != nil {
+		return fmt.Errorf("failed during API calls : %v", err)
+	}
+	// a test built on the platform may not have any questions yet, only the page breaks of its sections, it is cached once it does
+	if !slices.ContainsFunc(gForm.Items, func(b *forms.Item) bool { return b.QuestionItem != nil }) {
+		return nil
+	}
+
+	// the images are sent inline as base64
+	err = cacheMedia(ctx, redisClient, gForm)
+	if err != nil {
+		return err
+	}
+
+	// the section of every item, split by the page breaks
+	itemSections := apicalls.SectionIndex(gForm)
+
+	order := make([]interface{}, 0, len(gForm.Items))
+	data := make(map[string]interface{}, len(gForm.Items))
+	sections := make(map[string]interface{}, len(gForm.Items))
+	questions := []interface{}{}
+	for _, b := range gForm.Items {
+		values, err := json.Marshal(b)
+		if err != nil {
+			return fmt.Errorf("failed to marshal item : %v", err)
+		}
+		order = append(order, b.ItemId)
+		data[b.ItemId] = values
+		sections[b.ItemId] = itemSections[b.ItemId]
+		// only the question items are moved when the order is shuffled
+		if b.QuestionItem != nil {
+			questions = append(questions, b.ItemId)
+		}
+	}
+
+	// the questions drawn from the question bank are cached with their draw rule
+	rules := map[string]interface{}{}
+	if uploadMethod == config.TestUploadManual || uploadMethod == config.TestUploadCSVJSON {
+		testQuestions, err := queries.GetTestQuestions(ctx, testID)
+		if err != nil {
+			return fmt.Errorf("failed to get test questions : %v", err)
+		}
+		for _, q := range testQuestions {
+			if q.RuleID.Valid {
+				rules[q.ItemID] = q.RuleID.Int64
+			}
+		}
+	}
+
+	_, err = redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, itemIdOrder, itemIdSections, itemIdQuestions, itemIdRules)
+		pipe.RPush(ctx, itemIdOrder, order...)
+		pipe.HSet(ctx, itemIdSections, sections)
+		if len(questions) > 0 {
+			pipe.SAdd(ctx, itemIdQuestions, questions...)
+		}
+		if len(rules) > 0 {
+			pipe.HSet(ctx, itemIdRules, rules)
+		}
+		// the data is checked for the existence of the cache, it is written last
+		pipe.HSet(ctx, itemIdData, data)
+		if ttl > 0 {
+			for _, key := range []string{itemIdOrder, itemIdSections, itemIdQuestions, itemIdRules, itemIdData} {
+				pipe.Expire(ctx, key, ttl)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write test data to cache : %v", err)
+	}
+
+	return nil
+}
+
+// cacheMedia sets the content of every image of the form as base64 in place of its content uri.
+// The images are downloaded in parallel into the media store,
+// an image downloaded from the same uri recently is read from the store instead.
+func cacheMedia(ctx context.Context, redisClient *redis.Client, gForm *forms.Form) error {
+
+	images := []*forms.Image{}
+	for _, b := range gForm.Items {
+		switch {
+		case b.ImageItem != nil && b.ImageItem.Image != nil:
+			images = append(images, b.ImageItem.Image)
+		case b.QuestionItem != nil && b.QuestionItem.Image != nil:
+			images = append(images, b.QuestionItem.Image)
+		}
+	}
+
+	toFetch := []*forms.Image{}
+	urls := []string{}
+	for _, image := range images {
+		hash, err := redisClient.Get(ctx, mediaKey(image.ContentUri)).Result()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to get media hash from cache : %v", err)
+		}
+		if err == nil {
+			content, err := utils.ReadMedia(hash, config.MediaStorageDir)
+			if err == nil {
+				image.ContentUri = base64.StdEncoding.EncodeToString(content)
+				continue
+			}
+		}
+		toFetch = append(toFetch, image)
+		urls = append(urls, image.ContentUri)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	hashes, contents, err := utils.StoreMediaAll(urls, config.MediaStorageDir, config.MediaFetchParallel)
+	if err != nil {
+		return fmt.Errorf("failed to get file from url : %v", err)
+	}
+	for i, image := range toFetch {
+		err = redisClient.Set(ctx, mediaKey(urls[i]), hashes[i], config.MediaURLTTL * time.Minute).Err()
+		if err != nil {
+			return fmt.Errorf("failed to set media hash in cache : %v", err)
+		}
+		image.ContentUri = base64.StdEncoding.EncodeToString(contents[i])
+	}
+
+	return nil
+}
+
+// mediaKey is the cache key of the hash of the media at a uri
+func mediaKey(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return "media" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+// the company of a test or an admin can override the test window for a student (extra time, a make-up end time or a fresh attempt)
+// the override is read in TakeTest for a new attempt and copied to the attempt, so the results show what was in effect
+// an attempt in progress is updated right away along with its timer in the cache
+// the results of the test are generated again if they were already generated, they cannot be changed once published
+
+// setTestOverride validates and saves the override of a student, the caller must have checked access to the test
+func setTestOverride(ctx context.Context, queries *sqlc.Queries, redisClient *redis.Client, grantedBy int64, data *dto.TestOverride) *errs.Error {
+
+	data.Reason = strings.TrimSpace(data.Reason)
+	switch {
+	case data.Reason == "":
+		return &errs.Error{
+			Type: errs.MissingRequiredField,
+			Message: "A reason is required for the override.",
+			ToRespondWith: true,
+		}
+	case len(data.Reason) > config.OverrideReasonMaxLength:
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: fmt.Sprintf("The reason cannot be longer than %d characters.", config.OverrideReasonMaxLength),
+			ToRespondWith: true,
+		}
+	case data.ExtraMinutes < 0 || data.ExtraMinutes > config.OverrideMaxExtraMinutes:
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: fmt.Sprintf("The extra time must be between 0 and %d minutes.", config.OverrideMaxExtraMinutes),
+			ToRespondWith: true,
+		}
+	case data.EndDateTime != nil && !data.EndDateTime.After(time.Now()):
+		return &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "The end time of the override must be in the future.",
+			ToRespondWith: true,
+		}
+	case data.ExtraMinutes == 0 && data.EndDateTime == nil && !data.FreshAttempt:
+		return &errs.Error{
+			Type: errs.IncompleteForm,
+			Message: "The override must give extra time, an end time or a fresh attempt.",
+			ToRespondWith: true,
+		}
+	}
+
+	target, err := queries.TestOverrideTarget(ctx, sqlc.TestOverrideTargetParams{
+		TestID: data.TestID,
+		StudentID: data.StudentID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The student has not applied to the job of this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get override target : " + err.Error(),
+		}
+	}
+	if target.Published {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The results of this test are already published. The overrides cannot be changed.",
+			ToRespondWith: true,
+		}
+	}
+	// the time of a submitted attempt cannot be changed, it can only be discarded
+	if target.AttemptEndTime.Valid && !data.FreshAttempt {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The student has already submitted the test. Only a fresh attempt can be given.",
+			ToRespondWith: true,
+		}
+	}
+
+	endTime := pgtype.Timestamptz{}
+	if data.EndDateTime != nil {
+		endTime = pgtype.Timestamptz{Time: *data.EndDateTime, Valid: true}
+	}
+	_, err = queries.UpsertTestOverride(ctx, sqlc.UpsertTestOverrideParams{
+		TestID: data.TestID,
+		UserID: target.UserID,
+		ExtraMinutes: data.ExtraMinutes,
+		EndTime: endTime,
+		FreshAttempt: data.FreshAttempt,
+		Reason: data.Reason,
+		GrantedBy: grantedBy,
+	})
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to save test override : " + err.Error(),
+		}
+	}
+
+	if target.ResultID.Valid {
+		var errf *errs.Error
+		if data.FreshAttempt {
+			errf = discardAttempt(ctx, queries, redisClient, data.TestID, target.UserID, target.ResultID.Int64)
+		} else {
+			errf = applyAttemptOverride(ctx, queries, redisClient, data.TestID, target.UserID, target.ResultID.Int64, data.ExtraMinutes, endTime)
+		}
+		if errf != nil {
+			return errf
+		}
+	}
+
+	return resetTestResults(ctx, queries, data.TestID)
+}
+
+// removeTestOverride deletes the override of a student, an attempt in progress goes back to the test's own window
+func removeTestOverride(ctx context.Context, queries *sqlc.Queries, redisClient *redis.Client, testID int64, studentID int64) *errs.Error {
+
+	target, err := queries.TestOverrideTarget(ctx, sqlc.TestOverrideTargetParams{
+		TestID: testID,
+		StudentID: studentID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The student has not applied to the job of this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get override target : " + err.Error(),
+		}
+	}
+	if target.Published {
+		return &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The results of this test are already published. The overrides cannot be changed.",
+			ToRespondWith: true,
+		}
+	}
+
+	_, err = queries.DeleteTestOverride(ctx, sqlc.DeleteTestOverrideParams{
+		TestID: testID,
+		StudentID: studentID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return &errs.Error{
+				Type: errs.NotFound,
+				Message: "The student does not have an override for this test.",
+				ToRespondWith: true,
+			}
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete test override : " + err.Error(),
+		}
+	}
+
+	if target.ResultID.Valid && !target.AttemptEndTime.Valid {
+		errf := applyAttemptOverride(ctx, queries, redisClient, testID, target.UserID, target.ResultID.Int64, 0, pgtype.Timestamptz{})
+		if errf != nil {
+			return errf
+		}
+	}
+
+	return resetTestResults(ctx, queries, testID)
+}
+
+// discardAttempt deletes the attempt of a user with its responses and proctoring events, and their progress in the cache
+func discardAttempt(ctx context.Context, queries *sqlc.Queries, redisClient *redis.Client, testID int64, userID int64, resultID int64) *errs.Error {
+
+	err := queries.DeleteTestAttempt(ctx, resultID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to delete test attempt : " + err.Error(),
+		}
+	}
+
+	err = redisClient.Del(ctx, fmt.Sprintf("%dexpire%d", testID, userID), fmt.Sprintf("%dsections%d", testID, userID), fmt.Sprintf("%dip%d", testID, userID)).Err()
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to clear cached attempt data : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// applyAttemptOverride sets the override on an attempt in progress and moves its timer to the new deadline.
+// Nothing is done if the attempt was submitted in the meantime.
+func applyAttemptOverride(ctx context.Context, queries *sqlc.Queries, redisClient *redis.Client, testID int64, userID int64, resultID int64, extraMinutes int32, endTime pgtype.Timestamptz) *errs.Error {
+
+	deadline, err := queries.ApplyAttemptOverride(ctx, sqlc.ApplyAttemptOverrideParams{
+		ResultID: resultID,
+		ExtraMinutes: extraMinutes,
+		OverrideEndTime: endTime,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return nil
+		}
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update test attempt : " + err.Error(),
+		}
+	}
+
+	itemIdExpire := fmt.Sprintf("%dexpire%d", testID, userID)
+	left := time.Until(deadline.Time)
+	if left <= 0 {
+		err = redisClient.Del(ctx, itemIdExpire).Err()
+	} else {
+		err = redisClient.SetEx(ctx, itemIdExpire, "", left).Err()
+	}
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to update timer for test : " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// resetTestResults clears the result of a test that is not published yet, so that it is generated again by the poller
+func resetTestResults(ctx context.Context, queries *sqlc.Queries, testID int64) *errs.Error {
+
+	err := queries.ResetTestResultURL(ctx, testID)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to reset test results : " + err.Error(),
+		}
+	}
+
+	return nil
+}
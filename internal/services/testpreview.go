@@ -0,0 +1,381 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils/testforms"
+	"google.golang.org/api/forms/v1"
+)
+
+// the company can preview its test as the students see it, from the same cache as TakeTest once the test starts
+// (before that from its own cache that expires, as the form can still change) with the images inlined
+// and no answers, the items are drawn and shuffled for the company's account as they would be for a student
+// the preview is also a dry run, the responses are kept in the cache instead of the testresults and can be scored
+// against the answer key, there is no timer or section that closes, the test can be previewed at any time
+// opening the cover starts a new dry run
+
+// TestPreview responds with the requested item of the test and saves the response to the previous one in the dry run
+func (c *CompanyService) TestPreview(ctx *gin.Context, userID int64, testid string, currentItemId string, response *dto.TestResponse) (*dto.TestQuestion, *errs.Error) {
+
+	testID, testData, errf := c.previewTest(ctx, userID, testid)
+	if errf != nil {
+		return nil, errf
+	}
+
+	cacheKey, keysArray, errf := c.previewOrder(ctx, testid, testID, userID, testData)
+	if errf != nil {
+		return nil, errf
+	}
+
+	itemIdData := fmt.Sprintf("%sdata", cacheKey)
+	itemIdDryRun := fmt.Sprintf("%sdryrun%d", testid, userID)
+	itemIdDryRunExpire := fmt.Sprintf("%sdryrunexpire%d", testid, userID)
+
+	index := 0
+	if currentItemId == "cover" {
+		// a new dry run, the timer only shows the time a student would have
+		_, err := c.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, itemIdDryRun)
+			pipe.SetEx(ctx, itemIdDryRunExpire, "", time.Duration(testData.Duration) * time.Minute)
+			return nil
+		})
+		if err != nil {
+			return nil, &errs.Error{
+				Type: errs.Internal,
+				Message: fmt.Sprintf("failed to start dry run : %v", err),
+			}
+		}
+	} else {
+		if response.ItemID != "" {
+			if !slices.Contains(keysArray, response.ItemID) {
+				return nil, &errs.Error{
+					Type: errs.NotFound,
+					Message: "The answered item is not a part of this test.",
+					ToRespondWith: true,
+				}
+			}
+
+			// the options were served shuffled, the selected values are arranged back in the order of the options
+			if testData.Shuffle {
+				var answered *forms.Item
+				itemBytes, err := c.RedisClient.HGet(ctx, itemIdData, response.ItemID).Bytes()
+				if err != nil && err != redis.Nil {
+					return nil, &errs.Error{
+						Type: errs.Internal,
+						Message: fmt.Sprintf("failed to get item data from cache : %v", err),
+					}
+				}
+				if err == nil && json.Unmarshal(itemBytes, &answered) == nil {
+					response.Response = testforms.OptionOrder(answered, response.Response)
+				}
+			}
+
+			values, err := json.Marshal(response)
+			if err != nil {
+				return nil, &errs.Error{
+					Type: errs.Internal,
+					Message: fmt.Sprintf("failed to marshal dry run response : %v", err),
+				}
+			}
+			_, err = c.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, itemIdDryRun, response.ItemID, values)
+				pipe.Expire(ctx, itemIdDryRun, config.DryRunTTL * time.Hour)
+				return nil
+			})
+			if err != nil {
+				return nil, &errs.Error{
+					Type: errs.Internal,
+					Message: fmt.Sprintf("failed to save dry run response : %v", err),
+				}
+			}
+		}
+
+		index = slices.Index(keysArray, currentItemId)
+		if index < 0 {
+			return nil, &errs.Error{
+				Type: errs.NotFound,
+				Message: "The item is not a part of this test.",
+				ToRespondWith: true,
+			}
+		}
+	}
+
+	result, err := c.RedisClient.HGet(ctx, itemIdData, keysArray[index]).Bytes()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get item data from cache : %v", err),
+		}
+	}
+	var deserial *forms.Item
+	err = json.Unmarshal(result, &deserial)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to unMarshal item : %v", err),
+		}
+	}
+	if testData.Shuffle {
+		testforms.ShuffleOptions(deserial, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10), deserial.ItemId))
+	}
+
+	toSend := dto.TestQuestion{
+		Item: deserial,
+	}
+	if index - 1 >= 0 {
+		toSend.PrevId = keysArray[index - 1]
+	}
+	if index + 1 < len(keysArray) {
+		toSend.NextId = keysArray[index + 1]
+	}
+
+	// the name of the item's section, the sections are not timed in the preview
+	sections, err := c.queries.ListTestSections(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get test sections : %v", err),
+		}
+	}
+	if len(sections) > 0 {
+		sectionOf, errf := itemSections(ctx, c.RedisClient, cacheKey)
+		if errf != nil {
+			return nil, errf
+		}
+		if section := sectionOf[deserial.ItemId]; int(section) < len(sections) {
+			toSend.SectionName = sections[section].Name
+		}
+	}
+
+	ttl, err := c.RedisClient.TTL(ctx, itemIdDryRunExpire).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get timer for dry run : %v", err),
+		}
+	}
+	if ttl > 0 {
+		toSend.TTL = ttl / 1e9
+	}
+
+	return &toSend, nil
+}
+
+// DryRunScore scores the company's dry run of its test by the answer key, the same as the evaluation of the responses would.
+// Only the questions drawn for the company's account are counted.
+func (c *CompanyService) DryRunScore(ctx *gin.Context, userID int64, testid string) (*dto.DryRunScore, *errs.Error) {
+
+	testID, previewData, errf := c.previewTest(ctx, userID, testid)
+	if errf != nil {
+		return nil, errf
+	}
+
+	_, keysArray, errf := c.previewOrder(ctx, testid, testID, userID, previewData)
+	if errf != nil {
+		return nil, errf
+	}
+
+	testData, err := c.queries.TestData(ctx, testID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+	answers, err := testforms.AnswerKey(ctx, c.queries, c.Tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get answer key : " + err.Error(),
+		}
+	}
+	answerKey := make(map[string]dto.TestAnswer, len(answers))
+	for _, a := range answers {
+		answerKey[a.QuestionID] = a
+	}
+	gForm, err := testforms.Questions(ctx, c.queries, c.Tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test questions : " + err.Error(),
+		}
+	}
+	titles := make(map[string]string, len(gForm.Items))
+	for _, item := range gForm.Items {
+		titles[item.ItemId] = item.Title
+	}
+
+	cached, err := c.RedisClient.HGetAll(ctx, fmt.Sprintf("%sdryrun%d", testid, userID)).Result()
+	if err != nil {
+		return nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get dry run responses : %v", err),
+		}
+	}
+
+	score := &dto.DryRunScore{
+		Questions: []dto.DryRunQuestion{},
+	}
+	for _, key := range keysArray {
+		answer, ok := answerKey[key]
+		if !ok {
+			continue
+		}
+		var response dto.TestResponse
+		if values, ok := cached[key]; ok {
+			err = json.Unmarshal([]byte(values), &response)
+			if err != nil {
+				return nil, &errs.Error{
+					Type: errs.Internal,
+					Message: fmt.Sprintf("failed to unMarshal dry run response : %v", err),
+				}
+			}
+		}
+
+		points, status := dryRunPoints(answer, response.Response, testData.PartialCredit, testData.NegativeMarking)
+		if status == "Pending" {
+			score.Pending++
+		}
+		score.Score += points
+		score.MaxScore += answer.Points
+		score.Questions = append(score.Questions, dto.DryRunQuestion{
+			QuestionID: key,
+			Title: titles[key],
+			Response: response.Response,
+			CorrectAnswer: answer.CorrectAnswer,
+			Points: points,
+			MaxPoints: answer.Points,
+			Status: status,
+		})
+	}
+	if testData.MinScore.Valid {
+		score.Score = max(score.Score, int64(testData.MinScore.Int32))
+	}
+	score.Cutoff = int64(float64(testData.Threshold) / float64(100) * float64(score.MaxScore))
+	score.Passed = score.Score >= score.Cutoff
+
+	return score, nil
+}
+
+// dryRunPoints marks a response the same as the evaluation, full points for the exact answer, partial credit on
+// multi-select questions if the test gives it, and the negative marking for a wrong answer
+func dryRunPoints(answer dto.TestAnswer, response []string, partialCredit bool, negativeMarking int32) (int64, string) {
+
+	switch {
+	case len(response) == 0:
+		return 0, "Unattempted"
+	case answer.Manual:
+		return 0, "Pending"
+	case len(answer.CorrectAnswer) == 0:
+		return 0, "Unmarked"
+	case slices.Equal(response, answer.CorrectAnswer):
+		return answer.Points, "Correct"
+	}
+
+	if partialCredit && answer.Multiple {
+		matched := 0
+		for _, r := range response {
+			if slices.Contains(answer.CorrectAnswer, r) {
+				matched++
+			}
+		}
+		partial := int64(math.Round(float64(answer.Points) * float64(matched - (len(response) - matched)) / float64(len(answer.CorrectAnswer))))
+		if partial > 0 {
+			return partial, "Partial"
+		}
+	}
+
+	return -int64(math.Round(float64(answer.Points) * float64(negativeMarking) / 100)), "Wrong"
+}
+
+// previewTest parses the test id and gets the test of the company's user
+func (c *CompanyService) previewTest(ctx *gin.Context, userID int64, testid string) (int64, *sqlc.TestPreviewDataRow, *errs.Error) {
+
+	testID, err := strconv.ParseInt(testid, 10, 64)
+	if err != nil {
+		return 0, nil, &errs.Error{
+			Type: errs.InvalidFormat,
+			Message: "Invalid test id.",
+			ToRespondWith: true,
+		}
+	}
+
+	testData, err := c.queries.TestPreviewData(ctx, sqlc.TestPreviewDataParams{
+		TestID: testID,
+		UserID: userID,
+	})
+	if err != nil {
+		if err.Error() == errs.NoRowsMatch {
+			return 0, nil, &errs.Error{
+				Type: errs.Unauthorized,
+				Message: "You are not authorized to operate on this Test. This test belongs to a different user. Or the test does not exist.",
+				ToRespondWith: true,
+			}
+		}
+		return 0, nil, &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to get test data : " + err.Error(),
+		}
+	}
+
+	return testID, &testData, nil
+}
+
+// previewOrder returns the items of the test in the order TakeTest would serve them to the user, and the prefix of the cache they are in.
+// Once the test starts the preview reads the students' cache, before that it has its own that expires, see cachePreviewContent
+func (c *CompanyService) previewOrder(ctx *gin.Context, testid string, testID int64, userID int64, testData *sqlc.TestPreviewDataRow) (string, []string, *errs.Error) {
+
+	cacheKey := testid
+	var err error
+	if testData.Started {
+		err = cacheTestContent(ctx, c.queries, c.Tests, c.RedisClient, testID, testData.UploadMethod, testData.FileID)
+	} else {
+		cacheKey = previewCacheKey(testid)
+		err = cachePreviewContent(ctx, c.queries, c.Tests, c.RedisClient, testID, testData.UploadMethod, testData.FileID)
+	}
+	if err != nil {
+		return "", nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to cache test data : %v", err),
+		}
+	}
+
+	keysArray, err := c.RedisClient.LRange(ctx, fmt.Sprintf("%sorder", cacheKey), 0, -1).Result()
+	if err != nil {
+		return "", nil, &errs.Error{
+			Type: errs.Internal,
+			Message: fmt.Sprintf("failed to get list of itemid : %v", err),
+		}
+	}
+	if len(keysArray) == 0 {
+		return "", nil, &errs.Error{
+			Type: errs.InvalidState,
+			Message: "The test does not have any questions yet.",
+			ToRespondWith: true,
+		}
+	}
+
+	keysArray, errf := drawnOrder(ctx, c.queries, c.RedisClient, cacheKey, testID, testData.UploadMethod, keysArray, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10), "draw"))
+	if errf != nil {
+		return "", nil, errf
+	}
+	if testData.Shuffle {
+		keysArray, errf = shuffledOrder(ctx, c.RedisClient, cacheKey, keysArray, testforms.Seed(testData.ShuffleSeed, strconv.FormatInt(userID, 10)))
+		if errf != nil {
+			return "", nil, errf
+		}
+	}
+
+	return cacheKey, keysArray, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"go.mod/internal/dto"
+)
+
+func TestDryRunPoints(t *testing.T) {
+
+	single := dto.TestAnswer{CorrectAnswer: []string{"b"}, Points: 4}
+	multiple := dto.TestAnswer{CorrectAnswer: []string{"a", "b", "c"}, Points: 6, Multiple: true}
+
+	tests := []struct {
+		name string
+		answer dto.TestAnswer
+		response []string
+		partialCredit bool
+		negativeMarking int32
+		points int64
+		status string
+	}{
+		{"unattempted", single, []string{}, false, 50, 0, "Unattempted"},
+		{"manual", dto.TestAnswer{Points: 5, Manual: true}, []string{"an essay"}, false, 0, 0, "Pending"},
+		{"no answer key", dto.TestAnswer{Points: 5}, []string{"a"}, false, 0, 0, "Unmarked"},
+		{"correct", single, []string{"b"}, false, 50, 4, "Correct"},
+		{"wrong", single, []string{"a"}, false, 0, 0, "Wrong"},
+		{"wrong with negative marking", single, []string{"a"}, false, 50, -2, "Wrong"},
+		{"all of a multiple", multiple, []string{"a", "b", "c"}, true, 0, 6, "Correct"},
+		{"partial credit", multiple, []string{"a", "b"}, true, 0, 4, "Partial"},
+		{"partial credit less the wrong options", multiple, []string{"a", "b", "d"}, true, 0, 2, "Partial"},
+		{"as many wrong as right is wrong", multiple, []string{"a", "d"}, true, 25, -2, "Wrong"},
+		{"no partial credit", multiple, []string{"a", "b"}, false, 0, 0, "Wrong"},
+		{"partial credit only on multiple", single, []string{"b", "c"}, true, 0, 0, "Wrong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, status := dryRunPoints(tt.answer, tt.response, tt.partialCredit, tt.negativeMarking)
+			if points != tt.points || status != tt.status {
+				t.Errorf("got %d %s, want %d %s", points, status, tt.points, tt.status)
+			}
+		})
+	}
+}
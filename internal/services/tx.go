@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"go.mod/internal/config"
+	errs "go.mod/internal/const"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+// withTx runs fn with the queries bound to a new transaction,
+// the transaction is committed if fn succeeds and rolled back otherwise
+func withTx(ctx context.Context, queries *sqlc.Queries, fn func(qtx *sqlc.Queries) (*errs.Error)) (*errs.Error) {
+
+	tx, err := config.Pool.Begin(ctx)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to begin transaction : " + err.Error(),
+		}
+	}
+	// a no-op once the transaction is committed
+	defer tx.Rollback(ctx)
+
+	errf := fn(queries.WithTx(tx))
+	if errf != nil {
+		return errf
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return &errs.Error{
+			Type: errs.Internal,
+			Message: "Failed to commit transaction : " + err.Error(),
+		}
+	}
+
+	return nil
+}
@@ -9,12 +9,27 @@ import (
 )
 
 type Application struct {
-	ApplicationID int64
-	JobID         int64
-	StudentID     int64
-	DataUrl       pgtype.Text
+	ApplicationID   int64
+	JobID           int64
+	StudentID       int64
+	DataUrl         pgtype.Text
+	CreatedAt       pgtype.Timestamptz
+	Status          interface{}
+	StageID         pgtype.Int8
+	OfferDeclinedAt pgtype.Timestamptz
+}
+
+type Bankquestion struct {
+	QuestionID    int64
+	CompanyID     int64
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Tags          []string
+	Difficulty    string
 	CreatedAt     pgtype.Timestamptz
-	Status        interface{}
 }
 
 type Company struct {
@@ -41,6 +56,12 @@ type Discussion struct {
 	Content   string
 }
 
+type Drivestate struct {
+	ID        bool
+	PageToken string
+	UpdatedAt pgtype.Timestamptz
+}
+
 type Feedback struct {
 	FeedbackID    int64
 	CreatedAt     pgtype.Timestamptz
@@ -64,19 +85,54 @@ type Interview struct {
 }
 
 type Job struct {
-	JobID        int64
-	DataUrl      pgtype.Text
-	CreatedAt    pgtype.Timestamp
-	CompanyID    int64
-	Title        string
-	Location     string
-	Type         string
-	Salary       string
-	Skills       []string
-	Position     string
-	Extras       []byte
-	ActiveStatus bool
-	Description  pgtype.Text
+	JobID               int64
+	DataUrl             pgtype.Text
+	CreatedAt           pgtype.Timestamp
+	CompanyID           int64
+	Title               string
+	Location            string
+	Type                string
+	Salary              string
+	Skills              []string
+	Position            string
+	Extras              []byte
+	ActiveStatus        bool
+	Description         pgtype.Text
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+	ClosedByDeadline    bool
+}
+
+type Jobstage struct {
+	StageID   int64
+	JobID     int64
+	Position  int32
+	Name      string
+	CreatedAt pgtype.Timestamptz
+}
+
+type Jobversion struct {
+	JobID               int64
+	Version             int32
+	Title               string
+	Location            string
+	Type                string
+	Salary              string
+	Skills              []string
+	Position            string
+	Description         pgtype.Text
+	Extras              []byte
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+	CreatedAt           pgtype.Timestamptz
 }
 
 type Notification struct {
@@ -88,6 +144,55 @@ type Notification struct {
 	Timestamp   int64
 }
 
+type Placementpolicy struct {
+	ID                    bool
+	BlockAfterAccept      bool
+	DreamMinSalary        pgtype.Float8
+	MaxActiveApplications pgtype.Int4
+	DebarAfterDeclines    pgtype.Int4
+	UpdatedAt             pgtype.Timestamptz
+}
+
+type Proctorevent struct {
+	EventID   int64
+	ResultID  int64
+	EventType string
+	Detail    pgtype.Text
+	IpAddress string
+	CreatedAt pgtype.Timestamptz
+}
+
+type Sharedform struct {
+	FormID       string
+	ResponderUri string
+	DiscoveredAt pgtype.Timestamptz
+}
+
+type Shortlistbatch struct {
+	BatchID   int64
+	TestID    int64
+	CreatedAt pgtype.Timestamptz
+	UndoneAt  pgtype.Timestamptz
+}
+
+type Shortlistchange struct {
+	BatchID        int64
+	ApplicationID  int64
+	PreviousStatus interface{}
+	NewStatus      interface{}
+}
+
+type Similarityflag struct {
+	FlagID          int64
+	TestID          int64
+	ResultA         int64
+	ResultB         int64
+	CommonQuestions int32
+	SharedWrong     int32
+	TimeDifference  pgtype.Float8
+	CreatedAt       pgtype.Timestamptz
+}
+
 type Student struct {
 	StudentID    int64
 	StudentName  string
@@ -110,46 +215,119 @@ type Student struct {
 }
 
 type TempCorrectAnswer struct {
+	TestID        int64
 	QuestionID    string
 	CorrectAnswer []string
 	Points        pgtype.Int4
+	Manual        bool
+	Multiple      bool
+	Section       int32
 }
 
 type Test struct {
+	TestID              int64
+	TestName            string
+	Description         pgtype.Text
+	Duration            int64
+	QCount              int64
+	EndTime             pgtype.Timestamptz
+	Type                string
+	UploadMethod        interface{}
+	JobID               pgtype.Int8
+	CompanyID           int64
+	FileID              string
+	ResultUrl           pgtype.Text
+	Threshold           int32
+	Published           bool
+	NegativeMarking     int32
+	PartialCredit       bool
+	MinScore            pgtype.Int4
+	Shuffle             bool
+	ShuffleSeed         int64
+	StartTime           pgtype.Timestamptz
+	LateEntry           pgtype.Int8
+	GracePeriod         int64
+	CreatedAt           pgtype.Timestamptz
+	SimilarityCheckedAt pgtype.Timestamptz
+	AutoShortlist       bool
+	AnnouncedAt         pgtype.Timestamptz
+}
+
+type Testdrawrule struct {
+	RuleID     int64
+	TestID     int64
+	Tags       []string
+	Difficulty pgtype.Text
+	Count      int32
+	Points     int32
+	Position   int32
+	SectionID  pgtype.Int8
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Testoverride struct {
+	OverrideID   int64
 	TestID       int64
-	TestName     string
-	Description  pgtype.Text
-	Duration     int64
-	QCount       int64
+	UserID       int64
+	ExtraMinutes int32
 	EndTime      pgtype.Timestamptz
-	Type         string
-	UploadMethod interface{}
-	JobID        pgtype.Int8
-	CompanyID    int64
-	FileID       string
-	ResultUrl    pgtype.Text
-	Threshold    int32
-	Published    bool
+	FreshAttempt bool
+	Reason       string
+	GrantedBy    int64
 	CreatedAt    pgtype.Timestamptz
 }
 
+type Testquestion struct {
+	QuestionID    int64
+	TestID        int64
+	ItemID        string
+	Position      int32
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Points        int32
+	CreatedAt     pgtype.Timestamptz
+	SectionID     pgtype.Int8
+	RuleID        pgtype.Int8
+}
+
 type Testresponse struct {
-	ResponseID int64
-	ResultID   int64
-	QuestionID string
-	Response   []string
-	TimeTaken  pgtype.Int8
-	Points     pgtype.Int4
-	CreatedAt  pgtype.Timestamptz
+	ResponseID    int64
+	ResultID      int64
+	QuestionID    string
+	Response      []string
+	TimeTaken     pgtype.Int8
+	Points        pgtype.Int4
+	CreatedAt     pgtype.Timestamptz
+	NeedsGrading  bool
+	MaxPoints     pgtype.Int4
+	GraderComment pgtype.Text
+	GradedAt      pgtype.Timestamptz
+	Section       int32
 }
 
 type Testresult struct {
-	ResultID  int64
+	ResultID        int64
+	TestID          int64
+	UserID          int64
+	StartTime       pgtype.Timestamptz
+	EndTime         pgtype.Timestamptz
+	Score           pgtype.Int8
+	ExtraMinutes    int32
+	OverrideEndTime pgtype.Timestamptz
+	FreshAttempt    bool
+}
+
+type Testsection struct {
+	SectionID int64
 	TestID    int64
-	UserID    int64
-	StartTime pgtype.Timestamptz
-	EndTime   pgtype.Timestamptz
-	Score     pgtype.Int8
+	Name      string
+	Position  int32
+	Duration  pgtype.Int8
+	MinScore  pgtype.Int4
+	CreatedAt pgtype.Timestamptz
 }
 
 type User struct {
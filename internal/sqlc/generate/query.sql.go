@@ -11,6 +11,117 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const announceTest = `-- name: AnnounceTest :one
+UPDATE tests
+SET 
+    announced_at = NOW()
+WHERE tests.test_id = $1
+AND tests.announced_at IS NULL
+AND (
+    tests.upload_method <> 'Manual'
+    OR EXISTS (SELECT 1 FROM testquestions WHERE testquestions.test_id = tests.test_id)
+)
+RETURNING 
+    tests.job_id,
+    tests.test_name,
+    tests.description,
+    tests.duration,
+    tests.q_count,
+    tests.type,
+    tests.start_time,
+    tests.end_time,
+    tests.late_entry,
+    tests.grace_period
+`
+
+type AnnounceTestRow struct {
+	JobID       pgtype.Int8
+	TestName    string
+	Description pgtype.Text
+	Duration    int64
+	QCount      int64
+	Type        string
+	StartTime   pgtype.Timestamptz
+	EndTime     pgtype.Timestamptz
+	LateEntry   pgtype.Int8
+	GracePeriod int64
+}
+
+func (q *Queries) AnnounceTest(ctx context.Context, testID int64) (AnnounceTestRow, error) {
+	row := q.db.QueryRow(ctx, announceTest, testID)
+	var i AnnounceTestRow
+	err := row.Scan(
+		&i.JobID,
+		&i.TestName,
+		&i.Description,
+		&i.Duration,
+		&i.QCount,
+		&i.Type,
+		&i.StartTime,
+		&i.EndTime,
+		&i.LateEntry,
+		&i.GracePeriod,
+	)
+	return i, err
+}
+
+const applicantStageCounts = `-- name: ApplicantStageCounts :many
+SELECT
+    jobstages.job_id,
+    jobstages.position,
+    jobstages.name,
+    CAST(COUNT(staged.application_id) AS BIGINT) AS reached,
+    CAST(COALESCE(SUM(CASE WHEN staged.status = 'Rejected' AND staged.position = jobstages.position THEN 1 END), 0) AS BIGINT) AS rejected_count
+FROM jobstages
+JOIN jobs ON jobs.job_id = jobstages.job_id
+LEFT JOIN (
+    SELECT 
+        applications.application_id,
+        applications.job_id,
+        applications.status,
+        js.position
+    FROM applications
+    JOIN jobstages AS js ON js.stage_id = applications.stage_id
+) AS staged ON staged.job_id = jobstages.job_id AND staged.position >= jobstages.position
+WHERE jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+GROUP BY jobstages.job_id, jobstages.position, jobstages.name
+ORDER BY jobstages.job_id, jobstages.position
+`
+
+type ApplicantStageCountsRow struct {
+	JobID         int64
+	Position      int32
+	Name          string
+	Reached       int64
+	RejectedCount int64
+}
+
+func (q *Queries) ApplicantStageCounts(ctx context.Context, userID int64) ([]ApplicantStageCountsRow, error) {
+	rows, err := q.db.Query(ctx, applicantStageCounts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApplicantStageCountsRow
+	for rows.Next() {
+		var i ApplicantStageCountsRow
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Position,
+			&i.Name,
+			&i.Reached,
+			&i.RejectedCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const applicantsCount = `-- name: ApplicantsCount :many
 WITH ji AS (
     SELECT
@@ -115,6 +226,107 @@ func (q *Queries) ApplicationHistory(ctx context.Context, userID int64) ([]Appli
 	return items, nil
 }
 
+const applicationPlacement = `-- name: ApplicationPlacement :one
+SELECT 
+    students.user_id,
+    jobs.salary
+FROM applications
+JOIN students ON students.student_id = applications.student_id
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.application_id = $1
+`
+
+type ApplicationPlacementRow struct {
+	UserID int64
+	Salary string
+}
+
+func (q *Queries) ApplicationPlacement(ctx context.Context, applicationID int64) (ApplicationPlacementRow, error) {
+	row := q.db.QueryRow(ctx, applicationPlacement, applicationID)
+	var i ApplicationPlacementRow
+	err := row.Scan(&i.UserID, &i.Salary)
+	return i, err
+}
+
+const applicationRoundCounts = `-- name: ApplicationRoundCounts :many
+SELECT
+    rounds.position::INT AS position,
+    CAST(COUNT(*) AS BIGINT) AS reached,
+    CAST(COALESCE(SUM(CASE WHEN staged.status = 'Rejected' AND staged.position = rounds.position THEN 1 END), 0) AS BIGINT) AS rejected_count
+FROM (
+    SELECT 
+        applications.status,
+        jobstages.position
+    FROM applications
+    JOIN jobstages ON jobstages.stage_id = applications.stage_id
+    WHERE applications.student_id = (SELECT students.student_id FROM students WHERE students.user_id = $1)
+) AS staged
+CROSS JOIN LATERAL generate_series(1, staged.position) AS rounds(position)
+GROUP BY rounds.position
+ORDER BY rounds.position
+`
+
+type ApplicationRoundCountsRow struct {
+	Position      int32
+	Reached       int64
+	RejectedCount int64
+}
+
+func (q *Queries) ApplicationRoundCounts(ctx context.Context, userID int64) ([]ApplicationRoundCountsRow, error) {
+	rows, err := q.db.Query(ctx, applicationRoundCounts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApplicationRoundCountsRow
+	for rows.Next() {
+		var i ApplicationRoundCountsRow
+		if err := rows.Scan(&i.Position, &i.Reached, &i.RejectedCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const applicationStage = `-- name: ApplicationStage :one
+SELECT 
+    applications.job_id,
+    companies.user_id,
+    applications.status::TEXT AS status,
+    applications.stage_id,
+    COALESCE(jobstages.position, 0) AS position
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+JOIN companies ON companies.company_id = jobs.company_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
+WHERE applications.application_id = $1
+`
+
+type ApplicationStageRow struct {
+	JobID    int64
+	UserID   int64
+	Status   string
+	StageID  pgtype.Int8
+	Position int32
+}
+
+func (q *Queries) ApplicationStage(ctx context.Context, applicationID int64) (ApplicationStageRow, error) {
+	row := q.db.QueryRow(ctx, applicationStage, applicationID)
+	var i ApplicationStageRow
+	err := row.Scan(
+		&i.JobID,
+		&i.UserID,
+		&i.Status,
+		&i.StageID,
+		&i.Position,
+	)
+	return i, err
+}
+
 const applicationStatusTo = `-- name: ApplicationStatusTo :one
 WITH upd AS (
     UPDATE applications
@@ -201,6 +413,179 @@ func (q *Queries) ApplicationsStatusCounts(ctx context.Context, userID int64) (A
 	return i, err
 }
 
+const applyAttemptOverride = `-- name: ApplyAttemptOverride :one
+UPDATE testresults
+SET 
+    extra_minutes = $2,
+    override_end_time = $3
+FROM tests
+WHERE testresults.test_id = tests.test_id
+AND testresults.result_id = $1
+AND testresults.end_time IS NULL
+RETURNING (LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute'))::TIMESTAMPTZ AS deadline
+`
+
+type ApplyAttemptOverrideParams struct {
+	ResultID        int64
+	ExtraMinutes    int32
+	OverrideEndTime pgtype.Timestamptz
+}
+
+func (q *Queries) ApplyAttemptOverride(ctx context.Context, arg ApplyAttemptOverrideParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, applyAttemptOverride, arg.ResultID, arg.ExtraMinutes, arg.OverrideEndTime)
+	var deadline pgtype.Timestamptz
+	err := row.Scan(&deadline)
+	return deadline, err
+}
+
+const applyMarkingScheme = `-- name: ApplyMarkingScheme :exec
+WITH scored AS (
+    SELECT 
+        testresponses.response_id,
+        temp_correct_answers.points,
+        CASE WHEN $1::BOOLEAN AND temp_correct_answers.multiple THEN
+            ROUND(temp_correct_answers.points * (
+                CARDINALITY(ARRAY(SELECT UNNEST(testresponses.response) INTERSECT SELECT UNNEST(temp_correct_answers.correct_answer)))
+                - CARDINALITY(ARRAY(SELECT UNNEST(testresponses.response) EXCEPT SELECT UNNEST(temp_correct_answers.correct_answer)))
+            )::NUMERIC / CARDINALITY(temp_correct_answers.correct_answer))::INT
+        ELSE 0 END AS partial
+    FROM testresponses
+    JOIN testresults ON testresponses.result_id = testresults.result_id
+    JOIN temp_correct_answers ON temp_correct_answers.test_id = testresults.test_id AND testresponses.question_id = temp_correct_answers.question_id
+    WHERE testresults.test_id = $2
+    AND temp_correct_answers.manual = false
+    AND CARDINALITY(temp_correct_answers.correct_answer) > 0
+    AND CARDINALITY(testresponses.response) > 0
+    AND testresponses.response <> temp_correct_answers.correct_answer
+)
+UPDATE testresponses
+SET 
+    points = CASE WHEN scored.partial > 0 THEN scored.partial
+        ELSE -ROUND(scored.points * $3::INT / 100.0)::INT END,
+    max_points = scored.points
+FROM scored
+WHERE testresponses.response_id = scored.response_id
+`
+
+type ApplyMarkingSchemeParams struct {
+	PartialCredit   bool
+	TestID          int64
+	NegativeMarking int32
+}
+
+func (q *Queries) ApplyMarkingScheme(ctx context.Context, arg ApplyMarkingSchemeParams) error {
+	_, err := q.db.Exec(ctx, applyMarkingScheme, arg.PartialCredit, arg.TestID, arg.NegativeMarking)
+	return err
+}
+
+const applyShortlistBatch = `-- name: ApplyShortlistBatch :many
+WITH latest AS (
+    SELECT 
+        resultversions.version_id
+    FROM resultversions
+    WHERE resultversions.test_id = $1
+    ORDER BY resultversions.version DESC
+    LIMIT 1
+),
+candidates AS (
+    SELECT 
+        applications.application_id,
+        applications.status AS previous_status,
+        (CASE WHEN COALESCE(resultversionscores.passed, false) THEN 'ShortListed' ELSE 'Rejected' END)::application_status AS new_status
+    FROM tests
+    JOIN applications ON applications.job_id = tests.job_id
+    JOIN students ON applications.student_id = students.student_id
+    LEFT JOIN resultversionscores ON resultversionscores.version_id = (SELECT latest.version_id FROM latest)
+        AND resultversionscores.user_id = students.user_id
+    WHERE tests.test_id = $1
+    AND applications.status IN ('Applied', 'UnderReview')
+    FOR UPDATE OF applications
+),
+changes AS (
+    INSERT INTO shortlistchanges (batch_id, application_id, previous_status, new_status)
+    SELECT $2, candidates.application_id, candidates.previous_status, candidates.new_status
+    FROM candidates
+    RETURNING shortlistchanges.application_id, shortlistchanges.new_status
+),
+upd AS (
+    UPDATE applications
+    SET status = changes.new_status
+    FROM changes
+    WHERE applications.application_id = changes.application_id
+    RETURNING applications.application_id, applications.student_id, applications.job_id, applications.status
+)
+SELECT 
+    upd.application_id,
+    upd.status::TEXT AS status,
+    students.user_id,
+    students.student_name,
+    students.student_email,
+    jobs.title,
+    companies.company_name
+FROM upd
+JOIN students ON upd.student_id = students.student_id
+JOIN jobs ON upd.job_id = jobs.job_id
+JOIN companies ON jobs.company_id = companies.company_id
+`
+
+type ApplyShortlistBatchParams struct {
+	TestID  int64
+	BatchID int64
+}
+
+type ApplyShortlistBatchRow struct {
+	ApplicationID int64
+	Status        string
+	UserID        int64
+	StudentName   string
+	StudentEmail  string
+	Title         string
+	CompanyName   string
+}
+
+func (q *Queries) ApplyShortlistBatch(ctx context.Context, arg ApplyShortlistBatchParams) ([]ApplyShortlistBatchRow, error) {
+	rows, err := q.db.Query(ctx, applyShortlistBatch, arg.TestID, arg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApplyShortlistBatchRow
+	for rows.Next() {
+		var i ApplyShortlistBatchRow
+		if err := rows.Scan(
+			&i.ApplicationID,
+			&i.Status,
+			&i.UserID,
+			&i.StudentName,
+			&i.StudentEmail,
+			&i.Title,
+			&i.CompanyName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const assignResponseSections = `-- name: AssignResponseSections :exec
+UPDATE testresponses
+SET section = temp_correct_answers.section
+FROM testresults, temp_correct_answers
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND temp_correct_answers.test_id = testresults.test_id
+AND testresponses.question_id = temp_correct_answers.question_id
+`
+
+func (q *Queries) AssignResponseSections(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, assignResponseSections, testID)
+	return err
+}
+
 const cancelApplication = `-- name: CancelApplication :exec
 DELETE FROM applications 
 WHERE student_id = (SELECT student_id FROM students WHERE students.user_id = $1) 
@@ -267,16 +652,87 @@ func (q *Queries) CancelInterviewEmailData(ctx context.Context, applicationID in
 
 const clearAnswersTable = `-- name: ClearAnswersTable :exec
 DELETE FROM temp_correct_answers
+WHERE temp_correct_answers.test_id = $1
+`
+
+func (q *Queries) ClearAnswersTable(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, clearAnswersTable, testID)
+	return err
+}
+
+const clearSimilarityFlags = `-- name: ClearSimilarityFlags :exec
+DELETE FROM similarityflags
+WHERE similarityflags.test_id = $1
 `
 
-func (q *Queries) ClearAnswersTable(ctx context.Context) error {
-	_, err := q.db.Exec(ctx, clearAnswersTable)
+func (q *Queries) ClearSimilarityFlags(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, clearSimilarityFlags, testID)
 	return err
 }
 
+const closeExpiredJobs = `-- name: CloseExpiredJobs :many
+WITH closed AS (
+    UPDATE jobs
+    SET active_status = false,
+        closed_by_deadline = true
+    WHERE active_status
+    AND application_deadline <= NOW()
+    RETURNING job_id, title, company_id, application_deadline
+)
+SELECT
+    closed.job_id,
+    closed.title,
+    closed.application_deadline,
+    companies.user_id,
+    companies.company_name,
+    companies.representative_email,
+    (SELECT COUNT(*) FROM applications WHERE applications.job_id = closed.job_id) AS applicants
+FROM closed
+JOIN companies ON companies.company_id = closed.company_id
+`
+
+type CloseExpiredJobsRow struct {
+	JobID               int64
+	Title               string
+	ApplicationDeadline pgtype.Timestamptz
+	UserID              int64
+	CompanyName         string
+	RepresentativeEmail string
+	Applicants          int64
+}
+
+func (q *Queries) CloseExpiredJobs(ctx context.Context) ([]CloseExpiredJobsRow, error) {
+	rows, err := q.db.Query(ctx, closeExpiredJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CloseExpiredJobsRow
+	for rows.Next() {
+		var i CloseExpiredJobsRow
+		if err := rows.Scan(
+			&i.JobID,
+			&i.Title,
+			&i.ApplicationDeadline,
+			&i.UserID,
+			&i.CompanyName,
+			&i.RepresentativeEmail,
+			&i.Applicants,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const closeJob = `-- name: CloseJob :exec
 UPDATE jobs
-SET active_status = false
+SET active_status = false,
+    closed_by_deadline = false
 WHERE jobs.job_id = $1 
 AND jobs.company_id = (SELECT companies.company_id FROM companies WHERE user_id = $2)
 `
@@ -687,6 +1143,44 @@ func (q *Queries) CumulativeResultData(ctx context.Context, testID int64) ([]Cum
 	return items, nil
 }
 
+const deleteBankQuestion = `-- name: DeleteBankQuestion :one
+DELETE FROM bankquestions
+WHERE bankquestions.question_id = $1
+AND bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+RETURNING question_id
+`
+
+type DeleteBankQuestionParams struct {
+	QuestionID int64
+	UserID     int64
+}
+
+func (q *Queries) DeleteBankQuestion(ctx context.Context, arg DeleteBankQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteBankQuestion, arg.QuestionID, arg.UserID)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
+const deleteDrawRule = `-- name: DeleteDrawRule :one
+DELETE FROM testdrawrules
+WHERE testdrawrules.rule_id = $1
+AND testdrawrules.test_id = $2
+RETURNING rule_id
+`
+
+type DeleteDrawRuleParams struct {
+	RuleID int64
+	TestID int64
+}
+
+func (q *Queries) DeleteDrawRule(ctx context.Context, arg DeleteDrawRuleParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteDrawRule, arg.RuleID, arg.TestID)
+	var rule_id int64
+	err := row.Scan(&rule_id)
+	return rule_id, err
+}
+
 const deleteInterview = `-- name: DeleteInterview :exec
 DELETE FROM interviews
 WHERE application_id = $1
@@ -713,22 +1207,125 @@ func (q *Queries) DeleteJob(ctx context.Context, arg DeleteJobParams) error {
 	return err
 }
 
-const discussionsData = `-- name: DiscussionsData :many
-SELECT 
-    discussions.post_id,
-
-    discussions.content,
-    TO_CHAR(discussions.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+const deleteJobStages = `-- name: DeleteJobStages :exec
+DELETE FROM jobstages
+WHERE job_id = $1
+`
 
-    companies.company_name,
-    students.student_name,
+func (q *Queries) DeleteJobStages(ctx context.Context, jobID int64) error {
+	_, err := q.db.Exec(ctx, deleteJobStages, jobID)
+	return err
+}
 
-    (CASE WHEN discussions.user_id = $3 THEN true ELSE false END) AS owned
+const deleteSharedForm = `-- name: DeleteSharedForm :many
+DELETE FROM sharedforms
+WHERE sharedforms.form_id = $1
+RETURNING sharedforms.responder_uri
+`
 
-FROM discussions 
-LEFT JOIN students ON discussions.user_id = students.user_id
-LEFT JOIN companies ON discussions.user_id = companies.user_id
-WHERE thread_id IS NULL
+func (q *Queries) DeleteSharedForm(ctx context.Context, formID string) ([]string, error) {
+	rows, err := q.db.Query(ctx, deleteSharedForm, formID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var responder_uri string
+		if err := rows.Scan(&responder_uri); err != nil {
+			return nil, err
+		}
+		items = append(items, responder_uri)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteTestAttempt = `-- name: DeleteTestAttempt :exec
+DELETE FROM testresults
+WHERE testresults.result_id = $1
+`
+
+func (q *Queries) DeleteTestAttempt(ctx context.Context, resultID int64) error {
+	_, err := q.db.Exec(ctx, deleteTestAttempt, resultID)
+	return err
+}
+
+const deleteTestOverride = `-- name: DeleteTestOverride :one
+DELETE FROM testoverrides
+WHERE testoverrides.test_id = $1
+AND testoverrides.user_id = (SELECT students.user_id FROM students WHERE students.student_id = $2)
+RETURNING user_id
+`
+
+type DeleteTestOverrideParams struct {
+	TestID    int64
+	StudentID int64
+}
+
+func (q *Queries) DeleteTestOverride(ctx context.Context, arg DeleteTestOverrideParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteTestOverride, arg.TestID, arg.StudentID)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const deleteTestQuestion = `-- name: DeleteTestQuestion :one
+DELETE FROM testquestions
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id
+`
+
+type DeleteTestQuestionParams struct {
+	QuestionID int64
+	TestID     int64
+}
+
+func (q *Queries) DeleteTestQuestion(ctx context.Context, arg DeleteTestQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteTestQuestion, arg.QuestionID, arg.TestID)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
+const deleteTestSection = `-- name: DeleteTestSection :one
+DELETE FROM testsections
+WHERE testsections.section_id = $1
+AND testsections.test_id = $2
+RETURNING section_id
+`
+
+type DeleteTestSectionParams struct {
+	SectionID int64
+	TestID    int64
+}
+
+func (q *Queries) DeleteTestSection(ctx context.Context, arg DeleteTestSectionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, deleteTestSection, arg.SectionID, arg.TestID)
+	var section_id int64
+	err := row.Scan(&section_id)
+	return section_id, err
+}
+
+const discussionsData = `-- name: DiscussionsData :many
+SELECT 
+    discussions.post_id,
+
+    discussions.content,
+    TO_CHAR(discussions.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+
+    companies.company_name,
+    students.student_name,
+
+    (CASE WHEN discussions.user_id = $3 THEN true ELSE false END) AS owned
+
+FROM discussions 
+LEFT JOIN students ON discussions.user_id = students.user_id
+LEFT JOIN companies ON discussions.user_id = companies.user_id
+WHERE thread_id IS NULL
 ORDER BY discussions.created_at DESC
 OFFSET $1 LIMIT $2
 `
@@ -775,13 +1372,95 @@ func (q *Queries) DiscussionsData(ctx context.Context, arg DiscussionsDataParams
 	return items, nil
 }
 
+const drawTotalPoints = `-- name: DrawTotalPoints :one
+SELECT 
+    (SELECT COUNT(*) FROM testdrawrules WHERE testdrawrules.test_id = $1) AS rules,
+    ((
+        SELECT COALESCE(SUM(testquestions.points), 0) 
+        FROM testquestions 
+        WHERE testquestions.test_id = $1 
+        AND testquestions.rule_id IS NULL
+    ) + (
+        SELECT COALESCE(SUM(LEAST(testdrawrules.count, (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id)) * testdrawrules.points), 0)
+        FROM testdrawrules
+        WHERE testdrawrules.test_id = $1
+    ))::BIGINT AS total_points
+`
+
+type DrawTotalPointsRow struct {
+	Rules       int64
+	TotalPoints int64
+}
+
+func (q *Queries) DrawTotalPoints(ctx context.Context, testID int64) (DrawTotalPointsRow, error) {
+	row := q.db.QueryRow(ctx, drawTotalPoints, testID)
+	var i DrawTotalPointsRow
+	err := row.Scan(&i.Rules, &i.TotalPoints)
+	return i, err
+}
+
+const drivePageToken = `-- name: DrivePageToken :one
+SELECT 
+    drivestate.page_token
+FROM drivestate
+WHERE drivestate.id = true
+`
+
+func (q *Queries) DrivePageToken(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, drivePageToken)
+	var page_token string
+	err := row.Scan(&page_token)
+	return page_token, err
+}
+
+const editableTestData = `-- name: EditableTestData :one
+SELECT 
+    tests.upload_method::TEXT AS upload_method,
+    tests.end_time,
+    tests.duration,
+    (SELECT COUNT(*) FROM testresults WHERE testresults.test_id = tests.test_id) AS attempts
+FROM tests
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+`
+
+type EditableTestDataParams struct {
+	TestID int64
+	UserID int64
+}
+
+type EditableTestDataRow struct {
+	UploadMethod string
+	EndTime      pgtype.Timestamptz
+	Duration     int64
+	Attempts     int64
+}
+
+func (q *Queries) EditableTestData(ctx context.Context, arg EditableTestDataParams) (EditableTestDataRow, error) {
+	row := q.db.QueryRow(ctx, editableTestData, arg.TestID, arg.UserID)
+	var i EditableTestDataRow
+	err := row.Scan(
+		&i.UploadMethod,
+		&i.EndTime,
+		&i.Duration,
+		&i.Attempts,
+	)
+	return i, err
+}
+
 const evaluateTestResult = `-- name: EvaluateTestResult :one
 WITH tr AS (
     UPDATE testresponses
-    SET points = temp_correct_answers.points
-    FROM temp_correct_answers
-    WHERE testresponses.question_id = temp_correct_answers.question_id
+    SET 
+        points = temp_correct_answers.points,
+        max_points = temp_correct_answers.points
+    FROM testresults, temp_correct_answers
+    WHERE testresponses.result_id = testresults.result_id
+    AND testresults.test_id = $1
+    AND temp_correct_answers.test_id = testresults.test_id
+    AND testresponses.question_id = temp_correct_answers.question_id
     AND testresponses.response = temp_correct_answers.correct_answer
+    AND CARDINALITY(temp_correct_answers.correct_answer) > 0
     RETURNING testresponses.points, testresponses.result_id
 ),
 rs AS (
@@ -800,10 +1479,11 @@ up AS (
 SELECT 
     SUM(temp_correct_answers.points) AS totalpoints
 FROM temp_correct_answers
+WHERE temp_correct_answers.test_id = $1
 `
 
-func (q *Queries) EvaluateTestResult(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, evaluateTestResult)
+func (q *Queries) EvaluateTestResult(ctx context.Context, testID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, evaluateTestResult, testID)
 	var totalpoints int64
 	err := row.Scan(&totalpoints)
 	return totalpoints, err
@@ -1169,6 +1849,44 @@ func (q *Queries) FeedbacksByStudentsToCompanyUser(ctx context.Context, userID i
 	return items, nil
 }
 
+const finalizeExpiredAttempts = `-- name: FinalizeExpiredAttempts :execrows
+UPDATE testresults
+SET end_time = LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute')
+FROM tests
+WHERE testresults.test_id = tests.test_id
+AND testresults.end_time IS NULL
+AND testresults.start_time IS NOT NULL
+AND LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute') < NOW()
+`
+
+func (q *Queries) FinalizeExpiredAttempts(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, finalizeExpiredAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const flagResponsesForGrading = `-- name: FlagResponsesForGrading :exec
+UPDATE testresponses
+SET 
+    needs_grading = true,
+    max_points = temp_correct_answers.points
+FROM testresults, temp_correct_answers
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND temp_correct_answers.test_id = testresults.test_id
+AND testresponses.question_id = temp_correct_answers.question_id
+AND temp_correct_answers.manual = true
+AND testresponses.graded_at IS NULL
+AND testresponses.points IS NULL
+`
+
+func (q *Queries) FlagResponsesForGrading(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, flagResponsesForGrading, testID)
+	return err
+}
+
 const getAll = `-- name: GetAll :many
 SELECT user_id, email, password, role, user_uuid, created_at, confirmed, is_verified FROM users
 `
@@ -1310,7 +2028,13 @@ SELECT
     jobs.skills,
     jobs.company_id,
     jobs.active_status,
-    companies.company_name 
+    companies.company_name,
+    jobs.min_cgpa,
+    jobs.eligible_departments,
+    jobs.eligible_courses,
+    jobs.eligible_years,
+    jobs.eligible_genders,
+    jobs.application_deadline
 FROM jobs
 JOIN companies ON jobs.company_id = companies.company_id 
 LEFT JOIN (SELECT applications.job_id FROM applications WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)) AS t 
@@ -1325,16 +2049,22 @@ type GetApplicableJobsTypeFilterParams struct {
 }
 
 type GetApplicableJobsTypeFilterRow struct {
-	JobID        int64
-	Title        string
-	Location     string
-	Type         string
-	Salary       string
-	Position     string
-	Skills       []string
-	CompanyID    int64
-	ActiveStatus bool
-	CompanyName  string
+	JobID               int64
+	Title               string
+	Location            string
+	Type                string
+	Salary              string
+	Position            string
+	Skills              []string
+	CompanyID           int64
+	ActiveStatus        bool
+	CompanyName         string
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
 }
 
 func (q *Queries) GetApplicableJobsTypeFilter(ctx context.Context, arg GetApplicableJobsTypeFilterParams) ([]GetApplicableJobsTypeFilterRow, error) {
@@ -1357,6 +2087,12 @@ func (q *Queries) GetApplicableJobsTypeFilter(ctx context.Context, arg GetApplic
 			&i.CompanyID,
 			&i.ActiveStatus,
 			&i.CompanyName,
+			&i.MinCgpa,
+			&i.EligibleDepartments,
+			&i.EligibleCourses,
+			&i.EligibleYears,
+			&i.EligibleGenders,
+			&i.ApplicationDeadline,
 		); err != nil {
 			return nil, err
 		}
@@ -1383,11 +2119,15 @@ SELECT
     jobs.title, 
     applications.status::TEXT AS status,
     COALESCE(interviews.status::TEXT, '') AS interview_status,
-    applications.application_id
+    applications.application_id,
+    COALESCE(jobstages.name, '') AS stage,
+    COALESCE(jobstages.position, 0) AS stage_position,
+    (SELECT COUNT(*) FROM jobstages AS js WHERE js.job_id = jobs.job_id) AS total_stages
 FROM applications
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN students ON applications.student_id = students.student_id
 LEFT JOIN interviews ON applications.application_id = interviews.application_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
 WHERE jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
 AND (jobs.job_id = $2 OR $2 = 0)
 AND (applications.application_id = $3 OR $3 = 0)
@@ -1416,6 +2156,9 @@ type GetApplicantsRow struct {
 	Status          string
 	InterviewStatus interface{}
 	ApplicationID   int64
+	Stage           string
+	StagePosition   int32
+	TotalStages     int64
 }
 
 func (q *Queries) GetApplicants(ctx context.Context, arg GetApplicantsParams) ([]GetApplicantsRow, error) {
@@ -1442,6 +2185,9 @@ func (q *Queries) GetApplicants(ctx context.Context, arg GetApplicantsParams) ([
 			&i.Status,
 			&i.InterviewStatus,
 			&i.ApplicationID,
+			&i.Stage,
+			&i.StagePosition,
+			&i.TotalStages,
 		); err != nil {
 			return nil, err
 		}
@@ -1566,11 +2312,15 @@ SELECT
     companies.company_name,
     companies.representative_email,
     companies.representative_name,
-    applications.status::TEXT AS status
+    applications.status::TEXT AS status,
+    COALESCE(jobstages.name, '') AS stage,
+    COALESCE(jobstages.position, 0) AS stage_position,
+    (SELECT COUNT(*) FROM jobstages AS js WHERE js.job_id = jobs.job_id) AS total_stages
 FROM applications
 JOIN students ON applications.student_id = students.student_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
 WHERE students.user_id = $1 
   AND ($2 = 'All' OR applications.status::TEXT = $2)
 ORDER BY jobs.job_id
@@ -1594,6 +2344,9 @@ type GetMyApplicationsStatusFilterRow struct {
 	RepresentativeEmail string
 	RepresentativeName  string
 	Status              string
+	Stage               string
+	StagePosition       int32
+	TotalStages         int64
 }
 
 func (q *Queries) GetMyApplicationsStatusFilter(ctx context.Context, arg GetMyApplicationsStatusFilterParams) ([]GetMyApplicationsStatusFilterRow, error) {
@@ -1618,6 +2371,9 @@ func (q *Queries) GetMyApplicationsStatusFilter(ctx context.Context, arg GetMyAp
 			&i.RepresentativeEmail,
 			&i.RepresentativeName,
 			&i.Status,
+			&i.Stage,
+			&i.StagePosition,
+			&i.TotalStages,
 		); err != nil {
 			return nil, err
 		}
@@ -1837,6 +2593,71 @@ func (q *Queries) GetScheduleInterviewData(ctx context.Context, applicationID in
 	return i, err
 }
 
+const getTestQuestions = `-- name: GetTestQuestions :many
+SELECT 
+    testquestions.question_id,
+    testquestions.item_id,
+    testquestions.position,
+    testquestions.type,
+    testquestions.title,
+    testquestions.description,
+    testquestions.options,
+    testquestions.correct_answer,
+    testquestions.points,
+    testquestions.section_id,
+    testquestions.rule_id
+FROM testquestions
+LEFT JOIN testsections ON testquestions.section_id = testsections.section_id
+WHERE testquestions.test_id = $1
+ORDER BY testsections.position NULLS FIRST, testsections.section_id, testquestions.position, testquestions.question_id
+`
+
+type GetTestQuestionsRow struct {
+	QuestionID    int64
+	ItemID        string
+	Position      int32
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Points        int32
+	SectionID     pgtype.Int8
+	RuleID        pgtype.Int8
+}
+
+func (q *Queries) GetTestQuestions(ctx context.Context, testID int64) ([]GetTestQuestionsRow, error) {
+	rows, err := q.db.Query(ctx, getTestQuestions, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTestQuestionsRow
+	for rows.Next() {
+		var i GetTestQuestionsRow
+		if err := rows.Scan(
+			&i.QuestionID,
+			&i.ItemID,
+			&i.Position,
+			&i.Type,
+			&i.Title,
+			&i.Description,
+			&i.Options,
+			&i.CorrectAnswer,
+			&i.Points,
+			&i.SectionID,
+			&i.RuleID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserData = `-- name: GetUserData :one
 SELECT user_id, email, password, role, user_uuid, created_at, confirmed, is_verified FROM users WHERE email = $1
 `
@@ -1901,22 +2722,143 @@ func (q *Queries) GetUserUUIDFromUserID(ctx context.Context, userID int64) (pgty
 	return user_uuid, err
 }
 
+const gradeResponse = `-- name: GradeResponse :exec
+UPDATE testresponses
+SET 
+    points = $2,
+    grader_comment = $3,
+    graded_at = NOW()
+WHERE testresponses.response_id = $1
+`
+
+type GradeResponseParams struct {
+	ResponseID    int64
+	Points        pgtype.Int4
+	GraderComment pgtype.Text
+}
+
+func (q *Queries) GradeResponse(ctx context.Context, arg GradeResponseParams) error {
+	_, err := q.db.Exec(ctx, gradeResponse, arg.ResponseID, arg.Points, arg.GraderComment)
+	return err
+}
+
+const gradingQueue = `-- name: GradingQueue :many
+SELECT 
+    testresponses.response_id,
+    testresponses.result_id,
+    testresponses.question_id,
+    testresponses.response,
+    testresponses.max_points,
+    students.student_name,
+    students.roll_number
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+JOIN students ON testresults.user_id = students.user_id
+WHERE testresults.test_id = $1
+AND testresponses.needs_grading = true
+AND testresponses.graded_at IS NULL
+ORDER BY testresponses.question_id, testresponses.response_id
+`
+
+type GradingQueueRow struct {
+	ResponseID  int64
+	ResultID    int64
+	QuestionID  string
+	Response    []string
+	MaxPoints   pgtype.Int4
+	StudentName string
+	RollNumber  string
+}
+
+func (q *Queries) GradingQueue(ctx context.Context, testID int64) ([]GradingQueueRow, error) {
+	rows, err := q.db.Query(ctx, gradingQueue, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GradingQueueRow
+	for rows.Next() {
+		var i GradingQueueRow
+		if err := rows.Scan(
+			&i.ResponseID,
+			&i.ResultID,
+			&i.QuestionID,
+			&i.Response,
+			&i.MaxPoints,
+			&i.StudentName,
+			&i.RollNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAnswers = `-- name: InsertAnswers :exec
-INSERT INTO temp_correct_answers (question_id, correct_answer, points)
-VALUES ($1, $2, $3)
+INSERT INTO temp_correct_answers (test_id, question_id, correct_answer, points, manual, multiple, section)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type InsertAnswersParams struct {
+	TestID        int64
 	QuestionID    string
 	CorrectAnswer []string
 	Points        pgtype.Int4
+	Manual        bool
+	Multiple      bool
+	Section       int32
 }
 
 func (q *Queries) InsertAnswers(ctx context.Context, arg InsertAnswersParams) error {
-	_, err := q.db.Exec(ctx, insertAnswers, arg.QuestionID, arg.CorrectAnswer, arg.Points)
+	_, err := q.db.Exec(ctx, insertAnswers,
+		arg.TestID,
+		arg.QuestionID,
+		arg.CorrectAnswer,
+		arg.Points,
+		arg.Manual,
+		arg.Multiple,
+		arg.Section,
+	)
 	return err
 }
 
+const insertBankQuestion = `-- name: InsertBankQuestion :one
+INSERT INTO bankquestions (company_id, type, title, description, options, correct_answer, tags, difficulty)
+VALUES ((SELECT companies.company_id FROM companies WHERE companies.user_id = $1), $2, $3, $4, $5, $6, $7, $8)
+RETURNING question_id
+`
+
+type InsertBankQuestionParams struct {
+	UserID        int64
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Tags          []string
+	Difficulty    string
+}
+
+func (q *Queries) InsertBankQuestion(ctx context.Context, arg InsertBankQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertBankQuestion,
+		arg.UserID,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Options,
+		arg.CorrectAnswer,
+		arg.Tags,
+		arg.Difficulty,
+	)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
 const insertDiscussion = `-- name: InsertDiscussion :exec
 INSERT INTO discussions (user_id, role, content)
 VALUES ($1, (SELECT role FROM users WHERE users.user_id = $1), $2)
@@ -1932,6 +2874,37 @@ func (q *Queries) InsertDiscussion(ctx context.Context, arg InsertDiscussionPara
 	return err
 }
 
+const insertDrawRule = `-- name: InsertDrawRule :one
+INSERT INTO testdrawrules (test_id, tags, difficulty, count, points, position, section_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING rule_id
+`
+
+type InsertDrawRuleParams struct {
+	TestID     int64
+	Tags       []string
+	Difficulty pgtype.Text
+	Count      int32
+	Points     int32
+	Position   int32
+	SectionID  pgtype.Int8
+}
+
+func (q *Queries) InsertDrawRule(ctx context.Context, arg InsertDrawRuleParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertDrawRule,
+		arg.TestID,
+		arg.Tags,
+		arg.Difficulty,
+		arg.Count,
+		arg.Points,
+		arg.Position,
+		arg.SectionID,
+	)
+	var rule_id int64
+	err := row.Scan(&rule_id)
+	return rule_id, err
+}
+
 const insertFeedbackByCompanyToStudent = `-- name: InsertFeedbackByCompanyToStudent :exec
 INSERT INTO feedbacks (application_id, interview_id, user_id, message)
 VALUES ($1, $2, $3, $4)
@@ -1976,9 +2949,53 @@ func (q *Queries) InsertFeedbackByStudentToCompany(ctx context.Context, arg Inse
 	return err
 }
 
-const insertNewApplication = `-- name: InsertNewApplication :exec
+const insertJobStages = `-- name: InsertJobStages :many
+INSERT INTO jobstages (job_id, position, name)
+SELECT $1::BIGINT, stages.position, stages.name
+FROM UNNEST($2::TEXT[]) WITH ORDINALITY AS stages(name, position)
+RETURNING stage_id, position, name
+`
+
+type InsertJobStagesParams struct {
+	JobID int64
+	Names []string
+}
+
+type InsertJobStagesRow struct {
+	StageID  int64
+	Position int32
+	Name     string
+}
+
+func (q *Queries) InsertJobStages(ctx context.Context, arg InsertJobStagesParams) ([]InsertJobStagesRow, error) {
+	rows, err := q.db.Query(ctx, insertJobStages, arg.JobID, arg.Names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InsertJobStagesRow
+	for rows.Next() {
+		var i InsertJobStagesRow
+		if err := rows.Scan(&i.StageID, &i.Position, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertNewApplication = `-- name: InsertNewApplication :execrows
 INSERT INTO applications (job_id, student_id, data_url) 
-VALUES ($1, (SELECT student_id FROM students WHERE user_id = $2), $3)
+SELECT $1, (SELECT student_id FROM students WHERE user_id = $2), $3
+WHERE EXISTS (
+    SELECT 1 FROM jobs
+    WHERE jobs.job_id = $1
+    AND jobs.active_status
+    AND (jobs.application_deadline IS NULL OR jobs.application_deadline > NOW())
+)
 `
 
 type InsertNewApplicationParams struct {
@@ -1987,37 +3004,50 @@ type InsertNewApplicationParams struct {
 	DataUrl pgtype.Text
 }
 
-func (q *Queries) InsertNewApplication(ctx context.Context, arg InsertNewApplicationParams) error {
-	_, err := q.db.Exec(ctx, insertNewApplication, arg.JobID, arg.UserID, arg.DataUrl)
-	return err
+func (q *Queries) InsertNewApplication(ctx context.Context, arg InsertNewApplicationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, insertNewApplication, arg.JobID, arg.UserID, arg.DataUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const insertNewJob = `-- name: InsertNewJob :exec
-
-INSERT INTO jobs (data_url, company_id, title, location, type, salary, skills, position, extras, description)
-VALUES ($1, (SELECT company_id FROM companies WHERE companies.user_id = $2), $3, $4, $5, $6, $7, $8, $9, $10)
+const insertNewJob = `-- name: InsertNewJob :one
+WITH job AS (
+    INSERT INTO jobs (data_url, company_id, title, location, type, salary, skills, position, extras, description, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    VALUES ($1, (SELECT company_id FROM companies WHERE companies.user_id = $2), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+    RETURNING *
+)
+INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+SELECT job_id, 1, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline
+FROM job
+RETURNING job_id
 `
 
 type InsertNewJobParams struct {
-	DataUrl     pgtype.Text
-	UserID      int64
-	Title       string
-	Location    string
-	Type        string
-	Salary      string
-	Skills      []string
-	Position    string
-	Extras      []byte
-	Description pgtype.Text
-}
-
-// >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
-// Company queries
-func (q *Queries) InsertNewJob(ctx context.Context, arg InsertNewJobParams) error {
-	_, err := q.db.Exec(ctx, insertNewJob,
-		arg.DataUrl,
-		arg.UserID,
-		arg.Title,
+	DataUrl             pgtype.Text
+	UserID              int64
+	Title               string
+	Location            string
+	Type                string
+	Salary              string
+	Skills              []string
+	Position            string
+	Extras              []byte
+	Description         pgtype.Text
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+}
+
+func (q *Queries) InsertNewJob(ctx context.Context, arg InsertNewJobParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertNewJob,
+		arg.DataUrl,
+		arg.UserID,
+		arg.Title,
 		arg.Location,
 		arg.Type,
 		arg.Salary,
@@ -2025,8 +3055,16 @@ func (q *Queries) InsertNewJob(ctx context.Context, arg InsertNewJobParams) erro
 		arg.Position,
 		arg.Extras,
 		arg.Description,
+		arg.MinCgpa,
+		arg.EligibleDepartments,
+		arg.EligibleCourses,
+		arg.EligibleYears,
+		arg.EligibleGenders,
+		arg.ApplicationDeadline,
 	)
-	return err
+	var job_id int64
+	err := row.Scan(&job_id)
+	return job_id, err
 }
 
 const insertNotifications = `-- name: InsertNotifications :exec
@@ -2051,6 +3089,184 @@ func (q *Queries) InsertNotifications(ctx context.Context, arg InsertNotificatio
 	return err
 }
 
+const insertProctorEvent = `-- name: InsertProctorEvent :exec
+INSERT INTO proctorevents (result_id, event_type, detail, ip_address)
+VALUES ($1, $2, $3, $4)
+`
+
+type InsertProctorEventParams struct {
+	ResultID  int64
+	EventType string
+	Detail    pgtype.Text
+	IpAddress string
+}
+
+func (q *Queries) InsertProctorEvent(ctx context.Context, arg InsertProctorEventParams) error {
+	_, err := q.db.Exec(ctx, insertProctorEvent,
+		arg.ResultID,
+		arg.EventType,
+		arg.Detail,
+		arg.IpAddress,
+	)
+	return err
+}
+
+const insertSimilarityFlag = `-- name: InsertSimilarityFlag :exec
+INSERT INTO similarityflags (
+    test_id, result_a, result_b, common_questions, shared_wrong, time_difference
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+`
+
+type InsertSimilarityFlagParams struct {
+	TestID          int64
+	ResultA         int64
+	ResultB         int64
+	CommonQuestions int32
+	SharedWrong     int32
+	TimeDifference  pgtype.Float8
+}
+
+func (q *Queries) InsertSimilarityFlag(ctx context.Context, arg InsertSimilarityFlagParams) error {
+	_, err := q.db.Exec(ctx, insertSimilarityFlag,
+		arg.TestID,
+		arg.ResultA,
+		arg.ResultB,
+		arg.CommonQuestions,
+		arg.SharedWrong,
+		arg.TimeDifference,
+	)
+	return err
+}
+
+const insertTestQuestion = `-- name: InsertTestQuestion :one
+INSERT INTO testquestions (test_id, position, type, title, description, options, correct_answer, points, section_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING question_id
+`
+
+type InsertTestQuestionParams struct {
+	TestID        int64
+	Position      int32
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Points        int32
+	SectionID     pgtype.Int8
+}
+
+func (q *Queries) InsertTestQuestion(ctx context.Context, arg InsertTestQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertTestQuestion,
+		arg.TestID,
+		arg.Position,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Options,
+		arg.CorrectAnswer,
+		arg.Points,
+		arg.SectionID,
+	)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
+const insertTestSection = `-- name: InsertTestSection :one
+INSERT INTO testsections (test_id, name, position, duration, min_score)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING section_id
+`
+
+type InsertTestSectionParams struct {
+	TestID   int64
+	Name     string
+	Position int32
+	Duration pgtype.Int8
+	MinScore pgtype.Int4
+}
+
+func (q *Queries) InsertTestSection(ctx context.Context, arg InsertTestSectionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, insertTestSection,
+		arg.TestID,
+		arg.Name,
+		arg.Position,
+		arg.Duration,
+		arg.MinScore,
+	)
+	var section_id int64
+	err := row.Scan(&section_id)
+	return section_id, err
+}
+
+const integrityFlags = `-- name: IntegrityFlags :many
+SELECT 
+    testresults.result_id,
+    students.student_name,
+    students.roll_number,
+    COUNT(CASE WHEN proctorevents.event_type = 'TabSwitch' THEN 1 ELSE NULL END) AS tab_switches,
+    COUNT(CASE WHEN proctorevents.event_type = 'FocusLoss' THEN 1 ELSE NULL END) AS focus_losses,
+    COUNT(CASE WHEN proctorevents.event_type = 'FullscreenExit' THEN 1 ELSE NULL END) AS fullscreen_exits,
+    COUNT(CASE WHEN proctorevents.event_type IN ('Copy', 'Paste') THEN 1 ELSE NULL END) AS copy_pastes,
+    COUNT(CASE WHEN proctorevents.event_type = 'IPChange' THEN 1 ELSE NULL END) AS ip_changes
+FROM testresults
+JOIN students ON testresults.user_id = students.user_id
+JOIN proctorevents ON testresults.result_id = proctorevents.result_id
+WHERE testresults.test_id = $1
+GROUP BY testresults.result_id, students.student_name, students.roll_number
+HAVING COUNT(CASE WHEN proctorevents.event_type = 'IPChange' THEN 1 ELSE NULL END) > 0
+OR COUNT(proctorevents.event_id) >= $2
+ORDER BY COUNT(proctorevents.event_id) DESC, testresults.result_id
+`
+
+type IntegrityFlagsParams struct {
+	TestID    int64
+	MinEvents int64
+}
+
+type IntegrityFlagsRow struct {
+	ResultID        int64
+	StudentName     string
+	RollNumber      string
+	TabSwitches     int64
+	FocusLosses     int64
+	FullscreenExits int64
+	CopyPastes      int64
+	IpChanges       int64
+}
+
+func (q *Queries) IntegrityFlags(ctx context.Context, arg IntegrityFlagsParams) ([]IntegrityFlagsRow, error) {
+	rows, err := q.db.Query(ctx, integrityFlags, arg.TestID, arg.MinEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IntegrityFlagsRow
+	for rows.Next() {
+		var i IntegrityFlagsRow
+		if err := rows.Scan(
+			&i.ResultID,
+			&i.StudentName,
+			&i.RollNumber,
+			&i.TabSwitches,
+			&i.FocusLosses,
+			&i.FullscreenExits,
+			&i.CopyPastes,
+			&i.IpChanges,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const interviewHistory = `-- name: InterviewHistory :many
 SELECT 
     interviews.interview_id,
@@ -2154,64 +3370,61 @@ func (q *Queries) IsTestPublished(ctx context.Context, testID int64) (bool, erro
 	return published, err
 }
 
-const listToVerifyStudent = `-- name: ListToVerifyStudent :many
-
-
-
-
+const itemAnalysis = `-- name: ItemAnalysis :many
+WITH attempts AS (
+    SELECT 
+        testresults.result_id,
+        ROW_NUMBER() OVER (ORDER BY COALESCE(testresults.score, 0) DESC, testresults.result_id) AS position,
+        COUNT(*) OVER () AS total
+    FROM testresults
+    WHERE testresults.test_id = $1
+    AND testresults.end_time IS NOT NULL
+),
+groups AS (
+    SELECT 
+        attempts.result_id,
+        attempts.total,
+        attempts.position <= CEIL(attempts.total * 0.27) AS top_group,
+        attempts.position > attempts.total - CEIL(attempts.total * 0.27) AS bottom_group
+    FROM attempts
+)
 SELECT 
-    users.user_id,
-    users.email,
-    TO_CHAR(users.created_at, 'HH12:MI AM DD-MM-YYYY') AS created_at,
-    users.confirmed
-FROM users
-WHERE users.confirmed = true
-AND users.is_verified = false
-AND users.role = 1
+    testresponses.question_id,
+    COUNT(*) AS attempted,
+    COUNT(CASE WHEN testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS correct,
+    COUNT(CASE WHEN groups.top_group AND testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS top_correct,
+    COUNT(CASE WHEN groups.bottom_group AND testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS bottom_correct,
+    COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY testresponses.time_taken), 0)::FLOAT8 AS median_time_taken
+FROM testresponses
+JOIN groups ON testresponses.result_id = groups.result_id
+GROUP BY testresponses.question_id
 `
 
-type ListToVerifyStudentRow struct {
-	UserID    int64
-	Email     string
-	CreatedAt string
-	Confirmed bool
+type ItemAnalysisRow struct {
+	QuestionID      string
+	Attempted       int64
+	Correct         int64
+	TopCorrect      int64
+	BottomCorrect   int64
+	MedianTimeTaken float64
 }
 
-// -- name: GetStudentProfile :one
-// SELECT
-//
-//	students.student_name,
-//	students.roll_number,
-//	students.student_dob,
-//	students.gender,
-//	students.course,
-//	students.department,
-//	students.year_of_study,
-//	students.cgpa,
-//	students.contact_no,
-//	students.student_email,
-//	students.address,
-//	students.skills,
-//	students.extras
-//
-// FROM students
-// WHERE students.student_id = $1;
-// >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
-// Admin Functions --------------------------------
-func (q *Queries) ListToVerifyStudent(ctx context.Context) ([]ListToVerifyStudentRow, error) {
-	rows, err := q.db.Query(ctx, listToVerifyStudent)
+func (q *Queries) ItemAnalysis(ctx context.Context, testID int64) ([]ItemAnalysisRow, error) {
+	rows, err := q.db.Query(ctx, itemAnalysis, testID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListToVerifyStudentRow
+	var items []ItemAnalysisRow
 	for rows.Next() {
-		var i ListToVerifyStudentRow
+		var i ItemAnalysisRow
 		if err := rows.Scan(
-			&i.UserID,
-			&i.Email,
-			&i.CreatedAt,
-			&i.Confirmed,
+			&i.QuestionID,
+			&i.Attempted,
+			&i.Correct,
+			&i.TopCorrect,
+			&i.BottomCorrect,
+			&i.MedianTimeTaken,
 		); err != nil {
 			return nil, err
 		}
@@ -2223,146 +3436,1679 @@ func (q *Queries) ListToVerifyStudent(ctx context.Context) ([]ListToVerifyStuden
 	return items, nil
 }
 
-const newTest = `-- name: NewTest :exec
-INSERT INTO tests (test_name, description, duration, q_count, end_time, type, upload_method, job_id, company_id, file_id, threshold)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT company_id FROM companies WHERE user_id = $9), $10, $11)
+const itemAnalysisAttempts = `-- name: ItemAnalysisAttempts :many
+SELECT 
+    testresults.user_id,
+    tests.shuffle_seed
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
 `
 
-type NewTestParams struct {
-	TestName     string
-	Description  pgtype.Text
-	Duration     int64
-	QCount       int64
-	EndTime      pgtype.Timestamptz
-	Type         string
-	UploadMethod interface{}
-	JobID        pgtype.Int8
-	UserID       int64
-	FileID       string
-	Threshold    int32
+type ItemAnalysisAttemptsRow struct {
+	UserID      int64
+	ShuffleSeed int64
 }
 
-func (q *Queries) NewTest(ctx context.Context, arg NewTestParams) error {
-	_, err := q.db.Exec(ctx, newTest,
-		arg.TestName,
-		arg.Description,
-		arg.Duration,
-		arg.QCount,
-		arg.EndTime,
-		arg.Type,
-		arg.UploadMethod,
-		arg.JobID,
-		arg.UserID,
-		arg.FileID,
-		arg.Threshold,
-	)
-	return err
+func (q *Queries) ItemAnalysisAttempts(ctx context.Context, testID int64) ([]ItemAnalysisAttemptsRow, error) {
+	rows, err := q.db.Query(ctx, itemAnalysisAttempts, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ItemAnalysisAttemptsRow
+	for rows.Next() {
+		var i ItemAnalysisAttemptsRow
+		if err := rows.Scan(&i.UserID, &i.ShuffleSeed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const newTestResult = `-- name: NewTestResult :exec
-INSERT INTO testresults (test_id, user_id, start_time)
-VALUES ($1, $2, $3)
+const itemAnalysisCandidates = `-- name: ItemAnalysisCandidates :one
+SELECT 
+    COUNT(*) AS candidates,
+    CEIL(COUNT(*) * 0.27)::BIGINT AS group_size
+FROM testresults
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
 `
 
-type NewTestResultParams struct {
-	TestID    int64
-	UserID    int64
-	StartTime pgtype.Timestamptz
+type ItemAnalysisCandidatesRow struct {
+	Candidates int64
+	GroupSize  int64
 }
 
-func (q *Queries) NewTestResult(ctx context.Context, arg NewTestResultParams) error {
-	_, err := q.db.Exec(ctx, newTestResult, arg.TestID, arg.UserID, arg.StartTime)
-	return err
+func (q *Queries) ItemAnalysisCandidates(ctx context.Context, testID int64) (ItemAnalysisCandidatesRow, error) {
+	row := q.db.QueryRow(ctx, itemAnalysisCandidates, testID)
+	var i ItemAnalysisCandidatesRow
+	err := row.Scan(&i.Candidates, &i.GroupSize)
+	return i, err
 }
 
-const scheduleInterview = `-- name: ScheduleInterview :one
-INSERT INTO interviews (application_id, company_id, date_time, type, notes, location)
-VALUES ($1, (SELECT company_id FROM companies WHERE user_id = $2), $3, $4, $5, $6)
-RETURNING TO_CHAR(date_time, 'HH12:MI AM DD-MM-YYYY') AS date_time
+const itemOptionFrequency = `-- name: ItemOptionFrequency :many
+SELECT 
+    testresponses.question_id,
+    options.option::TEXT AS option,
+    COUNT(*) AS picks
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+CROSS JOIN LATERAL UNNEST(testresponses.response) AS options(option)
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
+GROUP BY testresponses.question_id, options.option
 `
 
-type ScheduleInterviewParams struct {
-	ApplicationID int64
-	UserID        int64
-	DateTime      pgtype.Timestamptz
-	Type          interface{}
-	Notes         pgtype.Text
-	Location      string
+type ItemOptionFrequencyRow struct {
+	QuestionID string
+	Option     string
+	Picks      int64
 }
 
-func (q *Queries) ScheduleInterview(ctx context.Context, arg ScheduleInterviewParams) (string, error) {
-	row := q.db.QueryRow(ctx, scheduleInterview,
-		arg.ApplicationID,
-		arg.UserID,
-		arg.DateTime,
-		arg.Type,
-		arg.Notes,
-		arg.Location,
-	)
-	var date_time string
-	err := row.Scan(&date_time)
-	return date_time, err
+func (q *Queries) ItemOptionFrequency(ctx context.Context, testID int64) ([]ItemOptionFrequencyRow, error) {
+	rows, err := q.db.Query(ctx, itemOptionFrequency, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ItemOptionFrequencyRow
+	for rows.Next() {
+		var i ItemOptionFrequencyRow
+		if err := rows.Scan(&i.QuestionID, &i.Option, &i.Picks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const scheduledInterviewsCompany = `-- name: ScheduledInterviewsCompany :many
-SELECT 
-    interviews.interview_id,
-    TO_CHAR(interviews.date_time, 'HH12:MI AM DD-MM-YYYY') AS date_time,
-    interviews.type::TEXT,
-    interviews.status::TEXT,
-    interviews.notes,
-    interviews.location,
-    interviews.extras,
+const jobApplicantUsers = `-- name: JobApplicantUsers :many
+SELECT students.user_id
+FROM applications
+JOIN students ON applications.student_id = students.student_id
+WHERE applications.job_id = $1
+AND applications.status != 'Rejected'
+`
+
+func (q *Queries) JobApplicantUsers(ctx context.Context, jobID int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, jobApplicantUsers, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var user_id int64
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const jobDeadline = `-- name: JobDeadline :one
+SELECT jobs.application_deadline
+FROM jobs
+WHERE jobs.job_id = $1
+AND jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+`
+
+type JobDeadlineParams struct {
+	JobID  int64
+	UserID int64
+}
+
+func (q *Queries) JobDeadline(ctx context.Context, arg JobDeadlineParams) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, jobDeadline, arg.JobID, arg.UserID)
+	var application_deadline pgtype.Timestamptz
+	err := row.Scan(&application_deadline)
+	return application_deadline, err
+}
+
+const jobEligibility = `-- name: JobEligibility :one
+SELECT active_status, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline, salary
+FROM jobs
+WHERE job_id = $1
+`
+
+type JobEligibilityRow struct {
+	ActiveStatus        bool
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+	Salary              string
+}
+
+func (q *Queries) JobEligibility(ctx context.Context, jobID int64) (JobEligibilityRow, error) {
+	row := q.db.QueryRow(ctx, jobEligibility, jobID)
+	var i JobEligibilityRow
+	err := row.Scan(
+		&i.ActiveStatus,
+		&i.MinCgpa,
+		&i.EligibleDepartments,
+		&i.EligibleCourses,
+		&i.EligibleYears,
+		&i.EligibleGenders,
+		&i.ApplicationDeadline,
+		&i.Salary,
+	)
+	return i, err
+}
+
+const jobStages = `-- name: JobStages :many
+SELECT 
+    stage_id,
+    position,
+    name
+FROM jobstages
+WHERE job_id = $1
+ORDER BY position
+`
+
+type JobStagesRow struct {
+	StageID  int64
+	Position int32
+	Name     string
+}
+
+func (q *Queries) JobStages(ctx context.Context, jobID int64) ([]JobStagesRow, error) {
+	rows, err := q.db.Query(ctx, jobStages, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobStagesRow
+	for rows.Next() {
+		var i JobStagesRow
+		if err := rows.Scan(&i.StageID, &i.Position, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const jobStagesEditable = `-- name: JobStagesEditable :one
+SELECT 
+    (SELECT COUNT(*) FROM applications WHERE applications.job_id = jobs.job_id AND applications.stage_id IS NOT NULL) AS in_progress
+FROM jobs
+JOIN companies ON companies.company_id = jobs.company_id
+WHERE jobs.job_id = $1
+AND companies.user_id = $2
+FOR UPDATE OF jobs
+`
+
+type JobStagesEditableParams struct {
+	JobID  int64
+	UserID int64
+}
+
+func (q *Queries) JobStagesEditable(ctx context.Context, arg JobStagesEditableParams) (int64, error) {
+	row := q.db.QueryRow(ctx, jobStagesEditable, arg.JobID, arg.UserID)
+	var in_progress int64
+	err := row.Scan(&in_progress)
+	return in_progress, err
+}
+
+const jobVersions = `-- name: JobVersions :many
+SELECT 
+    jobversions.version,
+    jobversions.title,
+    jobversions.location,
+    jobversions.type,
+    jobversions.salary,
+    jobversions.skills,
+    jobversions.position,
+    jobversions.description,
+    jobversions.min_cgpa,
+    jobversions.eligible_departments,
+    jobversions.eligible_courses,
+    jobversions.eligible_years,
+    jobversions.eligible_genders,
+    jobversions.application_deadline,
+    jobversions.created_at
+FROM jobversions
+JOIN jobs ON jobs.job_id = jobversions.job_id
+JOIN companies ON companies.company_id = jobs.company_id
+WHERE jobversions.job_id = $1
+AND companies.user_id = $2
+ORDER BY jobversions.version DESC
+`
+
+type JobVersionsParams struct {
+	JobID  int64
+	UserID int64
+}
+
+type JobVersionsRow struct {
+	Version             int32
+	Title               string
+	Location            string
+	Type                string
+	Salary              string
+	Skills              []string
+	Position            string
+	Description         pgtype.Text
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+	CreatedAt           pgtype.Timestamptz
+}
+
+func (q *Queries) JobVersions(ctx context.Context, arg JobVersionsParams) ([]JobVersionsRow, error) {
+	rows, err := q.db.Query(ctx, jobVersions, arg.JobID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobVersionsRow
+	for rows.Next() {
+		var i JobVersionsRow
+		if err := rows.Scan(
+			&i.Version,
+			&i.Title,
+			&i.Location,
+			&i.Type,
+			&i.Salary,
+			&i.Skills,
+			&i.Position,
+			&i.Description,
+			&i.MinCgpa,
+			&i.EligibleDepartments,
+			&i.EligibleCourses,
+			&i.EligibleYears,
+			&i.EligibleGenders,
+			&i.ApplicationDeadline,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const latestResultVersions = `-- name: LatestResultVersions :many
+SELECT 
+    resultversions.version
+FROM resultversions
+WHERE resultversions.test_id = $1
+ORDER BY resultversions.version DESC
+LIMIT 2
+`
+
+func (q *Queries) LatestResultVersions(ctx context.Context, testID int64) ([]int32, error) {
+	rows, err := q.db.Query(ctx, latestResultVersions, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var version int32
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		items = append(items, version)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const latestShortlistBatch = `-- name: LatestShortlistBatch :one
+SELECT 
+    shortlistbatches.batch_id,
+    shortlistbatches.undone_at
+FROM shortlistbatches
+WHERE shortlistbatches.test_id = $1
+ORDER BY shortlistbatches.batch_id DESC
+LIMIT 1
+`
+
+type LatestShortlistBatchRow struct {
+	BatchID  int64
+	UndoneAt pgtype.Timestamptz
+}
+
+func (q *Queries) LatestShortlistBatch(ctx context.Context, testID int64) (LatestShortlistBatchRow, error) {
+	row := q.db.QueryRow(ctx, latestShortlistBatch, testID)
+	var i LatestShortlistBatchRow
+	err := row.Scan(&i.BatchID, &i.UndoneAt)
+	return i, err
+}
+
+const listBankQuestions = `-- name: ListBankQuestions :many
+SELECT 
+    bankquestions.question_id,
+    bankquestions.type,
+    bankquestions.title,
+    bankquestions.description,
+    bankquestions.options,
+    bankquestions.correct_answer,
+    bankquestions.tags,
+    bankquestions.difficulty
+FROM bankquestions
+WHERE bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+AND ($2::TEXT IS NULL OR $2::TEXT = ANY(bankquestions.tags))
+AND ($3::TEXT IS NULL OR bankquestions.difficulty = $3::TEXT)
+ORDER BY bankquestions.question_id
+`
+
+type ListBankQuestionsParams struct {
+	UserID     int64
+	Tag        pgtype.Text
+	Difficulty pgtype.Text
+}
+
+type ListBankQuestionsRow struct {
+	QuestionID    int64
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Tags          []string
+	Difficulty    string
+}
+
+func (q *Queries) ListBankQuestions(ctx context.Context, arg ListBankQuestionsParams) ([]ListBankQuestionsRow, error) {
+	rows, err := q.db.Query(ctx, listBankQuestions, arg.UserID, arg.Tag, arg.Difficulty)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBankQuestionsRow
+	for rows.Next() {
+		var i ListBankQuestionsRow
+		if err := rows.Scan(
+			&i.QuestionID,
+			&i.Type,
+			&i.Title,
+			&i.Description,
+			&i.Options,
+			&i.CorrectAnswer,
+			&i.Tags,
+			&i.Difficulty,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDrawRules = `-- name: ListDrawRules :many
+SELECT 
+    testdrawrules.rule_id,
+    testdrawrules.tags,
+    testdrawrules.difficulty,
+    testdrawrules.count,
+    testdrawrules.points,
+    testdrawrules.position,
+    testdrawrules.section_id,
+    (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id) AS pool_size
+FROM testdrawrules
+WHERE testdrawrules.test_id = $1
+ORDER BY testdrawrules.position, testdrawrules.rule_id
+`
+
+type ListDrawRulesRow struct {
+	RuleID     int64
+	Tags       []string
+	Difficulty pgtype.Text
+	Count      int32
+	Points     int32
+	Position   int32
+	SectionID  pgtype.Int8
+	PoolSize   int64
+}
+
+func (q *Queries) ListDrawRules(ctx context.Context, testID int64) ([]ListDrawRulesRow, error) {
+	rows, err := q.db.Query(ctx, listDrawRules, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDrawRulesRow
+	for rows.Next() {
+		var i ListDrawRulesRow
+		if err := rows.Scan(
+			&i.RuleID,
+			&i.Tags,
+			&i.Difficulty,
+			&i.Count,
+			&i.Points,
+			&i.Position,
+			&i.SectionID,
+			&i.PoolSize,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listResultVersions = `-- name: ListResultVersions :many
+SELECT 
+    resultversions.version,
+    resultversions.reason,
+    resultversions.threshold,
+    resultversions.cutoff_marks,
+    resultversions.total_points,
+    TO_CHAR(resultversions.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+    COUNT(CASE WHEN resultversionscores.passed THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN NOT resultversionscores.passed THEN 1 ELSE NULL END) AS fail_count
+FROM resultversions
+LEFT JOIN resultversionscores ON resultversions.version_id = resultversionscores.version_id
+WHERE resultversions.test_id = $1
+GROUP BY resultversions.version_id
+ORDER BY resultversions.version DESC
+`
+
+type ListResultVersionsRow struct {
+	Version     int32
+	Reason      string
+	Threshold   int32
+	CutoffMarks int64
+	TotalPoints int64
+	CreatedAt   string
+	PassCount   int64
+	FailCount   int64
+}
+
+func (q *Queries) ListResultVersions(ctx context.Context, testID int64) ([]ListResultVersionsRow, error) {
+	rows, err := q.db.Query(ctx, listResultVersions, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListResultVersionsRow
+	for rows.Next() {
+		var i ListResultVersionsRow
+		if err := rows.Scan(
+			&i.Version,
+			&i.Reason,
+			&i.Threshold,
+			&i.CutoffMarks,
+			&i.TotalPoints,
+			&i.CreatedAt,
+			&i.PassCount,
+			&i.FailCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTestOverrides = `-- name: ListTestOverrides :many
+SELECT 
+    students.student_id,
+    students.student_name,
+    students.roll_number,
+    testoverrides.extra_minutes,
+    COALESCE(TO_CHAR(testoverrides.end_time, 'HH12:MI AM DD-MM-YYYY'), '')::TEXT AS end_time,
+    testoverrides.fresh_attempt,
+    testoverrides.reason,
+    TO_CHAR(testoverrides.created_at, 'HH12:MI AM DD-MM-YYYY') AS granted_at
+FROM testoverrides
+JOIN students ON testoverrides.user_id = students.user_id
+WHERE testoverrides.test_id = $1
+ORDER BY testoverrides.created_at DESC
+`
+
+type ListTestOverridesRow struct {
+	StudentID    int64
+	StudentName  string
+	RollNumber   string
+	ExtraMinutes int32
+	EndTime      string
+	FreshAttempt bool
+	Reason       string
+	GrantedAt    string
+}
+
+func (q *Queries) ListTestOverrides(ctx context.Context, testID int64) ([]ListTestOverridesRow, error) {
+	rows, err := q.db.Query(ctx, listTestOverrides, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTestOverridesRow
+	for rows.Next() {
+		var i ListTestOverridesRow
+		if err := rows.Scan(
+			&i.StudentID,
+			&i.StudentName,
+			&i.RollNumber,
+			&i.ExtraMinutes,
+			&i.EndTime,
+			&i.FreshAttempt,
+			&i.Reason,
+			&i.GrantedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTestSections = `-- name: ListTestSections :many
+SELECT 
+    testsections.section_id,
+    testsections.name,
+    testsections.position,
+    testsections.duration,
+    testsections.min_score
+FROM testsections
+WHERE testsections.test_id = $1
+ORDER BY testsections.position, testsections.section_id
+`
+
+type ListTestSectionsRow struct {
+	SectionID int64
+	Name      string
+	Position  int32
+	Duration  pgtype.Int8
+	MinScore  pgtype.Int4
+}
+
+func (q *Queries) ListTestSections(ctx context.Context, testID int64) ([]ListTestSectionsRow, error) {
+	rows, err := q.db.Query(ctx, listTestSections, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTestSectionsRow
+	for rows.Next() {
+		var i ListTestSectionsRow
+		if err := rows.Scan(
+			&i.SectionID,
+			&i.Name,
+			&i.Position,
+			&i.Duration,
+			&i.MinScore,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listToVerifyStudent = `-- name: ListToVerifyStudent :many
+
+
+
+
+SELECT 
+    users.user_id,
+    users.email,
+    TO_CHAR(users.created_at, 'HH12:MI AM DD-MM-YYYY') AS created_at,
+    users.confirmed
+FROM users
+WHERE users.confirmed = true
+AND users.is_verified = false
+AND users.role = 1
+`
+
+type ListToVerifyStudentRow struct {
+	UserID    int64
+	Email     string
+	CreatedAt string
+	Confirmed bool
+}
+
+// -- name: GetStudentProfile :one
+// SELECT
+//
+//	students.student_name,
+//	students.roll_number,
+//	students.student_dob,
+//	students.gender,
+//	students.course,
+//	students.department,
+//	students.year_of_study,
+//	students.cgpa,
+//	students.contact_no,
+//	students.student_email,
+//	students.address,
+//	students.skills,
+//	students.extras
+//
+// FROM students
+// WHERE students.student_id = $1;
+// >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
+// Admin Functions --------------------------------
+func (q *Queries) ListToVerifyStudent(ctx context.Context) ([]ListToVerifyStudentRow, error) {
+	rows, err := q.db.Query(ctx, listToVerifyStudent)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListToVerifyStudentRow
+	for rows.Next() {
+		var i ListToVerifyStudentRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Email,
+			&i.CreatedAt,
+			&i.Confirmed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockApplicationJob = `-- name: LockApplicationJob :one
+SELECT jobs.job_id
+FROM jobs
+JOIN applications ON applications.job_id = jobs.job_id
+WHERE applications.application_id = $1
+FOR UPDATE OF jobs
+`
+
+func (q *Queries) LockApplicationJob(ctx context.Context, applicationID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, lockApplicationJob, applicationID)
+	var job_id int64
+	err := row.Scan(&job_id)
+	return job_id, err
+}
+
+const lockTestEvaluation = `-- name: LockTestEvaluation :exec
+SELECT pg_advisory_xact_lock($1::BIGINT)
+`
+
+func (q *Queries) LockTestEvaluation(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, lockTestEvaluation, testID)
+	return err
+}
+
+const markSimilarityChecked = `-- name: MarkSimilarityChecked :exec
+UPDATE tests
+SET similarity_checked_at = NOW()
+WHERE tests.test_id = $1
+`
+
+func (q *Queries) MarkSimilarityChecked(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, markSimilarityChecked, testID)
+	return err
+}
+
+const newResultVersion = `-- name: NewResultVersion :one
+INSERT INTO resultversions (test_id, version, reason, threshold, cutoff_marks, total_points)
+VALUES ($1, (SELECT COALESCE(MAX(resultversions.version), 0) + 1 FROM resultversions WHERE resultversions.test_id = $1), $2, $3, $4, $5)
+RETURNING version_id, version
+`
+
+type NewResultVersionParams struct {
+	TestID      int64
+	Reason      string
+	Threshold   int32
+	CutoffMarks int64
+	TotalPoints int64
+}
+
+type NewResultVersionRow struct {
+	VersionID int64
+	Version   int32
+}
+
+func (q *Queries) NewResultVersion(ctx context.Context, arg NewResultVersionParams) (NewResultVersionRow, error) {
+	row := q.db.QueryRow(ctx, newResultVersion,
+		arg.TestID,
+		arg.Reason,
+		arg.Threshold,
+		arg.CutoffMarks,
+		arg.TotalPoints,
+	)
+	var i NewResultVersionRow
+	err := row.Scan(&i.VersionID, &i.Version)
+	return i, err
+}
+
+const newShortlistBatch = `-- name: NewShortlistBatch :one
+INSERT INTO shortlistbatches (
+    test_id
+) VALUES (
+    $1
+)
+ON CONFLICT (test_id) DO NOTHING
+RETURNING batch_id
+`
+
+func (q *Queries) NewShortlistBatch(ctx context.Context, testID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, newShortlistBatch, testID)
+	var batch_id int64
+	err := row.Scan(&batch_id)
+	return batch_id, err
+}
+
+const newTest = `-- name: NewTest :one
+INSERT INTO tests (test_name, description, duration, q_count, end_time, type, upload_method, job_id, company_id, file_id, threshold, negative_marking, partial_credit, min_score, shuffle, start_time, late_entry, grace_period)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT company_id FROM companies WHERE user_id = $9), $10, $11, $12, $13, $14, $15, $16, $17, $18)
+RETURNING test_id
+`
+
+type NewTestParams struct {
+	TestName        string
+	Description     pgtype.Text
+	Duration        int64
+	QCount          int64
+	EndTime         pgtype.Timestamptz
+	Type            string
+	UploadMethod    interface{}
+	JobID           pgtype.Int8
+	UserID          int64
+	FileID          string
+	Threshold       int32
+	NegativeMarking int32
+	PartialCredit   bool
+	MinScore        pgtype.Int4
+	Shuffle         bool
+	StartTime       pgtype.Timestamptz
+	LateEntry       pgtype.Int8
+	GracePeriod     int64
+}
+
+func (q *Queries) NewTest(ctx context.Context, arg NewTestParams) (int64, error) {
+	row := q.db.QueryRow(ctx, newTest,
+		arg.TestName,
+		arg.Description,
+		arg.Duration,
+		arg.QCount,
+		arg.EndTime,
+		arg.Type,
+		arg.UploadMethod,
+		arg.JobID,
+		arg.UserID,
+		arg.FileID,
+		arg.Threshold,
+		arg.NegativeMarking,
+		arg.PartialCredit,
+		arg.MinScore,
+		arg.Shuffle,
+		arg.StartTime,
+		arg.LateEntry,
+		arg.GracePeriod,
+	)
+	var test_id int64
+	err := row.Scan(&test_id)
+	return test_id, err
+}
+
+const newTestResult = `-- name: NewTestResult :exec
+INSERT INTO testresults (test_id, user_id, start_time, extra_minutes, override_end_time, fresh_attempt)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type NewTestResultParams struct {
+	TestID          int64
+	UserID          int64
+	StartTime       pgtype.Timestamptz
+	ExtraMinutes    int32
+	OverrideEndTime pgtype.Timestamptz
+	FreshAttempt    bool
+}
+
+func (q *Queries) NewTestResult(ctx context.Context, arg NewTestResultParams) error {
+	_, err := q.db.Exec(ctx, newTestResult,
+		arg.TestID,
+		arg.UserID,
+		arg.StartTime,
+		arg.ExtraMinutes,
+		arg.OverrideEndTime,
+		arg.FreshAttempt,
+	)
+	return err
+}
+
+const offeredApplication = `-- name: OfferedApplication :one
+SELECT 
+    applications.application_id,
+    jobs.salary
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.job_id = $1
+AND applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $2)
+AND applications.status = 'Offered'
+`
+
+type OfferedApplicationParams struct {
+	JobID  int64
+	UserID int64
+}
+
+type OfferedApplicationRow struct {
+	ApplicationID int64
+	Salary        string
+}
+
+func (q *Queries) OfferedApplication(ctx context.Context, arg OfferedApplicationParams) (OfferedApplicationRow, error) {
+	row := q.db.QueryRow(ctx, offeredApplication, arg.JobID, arg.UserID)
+	var i OfferedApplicationRow
+	err := row.Scan(&i.ApplicationID, &i.Salary)
+	return i, err
+}
+
+const openTestAttempt = `-- name: OpenTestAttempt :one
+SELECT 
+    testresults.result_id
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+WHERE testresults.test_id = $1
+AND testresults.user_id = $2
+AND testresults.start_time IS NOT NULL
+AND testresults.end_time IS NULL
+AND LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute') > NOW()
+`
+
+type OpenTestAttemptParams struct {
+	TestID int64
+	UserID int64
+}
+
+func (q *Queries) OpenTestAttempt(ctx context.Context, arg OpenTestAttemptParams) (int64, error) {
+	row := q.db.QueryRow(ctx, openTestAttempt, arg.TestID, arg.UserID)
+	var result_id int64
+	err := row.Scan(&result_id)
+	return result_id, err
+}
+
+const pendingGradingCount = `-- name: PendingGradingCount :one
+SELECT 
+    COUNT(*) AS pending
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresults.test_id = $1
+AND testresponses.needs_grading = true
+AND testresponses.graded_at IS NULL
+`
+
+func (q *Queries) PendingGradingCount(ctx context.Context, testID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, pendingGradingCount, testID)
+	var pending int64
+	err := row.Scan(&pending)
+	return pending, err
+}
+
+const placementPolicy = `-- name: PlacementPolicy :one
+SELECT 
+    block_after_accept,
+    dream_min_salary,
+    max_active_applications,
+    debar_after_declines,
+    updated_at
+FROM placementpolicy
+WHERE id = true
+`
+
+type PlacementPolicyRow struct {
+	BlockAfterAccept      bool
+	DreamMinSalary        pgtype.Float8
+	MaxActiveApplications pgtype.Int4
+	DebarAfterDeclines    pgtype.Int4
+	UpdatedAt             pgtype.Timestamptz
+}
+
+func (q *Queries) PlacementPolicy(ctx context.Context) (PlacementPolicyRow, error) {
+	row := q.db.QueryRow(ctx, placementPolicy)
+	var i PlacementPolicyRow
+	err := row.Scan(
+		&i.BlockAfterAccept,
+		&i.DreamMinSalary,
+		&i.MaxActiveApplications,
+		&i.DebarAfterDeclines,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const placementStanding = `-- name: PlacementStanding :one
+SELECT
+    CAST(COUNT(applications.application_id) FILTER (WHERE applications.status IN ('Applied', 'UnderReview', 'ShortListed', 'Offered')) AS BIGINT) AS active_applications,
+    CAST(COUNT(applications.application_id) FILTER (WHERE applications.offer_declined_at IS NOT NULL) AS BIGINT) AS declined_offers,
+    COALESCE(ARRAY_AGG(jobs.salary) FILTER (WHERE applications.status = 'Hired'), '{}')::TEXT[] AS accepted_salaries
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)
+`
+
+type PlacementStandingRow struct {
+	ActiveApplications int64
+	DeclinedOffers     int64
+	AcceptedSalaries   []string
+}
+
+func (q *Queries) PlacementStanding(ctx context.Context, userID int64) (PlacementStandingRow, error) {
+	row := q.db.QueryRow(ctx, placementStanding, userID)
+	var i PlacementStandingRow
+	err := row.Scan(&i.ActiveApplications, &i.DeclinedOffers, &i.AcceptedSalaries)
+	return i, err
+}
+
+const proctorAttemptData = `-- name: ProctorAttemptData :one
+SELECT 
+    students.student_name,
+    students.roll_number,
+    TO_CHAR(testresults.start_time, 'HH12:MI:SS AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(testresults.end_time, 'HH12:MI:SS AM DD-MM-YYYY') AS end_time
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+JOIN students ON testresults.user_id = students.user_id
+WHERE testresults.test_id = $1
+AND testresults.result_id = $2
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $3)
+`
+
+type ProctorAttemptDataParams struct {
+	TestID   int64
+	ResultID int64
+	UserID   int64
+}
+
+type ProctorAttemptDataRow struct {
+	StudentName string
+	RollNumber  string
+	StartTime   string
+	EndTime     string
+}
+
+func (q *Queries) ProctorAttemptData(ctx context.Context, arg ProctorAttemptDataParams) (ProctorAttemptDataRow, error) {
+	row := q.db.QueryRow(ctx, proctorAttemptData, arg.TestID, arg.ResultID, arg.UserID)
+	var i ProctorAttemptDataRow
+	err := row.Scan(
+		&i.StudentName,
+		&i.RollNumber,
+		&i.StartTime,
+		&i.EndTime,
+	)
+	return i, err
+}
+
+const proctorTimeline = `-- name: ProctorTimeline :many
+SELECT 
+    proctorevents.event_type,
+    proctorevents.detail,
+    proctorevents.ip_address,
+    TO_CHAR(proctorevents.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+    COALESCE(EXTRACT(EPOCH FROM proctorevents.created_at - testresults.start_time), 0)::BIGINT AS seconds_in
+FROM proctorevents
+JOIN testresults ON proctorevents.result_id = testresults.result_id
+WHERE proctorevents.result_id = $1
+ORDER BY proctorevents.created_at, proctorevents.event_id
+`
+
+type ProctorTimelineRow struct {
+	EventType string
+	Detail    pgtype.Text
+	IpAddress string
+	CreatedAt string
+	SecondsIn int64
+}
+
+func (q *Queries) ProctorTimeline(ctx context.Context, resultID int64) ([]ProctorTimelineRow, error) {
+	rows, err := q.db.Query(ctx, proctorTimeline, resultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProctorTimelineRow
+	for rows.Next() {
+		var i ProctorTimelineRow
+		if err := rows.Scan(
+			&i.EventType,
+			&i.Detail,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.SecondsIn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recomputeTestScores = `-- name: RecomputeTestScores :exec
+UPDATE testresults
+SET 
+    score = GREATEST(
+        COALESCE((SELECT SUM(testresponses.points) FROM testresponses WHERE testresponses.result_id = testresults.result_id), 0),
+        (SELECT tests.min_score FROM tests WHERE tests.test_id = testresults.test_id)
+    )
+WHERE testresults.test_id = $1
+`
+
+func (q *Queries) RecomputeTestScores(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, recomputeTestScores, testID)
+	return err
+}
+
+const resetTestPoints = `-- name: ResetTestPoints :exec
+UPDATE testresponses
+SET 
+    points = NULL,
+    max_points = NULL,
+    needs_grading = false
+FROM testresults
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND testresponses.graded_at IS NULL
+`
+
+func (q *Queries) ResetTestPoints(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, resetTestPoints, testID)
+	return err
+}
+
+const resetTestResultURL = `-- name: ResetTestResultURL :exec
+UPDATE tests
+SET result_url = NULL,
+    similarity_checked_at = NULL
+WHERE tests.test_id = $1
+AND tests.published = false
+`
+
+func (q *Queries) ResetTestResultURL(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, resetTestResultURL, testID)
+	return err
+}
+
+const respondToOffer = `-- name: RespondToOffer :one
+WITH upd AS (
+    UPDATE applications
+    SET status = CASE WHEN $1::BOOLEAN THEN 'Hired' ELSE 'Rejected' END::application_status,
+        offer_declined_at = CASE WHEN $1::BOOLEAN THEN NULL ELSE NOW() END
+    WHERE application_id = $2
+    AND status = 'Offered'
+    RETURNING application_id, job_id
+)
+SELECT 
+    upd.application_id,
+    jobs.title,
+    companies.user_id
+FROM upd
+JOIN jobs ON jobs.job_id = upd.job_id
+JOIN companies ON companies.company_id = jobs.company_id
+`
+
+type RespondToOfferParams struct {
+	Accept        bool
+	ApplicationID int64
+}
+
+type RespondToOfferRow struct {
+	ApplicationID int64
+	Title         string
+	UserID        int64
+}
+
+func (q *Queries) RespondToOffer(ctx context.Context, arg RespondToOfferParams) (RespondToOfferRow, error) {
+	row := q.db.QueryRow(ctx, respondToOffer, arg.Accept, arg.ApplicationID)
+	var i RespondToOfferRow
+	err := row.Scan(&i.ApplicationID, &i.Title, &i.UserID)
+	return i, err
+}
+
+const responseForGrading = `-- name: ResponseForGrading :one
+SELECT 
+    testresponses.needs_grading,
+    testresponses.max_points
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresponses.response_id = $1
+AND testresults.test_id = $2
+`
+
+type ResponseForGradingParams struct {
+	ResponseID int64
+	TestID     int64
+}
+
+type ResponseForGradingRow struct {
+	NeedsGrading bool
+	MaxPoints    pgtype.Int4
+}
+
+func (q *Queries) ResponseForGrading(ctx context.Context, arg ResponseForGradingParams) (ResponseForGradingRow, error) {
+	row := q.db.QueryRow(ctx, responseForGrading, arg.ResponseID, arg.TestID)
+	var i ResponseForGradingRow
+	err := row.Scan(&i.NeedsGrading, &i.MaxPoints)
+	return i, err
+}
+
+const resultVersionDiff = `-- name: ResultVersionDiff :many
+WITH old AS (
+    SELECT resultversionscores.user_id, resultversionscores.score, resultversionscores.passed
+    FROM resultversionscores
+    JOIN resultversions ON resultversionscores.version_id = resultversions.version_id
+    WHERE resultversions.test_id = $1
+    AND resultversions.version = $2
+),
+new AS (
+    SELECT resultversionscores.user_id, resultversionscores.score, resultversionscores.passed
+    FROM resultversionscores
+    JOIN resultversions ON resultversionscores.version_id = resultversions.version_id
+    WHERE resultversions.test_id = $1
+    AND resultversions.version = $3
+)
+SELECT 
+    students.student_id,
+    students.student_name,
+    students.roll_number,
+    old.score AS old_score,
+    new.score AS new_score,
+    old.passed AS old_passed,
+    new.passed AS new_passed
+FROM old
+FULL JOIN new ON old.user_id = new.user_id
+JOIN students ON students.user_id = COALESCE(old.user_id, new.user_id)
+WHERE old.passed IS DISTINCT FROM new.passed
+ORDER BY new.passed NULLS LAST, students.roll_number
+`
+
+type ResultVersionDiffParams struct {
+	TestID      int64
+	FromVersion int32
+	ToVersion   int32
+}
+
+type ResultVersionDiffRow struct {
+	StudentID   int64
+	StudentName string
+	RollNumber  string
+	OldScore    pgtype.Int8
+	NewScore    pgtype.Int8
+	OldPassed   pgtype.Bool
+	NewPassed   pgtype.Bool
+}
+
+func (q *Queries) ResultVersionDiff(ctx context.Context, arg ResultVersionDiffParams) ([]ResultVersionDiffRow, error) {
+	rows, err := q.db.Query(ctx, resultVersionDiff, arg.TestID, arg.FromVersion, arg.ToVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResultVersionDiffRow
+	for rows.Next() {
+		var i ResultVersionDiffRow
+		if err := rows.Scan(
+			&i.StudentID,
+			&i.StudentName,
+			&i.RollNumber,
+			&i.OldScore,
+			&i.NewScore,
+			&i.OldPassed,
+			&i.NewPassed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resultVersionExists = `-- name: ResultVersionExists :one
+SELECT 
+    resultversions.version_id
+FROM resultversions
+WHERE resultversions.test_id = $1
+AND resultversions.version = $2
+`
+
+type ResultVersionExistsParams struct {
+	TestID  int64
+	Version int32
+}
+
+func (q *Queries) ResultVersionExists(ctx context.Context, arg ResultVersionExistsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, resultVersionExists, arg.TestID, arg.Version)
+	var version_id int64
+	err := row.Scan(&version_id)
+	return version_id, err
+}
+
+const scheduleInterview = `-- name: ScheduleInterview :one
+INSERT INTO interviews (application_id, company_id, date_time, type, notes, location)
+VALUES ($1, (SELECT company_id FROM companies WHERE user_id = $2), $3, $4, $5, $6)
+RETURNING TO_CHAR(date_time, 'HH12:MI AM DD-MM-YYYY') AS date_time
+`
+
+type ScheduleInterviewParams struct {
+	ApplicationID int64
+	UserID        int64
+	DateTime      pgtype.Timestamptz
+	Type          interface{}
+	Notes         pgtype.Text
+	Location      string
+}
+
+func (q *Queries) ScheduleInterview(ctx context.Context, arg ScheduleInterviewParams) (string, error) {
+	row := q.db.QueryRow(ctx, scheduleInterview,
+		arg.ApplicationID,
+		arg.UserID,
+		arg.DateTime,
+		arg.Type,
+		arg.Notes,
+		arg.Location,
+	)
+	var date_time string
+	err := row.Scan(&date_time)
+	return date_time, err
+}
+
+const scheduledInterviewsCompany = `-- name: ScheduledInterviewsCompany :many
+SELECT 
+    interviews.interview_id,
+    TO_CHAR(interviews.date_time, 'HH12:MI AM DD-MM-YYYY') AS date_time,
+    interviews.type::TEXT,
+    interviews.status::TEXT,
+    interviews.notes,
+    interviews.location,
+    interviews.extras,
+    applications.application_id,
+    applications.job_id,
+    students.student_id,
+    students.student_name,
+    students.roll_number
+FROM interviews
+JOIN applications ON interviews.application_id = applications.application_id
+JOIN students ON applications.student_id = students.student_id
+WHERE interviews.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+AND interviews.status = 'Scheduled' AND interviews.date_time > NOW()
+ORDER BY interviews.date_time
+`
+
+type ScheduledInterviewsCompanyRow struct {
+	InterviewID      int64
+	DateTime         string
+	InterviewsType   string
+	InterviewsStatus string
+	Notes            pgtype.Text
+	Location         string
+	Extras           []byte
+	ApplicationID    int64
+	JobID            int64
+	StudentID        int64
+	StudentName      string
+	RollNumber       string
+}
+
+func (q *Queries) ScheduledInterviewsCompany(ctx context.Context, userID int64) ([]ScheduledInterviewsCompanyRow, error) {
+	rows, err := q.db.Query(ctx, scheduledInterviewsCompany, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledInterviewsCompanyRow
+	for rows.Next() {
+		var i ScheduledInterviewsCompanyRow
+		if err := rows.Scan(
+			&i.InterviewID,
+			&i.DateTime,
+			&i.InterviewsType,
+			&i.InterviewsStatus,
+			&i.Notes,
+			&i.Location,
+			&i.Extras,
+			&i.ApplicationID,
+			&i.JobID,
+			&i.StudentID,
+			&i.StudentName,
+			&i.RollNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const scheduledTestsCompany = `-- name: ScheduledTestsCompany :many
+SELECT 
+    tests.test_id,
+    tests.test_name,
+    tests.description,
+    tests.duration,
+    tests.q_count,
+    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    tests.type,
+    tests.threshold,
+    jobs.job_id,
+    jobs.title
+FROM tests
+JOIN jobs ON tests.job_id = jobs.job_id
+WHERE tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+AND tests.end_time > NOW()
+ORDER BY tests.end_time
+`
+
+type ScheduledTestsCompanyRow struct {
+	TestID      int64
+	TestName    string
+	Description pgtype.Text
+	Duration    int64
+	QCount      int64
+	EndTime     string
+	Type        string
+	Threshold   int32
+	JobID       int64
+	Title       string
+}
+
+func (q *Queries) ScheduledTestsCompany(ctx context.Context, userID int64) ([]ScheduledTestsCompanyRow, error) {
+	rows, err := q.db.Query(ctx, scheduledTestsCompany, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledTestsCompanyRow
+	for rows.Next() {
+		var i ScheduledTestsCompanyRow
+		if err := rows.Scan(
+			&i.TestID,
+			&i.TestName,
+			&i.Description,
+			&i.Duration,
+			&i.QCount,
+			&i.EndTime,
+			&i.Type,
+			&i.Threshold,
+			&i.JobID,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sectionPassFailCount = `-- name: SectionPassFailCount :many
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.name,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = $1
+),
+scores AS (
+    SELECT 
+        testresults.result_id,
+        sections.section,
+        COALESCE(SUM(testresponses.points), 0) AS score
+    FROM testresults
+    CROSS JOIN sections
+    LEFT JOIN testresponses ON (testresponses.result_id = testresults.result_id AND testresponses.section = sections.section)
+    WHERE testresults.test_id = $1
+    GROUP BY testresults.result_id, sections.section
+)
+SELECT 
+    sections.section,
+    sections.name,
+    sections.min_score,
+    COUNT(CASE WHEN scores.result_id IS NOT NULL AND (sections.min_score IS NULL OR scores.score >= sections.min_score) THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN scores.score < sections.min_score THEN 1 ELSE NULL END) AS fail_count
+FROM sections
+LEFT JOIN scores ON scores.section = sections.section
+GROUP BY sections.section, sections.name, sections.min_score
+ORDER BY sections.section
+`
+
+type SectionPassFailCountRow struct {
+	Section   int32
+	Name      string
+	MinScore  pgtype.Int4
+	PassCount int64
+	FailCount int64
+}
+
+func (q *Queries) SectionPassFailCount(ctx context.Context, testID int64) ([]SectionPassFailCountRow, error) {
+	rows, err := q.db.Query(ctx, sectionPassFailCount, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SectionPassFailCountRow
+	for rows.Next() {
+		var i SectionPassFailCountRow
+		if err := rows.Scan(
+			&i.Section,
+			&i.Name,
+			&i.MinScore,
+			&i.PassCount,
+			&i.FailCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setApplicationStage = `-- name: SetApplicationStage :one
+WITH upd AS (
+    UPDATE applications
+    SET stage_id = $1
+    WHERE application_id = $2
+    AND status = 'ShortListed'
+    AND stage_id IS NOT DISTINCT FROM $3
+    RETURNING student_id
+)
+SELECT
+    students.user_id
+FROM students 
+JOIN upd ON students.student_id = upd.student_id
+`
+
+type SetApplicationStageParams struct {
+	StageID        int64
+	ApplicationID  int64
+	CurrentStageID pgtype.Int8
+}
+
+func (q *Queries) SetApplicationStage(ctx context.Context, arg SetApplicationStageParams) (int64, error) {
+	row := q.db.QueryRow(ctx, setApplicationStage, arg.StageID, arg.ApplicationID, arg.CurrentStageID)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const setAutoShortlist = `-- name: SetAutoShortlist :exec
+UPDATE tests
+SET auto_shortlist = $2
+WHERE tests.test_id = $1
+`
+
+type SetAutoShortlistParams struct {
+	TestID        int64
+	AutoShortlist bool
+}
+
+func (q *Queries) SetAutoShortlist(ctx context.Context, arg SetAutoShortlistParams) error {
+	_, err := q.db.Exec(ctx, setAutoShortlist, arg.TestID, arg.AutoShortlist)
+	return err
+}
+
+const setDrivePageToken = `-- name: SetDrivePageToken :exec
+INSERT INTO drivestate (
+    id, page_token
+) VALUES (
+    true, $1
+)
+ON CONFLICT (id) DO UPDATE
+SET page_token = EXCLUDED.page_token,
+    updated_at = NOW()
+`
+
+func (q *Queries) SetDrivePageToken(ctx context.Context, pageToken string) error {
+	_, err := q.db.Exec(ctx, setDrivePageToken, pageToken)
+	return err
+}
+
+const setPlacementPolicy = `-- name: SetPlacementPolicy :exec
+INSERT INTO placementpolicy (
+    id, block_after_accept, dream_min_salary, max_active_applications, debar_after_declines
+) VALUES (
+    true, $1, $2, $3, $4
+)
+ON CONFLICT (id) DO UPDATE
+SET block_after_accept = EXCLUDED.block_after_accept,
+    dream_min_salary = EXCLUDED.dream_min_salary,
+    max_active_applications = EXCLUDED.max_active_applications,
+    debar_after_declines = EXCLUDED.debar_after_declines,
+    updated_at = NOW()
+`
+
+type SetPlacementPolicyParams struct {
+	BlockAfterAccept      bool
+	DreamMinSalary        pgtype.Float8
+	MaxActiveApplications pgtype.Int4
+	DebarAfterDeclines    pgtype.Int4
+}
+
+func (q *Queries) SetPlacementPolicy(ctx context.Context, arg SetPlacementPolicyParams) error {
+	_, err := q.db.Exec(ctx, setPlacementPolicy,
+		arg.BlockAfterAccept,
+		arg.DreamMinSalary,
+		arg.MaxActiveApplications,
+		arg.DebarAfterDeclines,
+	)
+	return err
+}
+
+const sharedFormByLink = `-- name: SharedFormByLink :one
+SELECT 
+    sharedforms.form_id
+FROM sharedforms
+WHERE sharedforms.responder_uri = $1
+`
+
+func (q *Queries) SharedFormByLink(ctx context.Context, responderUri string) (string, error) {
+	row := q.db.QueryRow(ctx, sharedFormByLink, responderUri)
+	var form_id string
+	err := row.Scan(&form_id)
+	return form_id, err
+}
+
+const shortlistPreview = `-- name: ShortlistPreview :many
+WITH latest AS (
+    SELECT 
+        resultversions.version_id
+    FROM resultversions
+    WHERE resultversions.test_id = $1
+    ORDER BY resultversions.version DESC
+    LIMIT 1
+)
+SELECT 
     applications.application_id,
-    applications.job_id,
-    students.student_id,
+    applications.status::TEXT AS status,
     students.student_name,
-    students.roll_number
-FROM interviews
-JOIN applications ON interviews.application_id = applications.application_id
+    students.roll_number,
+    resultversionscores.score,
+    COALESCE(resultversionscores.passed, false)::BOOLEAN AS passed
+FROM tests
+JOIN applications ON applications.job_id = tests.job_id
 JOIN students ON applications.student_id = students.student_id
-WHERE interviews.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
-AND interviews.status = 'Scheduled' AND interviews.date_time > NOW()
-ORDER BY interviews.date_time
+LEFT JOIN resultversionscores ON resultversionscores.version_id = (SELECT latest.version_id FROM latest)
+    AND resultversionscores.user_id = students.user_id
+WHERE tests.test_id = $1
+AND applications.status IN ('Applied', 'UnderReview')
+ORDER BY COALESCE(resultversionscores.passed, false) DESC, resultversionscores.score DESC NULLS LAST, applications.application_id
 `
 
-type ScheduledInterviewsCompanyRow struct {
-	InterviewID      int64
-	DateTime         string
-	InterviewsType   string
-	InterviewsStatus string
-	Notes            pgtype.Text
-	Location         string
-	Extras           []byte
-	ApplicationID    int64
-	JobID            int64
-	StudentID        int64
-	StudentName      string
-	RollNumber       string
+type ShortlistPreviewRow struct {
+	ApplicationID int64
+	Status        string
+	StudentName   string
+	RollNumber    string
+	Score         pgtype.Int8
+	Passed        bool
 }
 
-func (q *Queries) ScheduledInterviewsCompany(ctx context.Context, userID int64) ([]ScheduledInterviewsCompanyRow, error) {
-	rows, err := q.db.Query(ctx, scheduledInterviewsCompany, userID)
+func (q *Queries) ShortlistPreview(ctx context.Context, testID int64) ([]ShortlistPreviewRow, error) {
+	rows, err := q.db.Query(ctx, shortlistPreview, testID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ScheduledInterviewsCompanyRow
+	var items []ShortlistPreviewRow
 	for rows.Next() {
-		var i ScheduledInterviewsCompanyRow
+		var i ShortlistPreviewRow
 		if err := rows.Scan(
-			&i.InterviewID,
-			&i.DateTime,
-			&i.InterviewsType,
-			&i.InterviewsStatus,
-			&i.Notes,
-			&i.Location,
-			&i.Extras,
 			&i.ApplicationID,
-			&i.JobID,
-			&i.StudentID,
+			&i.Status,
 			&i.StudentName,
 			&i.RollNumber,
+			&i.Score,
+			&i.Passed,
 		); err != nil {
 			return nil, err
 		}
@@ -2374,67 +5120,45 @@ func (q *Queries) ScheduledInterviewsCompany(ctx context.Context, userID int64)
 	return items, nil
 }
 
-const scheduledTestsCompany = `-- name: ScheduledTestsCompany :many
+const shuffleAuditData = `-- name: ShuffleAuditData :one
 SELECT 
-    tests.test_id,
-    tests.test_name,
-    tests.description,
-    tests.duration,
-    tests.q_count,
-    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
-    tests.type,
-    tests.threshold,
-    jobs.job_id,
-    jobs.title
+    tests.file_id,
+    tests.upload_method::TEXT AS upload_method,
+    tests.shuffle,
+    tests.shuffle_seed,
+    testresults.user_id
 FROM tests
-JOIN jobs ON tests.job_id = jobs.job_id
-WHERE tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
-AND tests.end_time > NOW()
-ORDER BY tests.end_time
+JOIN testresults ON tests.test_id = testresults.test_id
+WHERE tests.test_id = $1
+AND testresults.result_id = $2
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $3)
 `
 
-type ScheduledTestsCompanyRow struct {
-	TestID      int64
-	TestName    string
-	Description pgtype.Text
-	Duration    int64
-	QCount      int64
-	EndTime     string
-	Type        string
-	Threshold   int32
-	JobID       int64
-	Title       string
+type ShuffleAuditDataParams struct {
+	TestID   int64
+	ResultID int64
+	UserID   int64
 }
 
-func (q *Queries) ScheduledTestsCompany(ctx context.Context, userID int64) ([]ScheduledTestsCompanyRow, error) {
-	rows, err := q.db.Query(ctx, scheduledTestsCompany, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []ScheduledTestsCompanyRow
-	for rows.Next() {
-		var i ScheduledTestsCompanyRow
-		if err := rows.Scan(
-			&i.TestID,
-			&i.TestName,
-			&i.Description,
-			&i.Duration,
-			&i.QCount,
-			&i.EndTime,
-			&i.Type,
-			&i.Threshold,
-			&i.JobID,
-			&i.Title,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type ShuffleAuditDataRow struct {
+	FileID       string
+	UploadMethod string
+	Shuffle      bool
+	ShuffleSeed  int64
+	UserID       int64
+}
+
+func (q *Queries) ShuffleAuditData(ctx context.Context, arg ShuffleAuditDataParams) (ShuffleAuditDataRow, error) {
+	row := q.db.QueryRow(ctx, shuffleAuditData, arg.TestID, arg.ResultID, arg.UserID)
+	var i ShuffleAuditDataRow
+	err := row.Scan(
+		&i.FileID,
+		&i.UploadMethod,
+		&i.Shuffle,
+		&i.ShuffleSeed,
+		&i.UserID,
+	)
+	return i, err
 }
 
 const signupUser = `-- name: SignupUser :one
@@ -2464,6 +5188,193 @@ func (q *Queries) SignupUser(ctx context.Context, arg SignupUserParams) (User, e
 	return i, err
 }
 
+const similarityFlags = `-- name: SimilarityFlags :many
+SELECT 
+    student_a.student_name AS student_name_a,
+    student_a.roll_number AS roll_number_a,
+    student_b.student_name AS student_name_b,
+    student_b.roll_number AS roll_number_b,
+    similarityflags.common_questions,
+    similarityflags.shared_wrong,
+    similarityflags.time_difference
+FROM similarityflags
+JOIN testresults AS result_a ON similarityflags.result_a = result_a.result_id
+JOIN testresults AS result_b ON similarityflags.result_b = result_b.result_id
+JOIN students AS student_a ON result_a.user_id = student_a.user_id
+JOIN students AS student_b ON result_b.user_id = student_b.user_id
+WHERE similarityflags.test_id = $1
+ORDER BY similarityflags.shared_wrong DESC, similarityflags.time_difference NULLS LAST, similarityflags.flag_id
+`
+
+type SimilarityFlagsRow struct {
+	StudentNameA    string
+	RollNumberA     string
+	StudentNameB    string
+	RollNumberB     string
+	CommonQuestions int32
+	SharedWrong     int32
+	TimeDifference  pgtype.Float8
+}
+
+func (q *Queries) SimilarityFlags(ctx context.Context, testID int64) ([]SimilarityFlagsRow, error) {
+	rows, err := q.db.Query(ctx, similarityFlags, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SimilarityFlagsRow
+	for rows.Next() {
+		var i SimilarityFlagsRow
+		if err := rows.Scan(
+			&i.StudentNameA,
+			&i.RollNumberA,
+			&i.StudentNameB,
+			&i.RollNumberB,
+			&i.CommonQuestions,
+			&i.SharedWrong,
+			&i.TimeDifference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const similarityPoller = `-- name: SimilarityPoller :many
+SELECT  
+    tests.test_id
+FROM tests
+WHERE tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
+AND tests.similarity_checked_at IS NULL
+AND tests.result_url IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+)
+ORDER BY tests.end_time
+`
+
+func (q *Queries) SimilarityPoller(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.Query(ctx, similarityPoller)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var test_id int64
+		if err := rows.Scan(&test_id); err != nil {
+			return nil, err
+		}
+		items = append(items, test_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const similarityResponses = `-- name: SimilarityResponses :many
+SELECT 
+    testresponses.result_id,
+    testresponses.question_id,
+    testresponses.response,
+    testresponses.time_taken
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
+ORDER BY testresponses.result_id, testresponses.question_id
+`
+
+type SimilarityResponsesRow struct {
+	ResultID   int64
+	QuestionID string
+	Response   []string
+	TimeTaken  pgtype.Int8
+}
+
+func (q *Queries) SimilarityResponses(ctx context.Context, testID int64) ([]SimilarityResponsesRow, error) {
+	rows, err := q.db.Query(ctx, similarityResponses, testID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SimilarityResponsesRow
+	for rows.Next() {
+		var i SimilarityResponsesRow
+		if err := rows.Scan(
+			&i.ResultID,
+			&i.QuestionID,
+			&i.Response,
+			&i.TimeTaken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const snapshotResultScores = `-- name: SnapshotResultScores :exec
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = $1
+),
+failed AS (
+    SELECT DISTINCT 
+        testresults.result_id
+    FROM testresults
+    CROSS JOIN sections
+    WHERE testresults.test_id = $1
+    AND sections.min_score IS NOT NULL
+    AND COALESCE((
+        SELECT SUM(testresponses.points) 
+        FROM testresponses 
+        WHERE testresponses.result_id = testresults.result_id 
+        AND testresponses.section = sections.section
+    ), 0) < sections.min_score
+)
+INSERT INTO resultversionscores (version_id, user_id, score, passed)
+SELECT 
+    $2,
+    testresults.user_id,
+    testresults.score,
+    (COALESCE(testresults.score, 0) >= $3::BIGINT AND failed.result_id IS NULL)
+FROM testresults
+LEFT JOIN failed ON testresults.result_id = failed.result_id
+WHERE testresults.test_id = $1
+`
+
+type SnapshotResultScoresParams struct {
+	TestID      int64
+	VersionID   int64
+	CutoffMarks int64
+}
+
+func (q *Queries) SnapshotResultScores(ctx context.Context, arg SnapshotResultScoresParams) error {
+	_, err := q.db.Exec(ctx, snapshotResultScores, arg.TestID, arg.VersionID, arg.CutoffMarks)
+	return err
+}
+
 const studentDashboardData = `-- name: StudentDashboardData :one
 WITH st AS (
     SELECT 
@@ -2496,6 +5407,33 @@ func (q *Queries) StudentDashboardData(ctx context.Context, userID int64) (Stude
 	return i, err
 }
 
+const studentEligibility = `-- name: StudentEligibility :one
+SELECT cgpa, department, course, year_of_study, gender
+FROM students
+WHERE user_id = $1
+`
+
+type StudentEligibilityRow struct {
+	Cgpa        pgtype.Float8
+	Department  string
+	Course      string
+	YearOfStudy string
+	Gender      string
+}
+
+func (q *Queries) StudentEligibility(ctx context.Context, userID int64) (StudentEligibilityRow, error) {
+	row := q.db.QueryRow(ctx, studentEligibility, userID)
+	var i StudentEligibilityRow
+	err := row.Scan(
+		&i.Cgpa,
+		&i.Department,
+		&i.Course,
+		&i.YearOfStudy,
+		&i.Gender,
+	)
+	return i, err
+}
+
 const studentInfo = `-- name: StudentInfo :one
 SELECT
     students.student_id,
@@ -2681,7 +5619,11 @@ WITH tr AS (
         result_id,
         SUM(time_taken) AS total_time_taken,
         COUNT(result_id) AS questions_attempted,
-        COUNT(CASE WHEN points > 0 THEN 1 ELSE NULL END) AS correct_response
+        COUNT(CASE WHEN points > 0 AND points >= COALESCE(max_points, points) THEN 1 ELSE NULL END) AS correct_response,
+        COUNT(CASE WHEN points > 0 AND points < max_points THEN 1 ELSE NULL END) AS partial_response,
+        COUNT(CASE WHEN points <= 0 THEN 1 ELSE NULL END) AS wrong_response,
+        COALESCE(SUM(CASE WHEN points > 0 THEN points ELSE 0 END), 0)::BIGINT AS points_earned,
+        COALESCE(SUM(CASE WHEN points < 0 THEN -points ELSE 0 END), 0)::BIGINT AS points_deducted
     FROM testresponses
     GROUP BY result_id
 )
@@ -2699,6 +5641,14 @@ SELECT
     tr.total_time_taken,
     tr.questions_attempted,
     tr.correct_response,
+    tr.partial_response,
+    tr.wrong_response,
+    tr.points_earned,
+    tr.points_deducted,
+
+    testresults.extra_minutes,
+    COALESCE(TO_CHAR(testresults.override_end_time, 'HH12:MI AM DD-MM-YYYY'), '')::TEXT AS override_end_time,
+    testresults.fresh_attempt,
 
     users.user_uuid
 FROM testresults
@@ -2720,6 +5670,13 @@ type StudentTestResultRow struct {
 	TotalTimeTaken     int64
 	QuestionsAttempted int64
 	CorrectResponse    int64
+	PartialResponse    int64
+	WrongResponse      int64
+	PointsEarned       int64
+	PointsDeducted     int64
+	ExtraMinutes       int32
+	OverrideEndTime    string
+	FreshAttempt       bool
 	UserUuid           pgtype.UUID
 }
 
@@ -2744,6 +5701,13 @@ func (q *Queries) StudentTestResult(ctx context.Context, testID int64) ([]Studen
 			&i.TotalTimeTaken,
 			&i.QuestionsAttempted,
 			&i.CorrectResponse,
+			&i.PartialResponse,
+			&i.WrongResponse,
+			&i.PointsEarned,
+			&i.PointsDeducted,
+			&i.ExtraMinutes,
+			&i.OverrideEndTime,
+			&i.FreshAttempt,
 			&i.UserUuid,
 		); err != nil {
 			return nil, err
@@ -2829,6 +5793,7 @@ UPDATE testresults
 SET end_time = $1
 WHERE user_id = $2 
 AND test_id = $3
+AND end_time IS NULL
 RETURNING result_id
 `
 
@@ -2845,13 +5810,72 @@ func (q *Queries) SubmitTest(ctx context.Context, arg SubmitTestParams) (int64,
 	return result_id, err
 }
 
+const syncDrawPool = `-- name: SyncDrawPool :exec
+WITH cleared AS (
+    DELETE FROM testquestions
+    WHERE testquestions.test_id = $1
+    AND testquestions.rule_id IS NOT NULL
+)
+INSERT INTO testquestions (test_id, position, type, title, description, options, correct_answer, points, section_id, rule_id)
+SELECT DISTINCT ON (bankquestions.question_id)
+    testdrawrules.test_id,
+    testdrawrules.position,
+    bankquestions.type,
+    bankquestions.title,
+    bankquestions.description,
+    bankquestions.options,
+    bankquestions.correct_answer,
+    testdrawrules.points,
+    testdrawrules.section_id,
+    testdrawrules.rule_id
+FROM testdrawrules
+JOIN tests ON testdrawrules.test_id = tests.test_id
+JOIN bankquestions ON bankquestions.company_id = tests.company_id
+WHERE testdrawrules.test_id = $1
+AND (CARDINALITY(testdrawrules.tags) = 0 OR bankquestions.tags && testdrawrules.tags)
+AND (testdrawrules.difficulty IS NULL OR bankquestions.difficulty = testdrawrules.difficulty)
+ORDER BY bankquestions.question_id, testdrawrules.position, testdrawrules.rule_id
+`
+
+func (q *Queries) SyncDrawPool(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, syncDrawPool, testID)
+	return err
+}
+
+const syncTestQCount = `-- name: SyncTestQCount :exec
+UPDATE tests
+SET 
+    q_count = (SELECT COUNT(*) FROM testquestions WHERE testquestions.test_id = $1 AND testquestions.rule_id IS NULL)
+        + (
+            SELECT COALESCE(SUM(LEAST(testdrawrules.count, (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id))), 0) 
+            FROM testdrawrules 
+            WHERE testdrawrules.test_id = $1
+        )
+WHERE tests.test_id = $1
+`
+
+func (q *Queries) SyncTestQCount(ctx context.Context, testID int64) error {
+	_, err := q.db.Exec(ctx, syncTestQCount, testID)
+	return err
+}
+
 const takeTest = `-- name: TakeTest :one
 SELECT 
     tests.file_id,
     tests.duration,
-    tests.end_time
+    tests.end_time,
+    tests.upload_method::TEXT AS upload_method,
+    tests.shuffle,
+    tests.shuffle_seed,
+    tests.start_time,
+    tests.late_entry,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
+    testoverrides.end_time AS override_end_time,
+    COALESCE(testoverrides.fresh_attempt, false)::BOOLEAN AS fresh_attempt
 FROM tests
 JOIN applications ON applications.job_id = tests.job_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE user_id = $1)
 AND tests.test_id = $2
 `
@@ -2862,25 +5886,60 @@ type TakeTestParams struct {
 }
 
 type TakeTestRow struct {
-	FileID   string
-	Duration int64
-	EndTime  pgtype.Timestamptz
+	FileID          string
+	Duration        int64
+	EndTime         pgtype.Timestamptz
+	UploadMethod    string
+	Shuffle         bool
+	ShuffleSeed     int64
+	StartTime       pgtype.Timestamptz
+	LateEntry       pgtype.Int8
+	GracePeriod     int64
+	ExtraMinutes    int32
+	OverrideEndTime pgtype.Timestamptz
+	FreshAttempt    bool
 }
 
 func (q *Queries) TakeTest(ctx context.Context, arg TakeTestParams) (TakeTestRow, error) {
 	row := q.db.QueryRow(ctx, takeTest, arg.UserID, arg.TestID)
 	var i TakeTestRow
-	err := row.Scan(&i.FileID, &i.Duration, &i.EndTime)
+	err := row.Scan(
+		&i.FileID,
+		&i.Duration,
+		&i.EndTime,
+		&i.UploadMethod,
+		&i.Shuffle,
+		&i.ShuffleSeed,
+		&i.StartTime,
+		&i.LateEntry,
+		&i.GracePeriod,
+		&i.ExtraMinutes,
+		&i.OverrideEndTime,
+		&i.FreshAttempt,
+	)
 	return i, err
 }
 
 const testAuthorization = `-- name: TestAuthorization :one
 SELECT 
-    tests.test_id
+    tests.test_id,
+    (tests.similarity_checked_at IS NOT NULL)::BOOLEAN AS similarity_checked
 FROM tests
 WHERE tests.test_id = $1
 AND tests.company_id = (SELECT company_id FROM companies WHERE companies.user_id = $2)
-AND tests.end_time < NOW()
+AND tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+)
 `
 
 type TestAuthorizationParams struct {
@@ -2888,21 +5947,44 @@ type TestAuthorizationParams struct {
 	UserID int64
 }
 
-func (q *Queries) TestAuthorization(ctx context.Context, arg TestAuthorizationParams) (int64, error) {
-	row := q.db.QueryRow(ctx, testAuthorization, arg.TestID, arg.UserID)
-	var test_id int64
-	err := row.Scan(&test_id)
-	return test_id, err
+type TestAuthorizationRow struct {
+	TestID            int64
+	SimilarityChecked bool
+}
+
+func (q *Queries) TestAuthorization(ctx context.Context, arg TestAuthorizationParams) (TestAuthorizationRow, error) {
+	row := q.db.QueryRow(ctx, testAuthorization, arg.TestID, arg.UserID)
+	var i TestAuthorizationRow
+	err := row.Scan(&i.TestID, &i.SimilarityChecked)
+	return i, err
+}
+
+const testAutoShortlist = `-- name: TestAutoShortlist :one
+SELECT 
+    tests.auto_shortlist
+FROM tests
+WHERE tests.test_id = $1
+`
+
+func (q *Queries) TestAutoShortlist(ctx context.Context, testID int64) (bool, error) {
+	row := q.db.QueryRow(ctx, testAutoShortlist, testID)
+	var auto_shortlist bool
+	err := row.Scan(&auto_shortlist)
+	return auto_shortlist, err
 }
 
 const testData = `-- name: TestData :one
 SELECT 
     tests.file_id,
+    tests.upload_method::TEXT AS upload_method,
     tests.test_id,
     tests.test_name,
     tests.q_count,
     TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
     tests.threshold,
+    tests.negative_marking,
+    tests.partial_credit,
+    tests.min_score,
     jobs.title,
     companies.company_name,
     companies.representative_email
@@ -2914,11 +5996,15 @@ WHERE tests.test_id = $1
 
 type TestDataRow struct {
 	FileID              string
+	UploadMethod        string
 	TestID              int64
 	TestName            string
 	QCount              int64
 	EndTime             string
 	Threshold           int32
+	NegativeMarking     int32
+	PartialCredit       bool
+	MinScore            pgtype.Int4
 	Title               string
 	CompanyName         string
 	RepresentativeEmail string
@@ -2929,11 +6015,15 @@ func (q *Queries) TestData(ctx context.Context, testID int64) (TestDataRow, erro
 	var i TestDataRow
 	err := row.Scan(
 		&i.FileID,
+		&i.UploadMethod,
 		&i.TestID,
 		&i.TestName,
 		&i.QCount,
 		&i.EndTime,
 		&i.Threshold,
+		&i.NegativeMarking,
+		&i.PartialCredit,
+		&i.MinScore,
 		&i.Title,
 		&i.CompanyName,
 		&i.RepresentativeEmail,
@@ -2985,7 +6075,11 @@ SELECT
     tests.description,
     tests.duration,
     tests.q_count,
-    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(tests.start_time, 'HH12:MI AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, tests.end_time), 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, LEAST(tests.start_time + tests.late_entry * INTERVAL '1 minute', tests.end_time)), 'HH12:MI AM DD-MM-YYYY') AS entry_closes,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
     tests.type,    
     companies.company_name,
     jobs.title
@@ -2993,6 +6087,7 @@ FROM applications
 JOIN tests ON applications.job_id = tests.job_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)
 AND tests.test_id = $2
 `
@@ -3003,15 +6098,19 @@ type TestMetadataParams struct {
 }
 
 type TestMetadataRow struct {
-	TestID      int64
-	TestName    string
-	Description pgtype.Text
-	Duration    int64
-	QCount      int64
-	EndTime     string
-	Type        string
-	CompanyName string
-	Title       string
+	TestID       int64
+	TestName     string
+	Description  pgtype.Text
+	Duration     int64
+	QCount       int64
+	StartTime    string
+	EndTime      string
+	EntryCloses  string
+	GracePeriod  int64
+	ExtraMinutes int32
+	Type         string
+	CompanyName  string
+	Title        string
 }
 
 func (q *Queries) TestMetadata(ctx context.Context, arg TestMetadataParams) (TestMetadataRow, error) {
@@ -3023,7 +6122,11 @@ func (q *Queries) TestMetadata(ctx context.Context, arg TestMetadataParams) (Tes
 		&i.Description,
 		&i.Duration,
 		&i.QCount,
+		&i.StartTime,
 		&i.EndTime,
+		&i.EntryCloses,
+		&i.GracePeriod,
+		&i.ExtraMinutes,
 		&i.Type,
 		&i.CompanyName,
 		&i.Title,
@@ -3031,11 +6134,71 @@ func (q *Queries) TestMetadata(ctx context.Context, arg TestMetadataParams) (Tes
 	return i, err
 }
 
+const testOverrideTarget = `-- name: TestOverrideTarget :one
+SELECT 
+    students.user_id,
+    tests.published,
+    testresults.result_id,
+    testresults.end_time AS attempt_end_time
+FROM tests
+JOIN applications ON applications.job_id = tests.job_id
+JOIN students ON applications.student_id = students.student_id
+LEFT JOIN testresults ON testresults.test_id = tests.test_id AND testresults.user_id = students.user_id
+WHERE tests.test_id = $1
+AND students.student_id = $2
+`
+
+type TestOverrideTargetParams struct {
+	TestID    int64
+	StudentID int64
+}
+
+type TestOverrideTargetRow struct {
+	UserID         int64
+	Published      bool
+	ResultID       pgtype.Int8
+	AttemptEndTime pgtype.Timestamptz
+}
+
+func (q *Queries) TestOverrideTarget(ctx context.Context, arg TestOverrideTargetParams) (TestOverrideTargetRow, error) {
+	row := q.db.QueryRow(ctx, testOverrideTarget, arg.TestID, arg.StudentID)
+	var i TestOverrideTargetRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Published,
+		&i.ResultID,
+		&i.AttemptEndTime,
+	)
+	return i, err
+}
+
 const testPassFailCount = `-- name: TestPassFailCount :one
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = $1
+),
+failed AS (
+    SELECT DISTINCT 
+        testresults.result_id
+    FROM testresults
+    CROSS JOIN sections
+    WHERE testresults.test_id = $1
+    AND sections.min_score IS NOT NULL
+    AND COALESCE((
+        SELECT SUM(testresponses.points) 
+        FROM testresponses 
+        WHERE testresponses.result_id = testresults.result_id 
+        AND testresponses.section = sections.section
+    ), 0) < sections.min_score
+)
 SELECT
-    COUNT(CASE WHEN testresults.score >= $2 THEN 1 ELSE NULL END) AS pass_count,
-    COUNT(CASE WHEN testresults.score < $2 THEN 1 ELSE NULL END) AS fail_count
+    COUNT(CASE WHEN testresults.score >= $2 AND failed.result_id IS NULL THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN testresults.score < $2 OR failed.result_id IS NOT NULL THEN 1 ELSE NULL END) AS fail_count
 FROM testresults
+LEFT JOIN failed ON testresults.result_id = failed.result_id
 WHERE testresults.test_id = $1
 `
 
@@ -3056,12 +6219,74 @@ func (q *Queries) TestPassFailCount(ctx context.Context, arg TestPassFailCountPa
 	return i, err
 }
 
+const testPreviewData = `-- name: TestPreviewData :one
+SELECT 
+    tests.upload_method::TEXT AS upload_method,
+    tests.file_id,
+    tests.duration,
+    tests.shuffle,
+    tests.shuffle_seed,
+    (tests.start_time <= NOW())::BOOLEAN AS started
+FROM tests
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+`
+
+type TestPreviewDataParams struct {
+	TestID int64
+	UserID int64
+}
+
+type TestPreviewDataRow struct {
+	UploadMethod string
+	FileID       string
+	Duration     int64
+	Shuffle      bool
+	ShuffleSeed  int64
+	Started      bool
+}
+
+func (q *Queries) TestPreviewData(ctx context.Context, arg TestPreviewDataParams) (TestPreviewDataRow, error) {
+	row := q.db.QueryRow(ctx, testPreviewData, arg.TestID, arg.UserID)
+	var i TestPreviewDataRow
+	err := row.Scan(
+		&i.UploadMethod,
+		&i.FileID,
+		&i.Duration,
+		&i.Shuffle,
+		&i.ShuffleSeed,
+		&i.Started,
+	)
+	return i, err
+}
+
 const testResultPoller = `-- name: TestResultPoller :one
 SELECT  
     tests.test_id
 FROM tests
-WHERE tests.end_time < NOW()
+WHERE tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
 AND tests.result_url IS NULL
+AND tests.similarity_checked_at IS NOT NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresponses
+    JOIN testresults ON testresponses.result_id = testresults.result_id
+    WHERE testresults.test_id = tests.test_id
+    AND testresponses.needs_grading = true
+    AND testresponses.graded_at IS NULL
+)
 LIMIT 1
 `
 
@@ -3072,6 +6297,57 @@ func (q *Queries) TestResultPoller(ctx context.Context) (int64, error) {
 	return test_id, err
 }
 
+const undoShortlistBatch = `-- name: UndoShortlistBatch :many
+WITH undone AS (
+    UPDATE shortlistbatches
+    SET undone_at = NOW()
+    WHERE shortlistbatches.batch_id = $1
+    AND shortlistbatches.undone_at IS NULL
+    RETURNING shortlistbatches.batch_id
+),
+upd AS (
+    UPDATE applications
+    SET status = shortlistchanges.previous_status
+    FROM shortlistchanges
+    JOIN undone ON shortlistchanges.batch_id = undone.batch_id
+    WHERE applications.application_id = shortlistchanges.application_id
+    AND applications.status = shortlistchanges.new_status
+    RETURNING applications.application_id, applications.student_id, applications.status
+)
+SELECT 
+    upd.application_id,
+    upd.status::TEXT AS status,
+    students.user_id
+FROM upd
+JOIN students ON upd.student_id = students.student_id
+`
+
+type UndoShortlistBatchRow struct {
+	ApplicationID int64
+	Status        string
+	UserID        int64
+}
+
+func (q *Queries) UndoShortlistBatch(ctx context.Context, batchID int64) ([]UndoShortlistBatchRow, error) {
+	rows, err := q.db.Query(ctx, undoShortlistBatch, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UndoShortlistBatchRow
+	for rows.Next() {
+		var i UndoShortlistBatchRow
+		if err := rows.Scan(&i.ApplicationID, &i.Status, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const upcomingInterviewsStudent = `-- name: UpcomingInterviewsStudent :many
 SELECT 
     companies.company_name,
@@ -3135,7 +6411,12 @@ SELECT
     tests.description,
     tests.duration,
     tests.q_count,
-    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(tests.start_time, 'HH12:MI AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, tests.end_time), 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, LEAST(tests.start_time + tests.late_entry * INTERVAL '1 minute', tests.end_time)), 'HH12:MI AM DD-MM-YYYY') AS entry_closes,
+    tests.late_entry,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
     tests.type,    
     companies.company_name,
     jobs.title
@@ -3143,21 +6424,29 @@ FROM applications
 JOIN tests ON applications.job_id = tests.job_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)
 AND NOT EXISTS (SELECT 1 FROM testresults WHERE testresults.test_id = tests.test_id AND testresults.user_id = $1)
-AND tests.end_time > NOW()
+AND COALESCE(testoverrides.end_time, tests.end_time) > NOW()
+AND (testoverrides.end_time IS NOT NULL OR tests.late_entry IS NULL OR tests.start_time + tests.late_entry * INTERVAL '1 minute' > NOW())
+ORDER BY tests.start_time
 `
 
 type UpcomingTestsStudentRow struct {
-	TestID      int64
-	TestName    string
-	Description pgtype.Text
-	Duration    int64
-	QCount      int64
-	EndTime     string
-	Type        string
-	CompanyName string
-	Title       string
+	TestID       int64
+	TestName     string
+	Description  pgtype.Text
+	Duration     int64
+	QCount       int64
+	StartTime    string
+	EndTime      string
+	EntryCloses  string
+	LateEntry    pgtype.Int8
+	GracePeriod  int64
+	ExtraMinutes int32
+	Type         string
+	CompanyName  string
+	Title        string
 }
 
 func (q *Queries) UpcomingTestsStudent(ctx context.Context, userID int64) ([]UpcomingTestsStudentRow, error) {
@@ -3175,7 +6464,12 @@ func (q *Queries) UpcomingTestsStudent(ctx context.Context, userID int64) ([]Upc
 			&i.Description,
 			&i.Duration,
 			&i.QCount,
+			&i.StartTime,
 			&i.EndTime,
+			&i.EntryCloses,
+			&i.LateEntry,
+			&i.GracePeriod,
+			&i.ExtraMinutes,
 			&i.Type,
 			&i.CompanyName,
 			&i.Title,
@@ -3190,6 +6484,79 @@ func (q *Queries) UpcomingTestsStudent(ctx context.Context, userID int64) ([]Upc
 	return items, nil
 }
 
+const updateAnswerKey = `-- name: UpdateAnswerKey :one
+UPDATE testquestions
+SET 
+    correct_answer = $3,
+    points = $4
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id
+`
+
+type UpdateAnswerKeyParams struct {
+	QuestionID    int64
+	TestID        int64
+	CorrectAnswer []string
+	Points        int32
+}
+
+func (q *Queries) UpdateAnswerKey(ctx context.Context, arg UpdateAnswerKeyParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateAnswerKey,
+		arg.QuestionID,
+		arg.TestID,
+		arg.CorrectAnswer,
+		arg.Points,
+	)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
+const updateBankQuestion = `-- name: UpdateBankQuestion :one
+UPDATE bankquestions
+SET 
+    type = $3,
+    title = $4,
+    description = $5,
+    options = $6,
+    correct_answer = $7,
+    tags = $8,
+    difficulty = $9
+WHERE bankquestions.question_id = $1
+AND bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+RETURNING question_id
+`
+
+type UpdateBankQuestionParams struct {
+	QuestionID    int64
+	UserID        int64
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Tags          []string
+	Difficulty    string
+}
+
+func (q *Queries) UpdateBankQuestion(ctx context.Context, arg UpdateBankQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateBankQuestion,
+		arg.QuestionID,
+		arg.UserID,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Options,
+		arg.CorrectAnswer,
+		arg.Tags,
+		arg.Difficulty,
+	)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
 const updateCompanyDetails = `-- name: UpdateCompanyDetails :exec
 UPDATE companies
 SET company_name = $1,
@@ -3266,6 +6633,47 @@ func (q *Queries) UpdateDiscussion(ctx context.Context, arg UpdateDiscussionPara
 	return err
 }
 
+const updateDrawRule = `-- name: UpdateDrawRule :one
+UPDATE testdrawrules
+SET 
+    tags = $3,
+    difficulty = $4,
+    count = $5,
+    points = $6,
+    position = $7,
+    section_id = $8
+WHERE testdrawrules.rule_id = $1
+AND testdrawrules.test_id = $2
+RETURNING rule_id
+`
+
+type UpdateDrawRuleParams struct {
+	RuleID     int64
+	TestID     int64
+	Tags       []string
+	Difficulty pgtype.Text
+	Count      int32
+	Points     int32
+	Position   int32
+	SectionID  pgtype.Int8
+}
+
+func (q *Queries) UpdateDrawRule(ctx context.Context, arg UpdateDrawRuleParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateDrawRule,
+		arg.RuleID,
+		arg.TestID,
+		arg.Tags,
+		arg.Difficulty,
+		arg.Count,
+		arg.Points,
+		arg.Position,
+		arg.SectionID,
+	)
+	var rule_id int64
+	err := row.Scan(&rule_id)
+	return rule_id, err
+}
+
 const updateEmailConfirmation = `-- name: UpdateEmailConfirmation :exec
 UPDATE users
 SET confirmed = true
@@ -3317,35 +6725,92 @@ func (q *Queries) UpdateInterview(ctx context.Context, arg UpdateInterviewParams
 	return i, err
 }
 
-const updateJob = `-- name: UpdateJob :exec
-UPDATE jobs
-SET location = $1,
-    title = $2,
-    description = $3,
-    type = $4,
-    salary = $5,
-    skills = $6,
-    position = $7,
-    extras = $8
-WHERE job_id = $9
-AND company_id = (SELECT company_id FROM companies WHERE companies.user_id = $10)
+const updateJob = `-- name: UpdateJob :one
+WITH old AS (
+    SELECT job_id, location, salary, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, active_status, application_deadline, closed_by_deadline
+    FROM jobs
+    WHERE job_id = $9
+    AND company_id = (SELECT company_id FROM companies WHERE companies.user_id = $10)
+    FOR UPDATE
+),
+job AS (
+    UPDATE jobs
+    SET location = $1,
+        title = $2,
+        description = $3,
+        type = $4,
+        salary = $5,
+        skills = $6,
+        position = $7,
+        extras = $8,
+        min_cgpa = $11,
+        eligible_departments = $12,
+        eligible_courses = $13,
+        eligible_years = $14,
+        eligible_genders = $15,
+        application_deadline = CASE WHEN $17::BOOLEAN THEN $16 ELSE old.application_deadline END,
+        active_status = CASE WHEN $17::BOOLEAN AND NOT old.active_status AND old.closed_by_deadline AND $16 > NOW() THEN true ELSE old.active_status END,
+        closed_by_deadline = CASE WHEN $17::BOOLEAN AND NOT old.active_status AND old.closed_by_deadline AND $16 > NOW() THEN false ELSE old.closed_by_deadline END
+    FROM old
+    WHERE jobs.job_id = old.job_id
+    RETURNING jobs.*
+),
+original AS (
+    INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    SELECT jobs.job_id, 1, jobs.title, jobs.location, jobs.type, jobs.salary, jobs.skills, jobs.position, jobs.description, jobs.extras, jobs.min_cgpa, jobs.eligible_departments, jobs.eligible_courses, jobs.eligible_years, jobs.eligible_genders, jobs.application_deadline
+    FROM jobs
+    JOIN old ON old.job_id = jobs.job_id
+    WHERE NOT EXISTS (SELECT 1 FROM jobversions WHERE jobversions.job_id = old.job_id)
+),
+snapshot AS (
+    INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    SELECT job_id, (SELECT COALESCE(MAX(version), 1) + 1 FROM jobversions WHERE jobversions.job_id = job.job_id), title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline
+    FROM job
+    RETURNING job_id, version
+)
+SELECT 
+    snapshot.version,
+    (old.salary <> job.salary)::BOOLEAN AS salary_changed,
+    (old.location <> job.location)::BOOLEAN AS location_changed,
+    (old.min_cgpa IS DISTINCT FROM job.min_cgpa
+        OR old.eligible_departments <> job.eligible_departments
+        OR old.eligible_courses <> job.eligible_courses
+        OR old.eligible_years <> job.eligible_years
+        OR old.eligible_genders <> job.eligible_genders)::BOOLEAN AS eligibility_changed
+FROM snapshot
+JOIN old ON old.job_id = snapshot.job_id
+JOIN job ON job.job_id = snapshot.job_id
 `
 
 type UpdateJobParams struct {
-	Location    string
-	Title       string
-	Description pgtype.Text
-	Type        string
-	Salary      string
-	Skills      []string
-	Position    string
-	Extras      []byte
-	JobID       int64
-	UserID      int64
+	Location            string
+	Title               string
+	Description         pgtype.Text
+	Type                string
+	Salary              string
+	Skills              []string
+	Position            string
+	Extras              []byte
+	JobID               int64
+	UserID              int64
+	MinCgpa             pgtype.Float8
+	EligibleDepartments []string
+	EligibleCourses     []string
+	EligibleYears       []string
+	EligibleGenders     []string
+	ApplicationDeadline pgtype.Timestamptz
+	DeadlineSet         bool
+}
+
+type UpdateJobRow struct {
+	Version            int32
+	SalaryChanged      bool
+	LocationChanged    bool
+	EligibilityChanged bool
 }
 
-func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) error {
-	_, err := q.db.Exec(ctx, updateJob,
+func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) (UpdateJobRow, error) {
+	row := q.db.QueryRow(ctx, updateJob,
 		arg.Location,
 		arg.Title,
 		arg.Description,
@@ -3356,8 +6821,54 @@ func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) error {
 		arg.Extras,
 		arg.JobID,
 		arg.UserID,
+		arg.MinCgpa,
+		arg.EligibleDepartments,
+		arg.EligibleCourses,
+		arg.EligibleYears,
+		arg.EligibleGenders,
+		arg.ApplicationDeadline,
+		arg.DeadlineSet,
 	)
-	return err
+	var i UpdateJobRow
+	err := row.Scan(
+		&i.Version,
+		&i.SalaryChanged,
+		&i.LocationChanged,
+		&i.EligibilityChanged,
+	)
+	return i, err
+}
+
+const updateMarkingScheme = `-- name: UpdateMarkingScheme :execrows
+UPDATE tests
+SET 
+    negative_marking = $3,
+    partial_credit = $4,
+    min_score = $5
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+`
+
+type UpdateMarkingSchemeParams struct {
+	TestID          int64
+	UserID          int64
+	NegativeMarking int32
+	PartialCredit   bool
+	MinScore        pgtype.Int4
+}
+
+func (q *Queries) UpdateMarkingScheme(ctx context.Context, arg UpdateMarkingSchemeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMarkingScheme,
+		arg.TestID,
+		arg.UserID,
+		arg.NegativeMarking,
+		arg.PartialCredit,
+		arg.MinScore,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const updatePassword = `-- name: UpdatePassword :exec
@@ -3514,6 +7025,53 @@ func (q *Queries) UpdateTest(ctx context.Context, arg UpdateTestParams) error {
 	return err
 }
 
+const updateTestQuestion = `-- name: UpdateTestQuestion :one
+UPDATE testquestions
+SET 
+    position = $3,
+    type = $4,
+    title = $5,
+    description = $6,
+    options = $7,
+    correct_answer = $8,
+    points = $9,
+    section_id = $10
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id
+`
+
+type UpdateTestQuestionParams struct {
+	QuestionID    int64
+	TestID        int64
+	Position      int32
+	Type          string
+	Title         string
+	Description   pgtype.Text
+	Options       []string
+	CorrectAnswer []string
+	Points        int32
+	SectionID     pgtype.Int8
+}
+
+func (q *Queries) UpdateTestQuestion(ctx context.Context, arg UpdateTestQuestionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateTestQuestion,
+		arg.QuestionID,
+		arg.TestID,
+		arg.Position,
+		arg.Type,
+		arg.Title,
+		arg.Description,
+		arg.Options,
+		arg.CorrectAnswer,
+		arg.Points,
+		arg.SectionID,
+	)
+	var question_id int64
+	err := row.Scan(&question_id)
+	return question_id, err
+}
+
 const updateTestResultURLUnprotected = `-- name: UpdateTestResultURLUnprotected :exec
 UPDATE tests
 SET 
@@ -3531,6 +7089,100 @@ func (q *Queries) UpdateTestResultURLUnprotected(ctx context.Context, arg Update
 	return err
 }
 
+const updateTestSection = `-- name: UpdateTestSection :one
+UPDATE testsections
+SET 
+    name = $3,
+    position = $4,
+    duration = $5,
+    min_score = $6
+WHERE testsections.section_id = $1
+AND testsections.test_id = $2
+RETURNING section_id
+`
+
+type UpdateTestSectionParams struct {
+	SectionID int64
+	TestID    int64
+	Name      string
+	Position  int32
+	Duration  pgtype.Int8
+	MinScore  pgtype.Int4
+}
+
+func (q *Queries) UpdateTestSection(ctx context.Context, arg UpdateTestSectionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, updateTestSection,
+		arg.SectionID,
+		arg.TestID,
+		arg.Name,
+		arg.Position,
+		arg.Duration,
+		arg.MinScore,
+	)
+	var section_id int64
+	err := row.Scan(&section_id)
+	return section_id, err
+}
+
+const upsertSharedForm = `-- name: UpsertSharedForm :exec
+INSERT INTO sharedforms (
+    form_id, responder_uri
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (form_id) DO UPDATE
+SET responder_uri = EXCLUDED.responder_uri
+`
+
+type UpsertSharedFormParams struct {
+	FormID       string
+	ResponderUri string
+}
+
+func (q *Queries) UpsertSharedForm(ctx context.Context, arg UpsertSharedFormParams) error {
+	_, err := q.db.Exec(ctx, upsertSharedForm, arg.FormID, arg.ResponderUri)
+	return err
+}
+
+const upsertTestOverride = `-- name: UpsertTestOverride :one
+INSERT INTO testoverrides (test_id, user_id, extra_minutes, end_time, fresh_attempt, reason, granted_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (test_id, user_id)
+DO UPDATE SET 
+    extra_minutes = $3,
+    end_time = $4,
+    fresh_attempt = $5,
+    reason = $6,
+    granted_by = $7,
+    created_at = NOW()
+RETURNING override_id
+`
+
+type UpsertTestOverrideParams struct {
+	TestID       int64
+	UserID       int64
+	ExtraMinutes int32
+	EndTime      pgtype.Timestamptz
+	FreshAttempt bool
+	Reason       string
+	GrantedBy    int64
+}
+
+func (q *Queries) UpsertTestOverride(ctx context.Context, arg UpsertTestOverrideParams) (int64, error) {
+	row := q.db.QueryRow(ctx, upsertTestOverride,
+		arg.TestID,
+		arg.UserID,
+		arg.ExtraMinutes,
+		arg.EndTime,
+		arg.FreshAttempt,
+		arg.Reason,
+		arg.GrantedBy,
+	)
+	var override_id int64
+	err := row.Scan(&override_id)
+	return override_id, err
+}
+
 const usersTableData = `-- name: UsersTableData :one
 SELECT 
     TO_CHAR(users.created_at, 'HH12:MI AM DD-MM-YYYY') AS created_at,
@@ -0,0 +1,21 @@
+-- schema.sql creates the tables of a new database, the files here bring an existing database up to it
+-- every file is run once, in the order of its number, eg. psql "$DATABASE_URL" -f 0001_temp_correct_answers_per_test.sql
+
+-- the answer key of every test being evaluated is scoped to its test, the question ids of copied forms repeat across tests
+-- the table only holds the key of the evaluation in progress, it is filled again on every evaluation
+BEGIN;
+
+DROP TABLE IF EXISTS temp_correct_answers;
+
+CREATE TABLE temp_correct_answers (
+    test_id BIGINT NOT NULL,
+    question_id TEXT NOT NULL,
+    correct_answer TEXT[],
+    points INT,
+    manual BOOLEAN NOT NULL DEFAULT false,
+    multiple BOOLEAN NOT NULL DEFAULT false,
+    section INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (test_id, question_id)
+);
+
+COMMIT;
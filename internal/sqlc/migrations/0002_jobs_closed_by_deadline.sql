@@ -0,0 +1,4 @@
+-- a job closed by the deadline poller is told apart from a job closed by the company,
+-- only the first is reopened when its deadline is moved to the future
+-- the jobs closed before this are left as they are, it is not known who closed them
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS closed_by_deadline BOOLEAN NOT NULL DEFAULT false;
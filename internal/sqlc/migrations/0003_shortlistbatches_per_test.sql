@@ -0,0 +1,3 @@
+-- a test is shortlisted by its cutoff once, a second batch of the same test is refused
+-- a test with more than one batch already must have the extra ones removed before this runs
+ALTER TABLE shortlistbatches ADD CONSTRAINT unique_shortlistbatches_test_id UNIQUE (test_id);
@@ -112,22 +112,109 @@ FROM tb;
 -- >>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>>
 -- Company queries 
 
--- name: InsertNewJob :exec
-INSERT INTO jobs (data_url, company_id, title, location, type, salary, skills, position, extras, description)
-VALUES ($1, (SELECT company_id FROM companies WHERE companies.user_id = $2), $3, $4, $5, $6, $7, $8, $9, $10);
+-- name: InsertNewJob :one
+WITH job AS (
+    INSERT INTO jobs (data_url, company_id, title, location, type, salary, skills, position, extras, description, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    VALUES ($1, (SELECT company_id FROM companies WHERE companies.user_id = $2), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+    RETURNING *
+)
+INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+SELECT job_id, 1, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline
+FROM job
+RETURNING job_id;
+
+-- name: UpdateJob :one
+WITH old AS (
+    SELECT job_id, location, salary, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, active_status, application_deadline, closed_by_deadline
+    FROM jobs
+    WHERE job_id = $9
+    AND company_id = (SELECT company_id FROM companies WHERE companies.user_id = $10)
+    FOR UPDATE
+),
+job AS (
+    UPDATE jobs
+    SET location = $1,
+        title = $2,
+        description = $3,
+        type = $4,
+        salary = $5,
+        skills = $6,
+        position = $7,
+        extras = $8,
+        min_cgpa = $11,
+        eligible_departments = $12,
+        eligible_courses = $13,
+        eligible_years = $14,
+        eligible_genders = $15,
+        application_deadline = CASE WHEN sqlc.arg('deadline_set')::BOOLEAN THEN $16 ELSE old.application_deadline END,
+        active_status = CASE WHEN sqlc.arg('deadline_set')::BOOLEAN AND NOT old.active_status AND old.closed_by_deadline AND $16 > NOW() THEN true ELSE old.active_status END,
+        closed_by_deadline = CASE WHEN sqlc.arg('deadline_set')::BOOLEAN AND NOT old.active_status AND old.closed_by_deadline AND $16 > NOW() THEN false ELSE old.closed_by_deadline END
+    FROM old
+    WHERE jobs.job_id = old.job_id
+    RETURNING jobs.*
+),
+original AS (
+    INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    SELECT jobs.job_id, 1, jobs.title, jobs.location, jobs.type, jobs.salary, jobs.skills, jobs.position, jobs.description, jobs.extras, jobs.min_cgpa, jobs.eligible_departments, jobs.eligible_courses, jobs.eligible_years, jobs.eligible_genders, jobs.application_deadline
+    FROM jobs
+    JOIN old ON old.job_id = jobs.job_id
+    WHERE NOT EXISTS (SELECT 1 FROM jobversions WHERE jobversions.job_id = old.job_id)
+),
+snapshot AS (
+    INSERT INTO jobversions (job_id, version, title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline)
+    SELECT job_id, (SELECT COALESCE(MAX(version), 1) + 1 FROM jobversions WHERE jobversions.job_id = job.job_id), title, location, type, salary, skills, position, description, extras, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline
+    FROM job
+    RETURNING job_id, version
+)
+SELECT 
+    snapshot.version,
+    (old.salary <> job.salary)::BOOLEAN AS salary_changed,
+    (old.location <> job.location)::BOOLEAN AS location_changed,
+    (old.min_cgpa IS DISTINCT FROM job.min_cgpa
+        OR old.eligible_departments <> job.eligible_departments
+        OR old.eligible_courses <> job.eligible_courses
+        OR old.eligible_years <> job.eligible_years
+        OR old.eligible_genders <> job.eligible_genders)::BOOLEAN AS eligibility_changed
+FROM snapshot
+JOIN old ON old.job_id = snapshot.job_id
+JOIN job ON job.job_id = snapshot.job_id;
+
+-- name: JobDeadline :one
+SELECT jobs.application_deadline
+FROM jobs
+WHERE jobs.job_id = $1
+AND jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2);
 
--- name: UpdateJob :exec
-UPDATE jobs
-SET location = $1,
-    title = $2,
-    description = $3,
-    type = $4,
-    salary = $5,
-    skills = $6,
-    position = $7,
-    extras = $8
-WHERE job_id = $9
-AND company_id = (SELECT company_id FROM companies WHERE companies.user_id = $10);
+-- name: JobVersions :many
+SELECT 
+    jobversions.version,
+    jobversions.title,
+    jobversions.location,
+    jobversions.type,
+    jobversions.salary,
+    jobversions.skills,
+    jobversions.position,
+    jobversions.description,
+    jobversions.min_cgpa,
+    jobversions.eligible_departments,
+    jobversions.eligible_courses,
+    jobversions.eligible_years,
+    jobversions.eligible_genders,
+    jobversions.application_deadline,
+    jobversions.created_at
+FROM jobversions
+JOIN jobs ON jobs.job_id = jobversions.job_id
+JOIN companies ON companies.company_id = jobs.company_id
+WHERE jobversions.job_id = $1
+AND companies.user_id = $2
+ORDER BY jobversions.version DESC;
+
+-- name: JobApplicantUsers :many
+SELECT students.user_id
+FROM applications
+JOIN students ON applications.student_id = students.student_id
+WHERE applications.job_id = $1
+AND applications.status != 'Rejected';
 
 
 
@@ -146,9 +233,26 @@ RETURNING *;
 
 
 
--- name: InsertNewApplication :exec
+-- name: InsertNewApplication :execrows
 INSERT INTO applications (job_id, student_id, data_url) 
-VALUES ($1, (SELECT student_id FROM students WHERE user_id = $2), $3);
+SELECT $1, (SELECT student_id FROM students WHERE user_id = $2), $3
+WHERE EXISTS (
+    SELECT 1 FROM jobs
+    WHERE jobs.job_id = $1
+    AND jobs.active_status
+    AND (jobs.application_deadline IS NULL OR jobs.application_deadline > NOW())
+);
+
+
+-- name: StudentEligibility :one
+SELECT cgpa, department, course, year_of_study, gender
+FROM students
+WHERE user_id = $1;
+
+-- name: JobEligibility :one
+SELECT active_status, min_cgpa, eligible_departments, eligible_courses, eligible_years, eligible_genders, application_deadline, salary
+FROM jobs
+WHERE job_id = $1;
 
 
 -- name: GetApplicableJobsTypeFilter :many
@@ -162,7 +266,13 @@ SELECT
     jobs.skills,
     jobs.company_id,
     jobs.active_status,
-    companies.company_name 
+    companies.company_name,
+    jobs.min_cgpa,
+    jobs.eligible_departments,
+    jobs.eligible_courses,
+    jobs.eligible_years,
+    jobs.eligible_genders,
+    jobs.application_deadline
 FROM jobs
 JOIN companies ON jobs.company_id = companies.company_id 
 LEFT JOIN (SELECT applications.job_id FROM applications WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)) AS t 
@@ -186,17 +296,48 @@ SELECT
     companies.company_name,
     companies.representative_email,
     companies.representative_name,
-    applications.status::TEXT AS status
+    applications.status::TEXT AS status,
+    COALESCE(jobstages.name, '') AS stage,
+    COALESCE(jobstages.position, 0) AS stage_position,
+    (SELECT COUNT(*) FROM jobstages AS js WHERE js.job_id = jobs.job_id) AS total_stages
 FROM applications
 JOIN students ON applications.student_id = students.student_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
 WHERE students.user_id = $1 
   AND ($2 = 'All' OR applications.status::TEXT = $2)
 ORDER BY jobs.job_id;
 
 
 
+-- name: OfferedApplication :one
+SELECT 
+    applications.application_id,
+    jobs.salary
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.job_id = $1
+AND applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $2)
+AND applications.status = 'Offered';
+
+-- name: RespondToOffer :one
+WITH upd AS (
+    UPDATE applications
+    SET status = CASE WHEN sqlc.arg('accept')::BOOLEAN THEN 'Hired' ELSE 'Rejected' END::application_status,
+        offer_declined_at = CASE WHEN sqlc.arg('accept')::BOOLEAN THEN NULL ELSE NOW() END
+    WHERE application_id = sqlc.arg('application_id')
+    AND status = 'Offered'
+    RETURNING application_id, job_id
+)
+SELECT 
+    upd.application_id,
+    jobs.title,
+    companies.user_id
+FROM upd
+JOIN jobs ON jobs.job_id = upd.job_id
+JOIN companies ON companies.company_id = jobs.company_id;
+
 -- name: CancelApplication :exec
 DELETE FROM applications 
 WHERE student_id = (SELECT student_id FROM students WHERE students.user_id = $1) 
@@ -219,11 +360,15 @@ SELECT
     jobs.title, 
     applications.status::TEXT AS status,
     COALESCE(interviews.status::TEXT, '') AS interview_status,
-    applications.application_id
+    applications.application_id,
+    COALESCE(jobstages.name, '') AS stage,
+    COALESCE(jobstages.position, 0) AS stage_position,
+    (SELECT COUNT(*) FROM jobstages AS js WHERE js.job_id = jobs.job_id) AS total_stages
 FROM applications
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN students ON applications.student_id = students.student_id
 LEFT JOIN interviews ON applications.application_id = interviews.application_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
 WHERE jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
 AND (jobs.job_id = $2 OR $2 = 0)
 AND (applications.application_id = $3 OR $3 = 0)
@@ -292,10 +437,31 @@ ORDER BY jobs.job_id;
 
 -- name: CloseJob :exec
 UPDATE jobs
-SET active_status = false
+SET active_status = false,
+    closed_by_deadline = false
 WHERE jobs.job_id = $1 
 AND jobs.company_id = (SELECT companies.company_id FROM companies WHERE user_id = $2);
 
+-- name: CloseExpiredJobs :many
+WITH closed AS (
+    UPDATE jobs
+    SET active_status = false,
+        closed_by_deadline = true
+    WHERE active_status
+    AND application_deadline <= NOW()
+    RETURNING job_id, title, company_id, application_deadline
+)
+SELECT
+    closed.job_id,
+    closed.title,
+    closed.application_deadline,
+    companies.user_id,
+    companies.company_name,
+    companies.representative_email,
+    (SELECT COUNT(*) FROM applications WHERE applications.job_id = closed.job_id) AS applicants
+FROM closed
+JOIN companies ON companies.company_id = closed.company_id;
+
 -- name: DeleteJob :exec
 DELETE FROM jobs 
 WHERE jobs.job_id = $1
@@ -323,6 +489,68 @@ SELECT
 FROM students 
 JOIN upd ON students.student_id = upd.student_id;
 
+-- name: JobStagesEditable :one
+SELECT 
+    (SELECT COUNT(*) FROM applications WHERE applications.job_id = jobs.job_id AND applications.stage_id IS NOT NULL) AS in_progress
+FROM jobs
+JOIN companies ON companies.company_id = jobs.company_id
+WHERE jobs.job_id = $1
+AND companies.user_id = $2
+FOR UPDATE OF jobs;
+
+-- name: LockApplicationJob :one
+SELECT jobs.job_id
+FROM jobs
+JOIN applications ON applications.job_id = jobs.job_id
+WHERE applications.application_id = $1
+FOR UPDATE OF jobs;
+
+-- name: DeleteJobStages :exec
+DELETE FROM jobstages
+WHERE job_id = $1;
+
+-- name: InsertJobStages :many
+INSERT INTO jobstages (job_id, position, name)
+SELECT sqlc.arg('job_id')::BIGINT, stages.position, stages.name
+FROM UNNEST(sqlc.arg('names')::TEXT[]) WITH ORDINALITY AS stages(name, position)
+RETURNING stage_id, position, name;
+
+-- name: JobStages :many
+SELECT 
+    stage_id,
+    position,
+    name
+FROM jobstages
+WHERE job_id = $1
+ORDER BY position;
+
+-- name: ApplicationStage :one
+SELECT 
+    applications.job_id,
+    companies.user_id,
+    applications.status::TEXT AS status,
+    applications.stage_id,
+    COALESCE(jobstages.position, 0) AS position
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+JOIN companies ON companies.company_id = jobs.company_id
+LEFT JOIN jobstages ON jobstages.stage_id = applications.stage_id
+WHERE applications.application_id = $1;
+
+-- name: SetApplicationStage :one
+WITH upd AS (
+    UPDATE applications
+    SET stage_id = sqlc.arg('stage_id')
+    WHERE application_id = sqlc.arg('application_id')
+    AND status = 'ShortListed'
+    AND stage_id IS NOT DISTINCT FROM sqlc.narg('current_stage_id')
+    RETURNING student_id
+)
+SELECT
+    students.user_id
+FROM students 
+JOIN upd ON students.student_id = upd.student_id;
+
 -- name: ApplicationStatusTo :one
 WITH upd AS (
     UPDATE applications
@@ -428,7 +656,12 @@ SELECT
     tests.description,
     tests.duration,
     tests.q_count,
-    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(tests.start_time, 'HH12:MI AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, tests.end_time), 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, LEAST(tests.start_time + tests.late_entry * INTERVAL '1 minute', tests.end_time)), 'HH12:MI AM DD-MM-YYYY') AS entry_closes,
+    tests.late_entry,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
     tests.type,    
     companies.company_name,
     jobs.title
@@ -436,9 +669,12 @@ FROM applications
 JOIN tests ON applications.job_id = tests.job_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)
 AND NOT EXISTS (SELECT 1 FROM testresults WHERE testresults.test_id = tests.test_id AND testresults.user_id = $1)
-AND tests.end_time > NOW();
+AND COALESCE(testoverrides.end_time, tests.end_time) > NOW()
+AND (testoverrides.end_time IS NOT NULL OR tests.late_entry IS NULL OR tests.start_time + tests.late_entry * INTERVAL '1 minute' > NOW())
+ORDER BY tests.start_time;
 
 
 -- name: ScheduledInterviewsCompany :many
@@ -570,7 +806,11 @@ SELECT
     tests.description,
     tests.duration,
     tests.q_count,
-    TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(tests.start_time, 'HH12:MI AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, tests.end_time), 'HH12:MI AM DD-MM-YYYY') AS end_time,
+    TO_CHAR(COALESCE(testoverrides.end_time, LEAST(tests.start_time + tests.late_entry * INTERVAL '1 minute', tests.end_time)), 'HH12:MI AM DD-MM-YYYY') AS entry_closes,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
     tests.type,    
     companies.company_name,
     jobs.title
@@ -578,28 +818,62 @@ FROM applications
 JOIN tests ON applications.job_id = tests.job_id
 JOIN jobs ON applications.job_id = jobs.job_id
 JOIN companies ON jobs.company_id = companies.company_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1)
 AND tests.test_id = $2;
 
 
--- name: NewTest :exec
-INSERT INTO tests (test_name, description, duration, q_count, end_time, type, upload_method, job_id, company_id, file_id, threshold)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT company_id FROM companies WHERE user_id = $9), $10, $11);
+-- name: NewTest :one
+INSERT INTO tests (test_name, description, duration, q_count, end_time, type, upload_method, job_id, company_id, file_id, threshold, negative_marking, partial_credit, min_score, shuffle, start_time, late_entry, grace_period)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, (SELECT company_id FROM companies WHERE user_id = $9), $10, $11, $12, $13, $14, $15, $16, $17, $18)
+RETURNING test_id;
 
 
+-- name: AnnounceTest :one
+UPDATE tests
+SET 
+    announced_at = NOW()
+WHERE tests.test_id = $1
+AND tests.announced_at IS NULL
+AND (
+    tests.upload_method <> 'Manual'
+    OR EXISTS (SELECT 1 FROM testquestions WHERE testquestions.test_id = tests.test_id)
+)
+RETURNING 
+    tests.job_id,
+    tests.test_name,
+    tests.description,
+    tests.duration,
+    tests.q_count,
+    tests.type,
+    tests.start_time,
+    tests.end_time,
+    tests.late_entry,
+    tests.grace_period;
+
 -- name: TakeTest :one
 SELECT 
     tests.file_id,
     tests.duration,
-    tests.end_time
+    tests.end_time,
+    tests.upload_method::TEXT AS upload_method,
+    tests.shuffle,
+    tests.shuffle_seed,
+    tests.start_time,
+    tests.late_entry,
+    tests.grace_period,
+    COALESCE(testoverrides.extra_minutes, 0)::INT AS extra_minutes,
+    testoverrides.end_time AS override_end_time,
+    COALESCE(testoverrides.fresh_attempt, false)::BOOLEAN AS fresh_attempt
 FROM tests
 JOIN applications ON applications.job_id = tests.job_id
+LEFT JOIN testoverrides ON testoverrides.test_id = tests.test_id AND testoverrides.user_id = $1
 WHERE applications.student_id = (SELECT student_id FROM students WHERE user_id = $1)
 AND tests.test_id = $2;
 
 -- name: NewTestResult :exec
-INSERT INTO testresults (test_id, user_id, start_time)
-VALUES ($1, $2, $3);
+INSERT INTO testresults (test_id, user_id, start_time, extra_minutes, override_end_time, fresh_attempt)
+VALUES ($1, $2, $3, $4, $5, $6);
 
 -- name: IsTestGiven :one
 SELECT 
@@ -623,8 +897,102 @@ UPDATE testresults
 SET end_time = $1
 WHERE user_id = $2 
 AND test_id = $3
+AND end_time IS NULL
 RETURNING result_id;
 
+-- name: FinalizeExpiredAttempts :execrows
+UPDATE testresults
+SET end_time = LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute')
+FROM tests
+WHERE testresults.test_id = tests.test_id
+AND testresults.end_time IS NULL
+AND testresults.start_time IS NOT NULL
+AND LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute') < NOW();
+
+-- name: OpenTestAttempt :one
+SELECT 
+    testresults.result_id
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+WHERE testresults.test_id = $1
+AND testresults.user_id = $2
+AND testresults.start_time IS NOT NULL
+AND testresults.end_time IS NULL
+AND LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute') > NOW();
+
+-- name: InsertProctorEvent :exec
+INSERT INTO proctorevents (result_id, event_type, detail, ip_address)
+VALUES ($1, $2, $3, $4);
+
+-- name: TestOverrideTarget :one
+SELECT 
+    students.user_id,
+    tests.published,
+    testresults.result_id,
+    testresults.end_time AS attempt_end_time
+FROM tests
+JOIN applications ON applications.job_id = tests.job_id
+JOIN students ON applications.student_id = students.student_id
+LEFT JOIN testresults ON testresults.test_id = tests.test_id AND testresults.user_id = students.user_id
+WHERE tests.test_id = $1
+AND students.student_id = $2;
+
+-- name: UpsertTestOverride :one
+INSERT INTO testoverrides (test_id, user_id, extra_minutes, end_time, fresh_attempt, reason, granted_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (test_id, user_id)
+DO UPDATE SET 
+    extra_minutes = $3,
+    end_time = $4,
+    fresh_attempt = $5,
+    reason = $6,
+    granted_by = $7,
+    created_at = NOW()
+RETURNING override_id;
+
+-- name: DeleteTestOverride :one
+DELETE FROM testoverrides
+WHERE testoverrides.test_id = $1
+AND testoverrides.user_id = (SELECT students.user_id FROM students WHERE students.student_id = $2)
+RETURNING user_id;
+
+-- name: ListTestOverrides :many
+SELECT 
+    students.student_id,
+    students.student_name,
+    students.roll_number,
+    testoverrides.extra_minutes,
+    COALESCE(TO_CHAR(testoverrides.end_time, 'HH12:MI AM DD-MM-YYYY'), '')::TEXT AS end_time,
+    testoverrides.fresh_attempt,
+    testoverrides.reason,
+    TO_CHAR(testoverrides.created_at, 'HH12:MI AM DD-MM-YYYY') AS granted_at
+FROM testoverrides
+JOIN students ON testoverrides.user_id = students.user_id
+WHERE testoverrides.test_id = $1
+ORDER BY testoverrides.created_at DESC;
+
+-- name: DeleteTestAttempt :exec
+DELETE FROM testresults
+WHERE testresults.result_id = $1;
+
+-- name: ApplyAttemptOverride :one
+UPDATE testresults
+SET 
+    extra_minutes = $2,
+    override_end_time = $3
+FROM tests
+WHERE testresults.test_id = tests.test_id
+AND testresults.result_id = $1
+AND testresults.end_time IS NULL
+RETURNING (LEAST(testresults.start_time + (tests.duration + testresults.extra_minutes) * INTERVAL '1 minute', COALESCE(testresults.override_end_time, tests.end_time) + (tests.grace_period + testresults.extra_minutes) * INTERVAL '1 minute'))::TIMESTAMPTZ AS deadline;
+
+-- name: ResetTestResultURL :exec
+UPDATE tests
+SET result_url = NULL,
+    similarity_checked_at = NULL
+WHERE tests.test_id = $1
+AND tests.published = false;
+
 
 -- name: StudentProfileData :one
 SELECT 
@@ -728,6 +1096,45 @@ FROM applications
 JOIN ji ON ji.job_id = applications.job_id
 GROUP BY applications.job_id;
 
+-- name: ApplicantStageCounts :many
+SELECT
+    jobstages.job_id,
+    jobstages.position,
+    jobstages.name,
+    CAST(COUNT(staged.application_id) AS BIGINT) AS reached,
+    CAST(COALESCE(SUM(CASE WHEN staged.status = 'Rejected' AND staged.position = jobstages.position THEN 1 END), 0) AS BIGINT) AS rejected_count
+FROM jobstages
+JOIN jobs ON jobs.job_id = jobstages.job_id
+LEFT JOIN (
+    SELECT 
+        applications.application_id,
+        applications.job_id,
+        applications.status,
+        js.position
+    FROM applications
+    JOIN jobstages AS js ON js.stage_id = applications.stage_id
+) AS staged ON staged.job_id = jobstages.job_id AND staged.position >= jobstages.position
+WHERE jobs.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+GROUP BY jobstages.job_id, jobstages.position, jobstages.name
+ORDER BY jobstages.job_id, jobstages.position;
+
+-- name: ApplicationRoundCounts :many
+SELECT
+    rounds.position::INT AS position,
+    CAST(COUNT(*) AS BIGINT) AS reached,
+    CAST(COALESCE(SUM(CASE WHEN staged.status = 'Rejected' AND staged.position = rounds.position THEN 1 END), 0) AS BIGINT) AS rejected_count
+FROM (
+    SELECT 
+        applications.status,
+        jobstages.position
+    FROM applications
+    JOIN jobstages ON jobstages.stage_id = applications.stage_id
+    WHERE applications.student_id = (SELECT students.student_id FROM students WHERE students.user_id = $1)
+) AS staged
+CROSS JOIN LATERAL generate_series(1, staged.position) AS rounds(position)
+GROUP BY rounds.position
+ORDER BY rounds.position;
+
 -- name: UsersTableData :one
 SELECT 
     TO_CHAR(users.created_at, 'HH12:MI AM DD-MM-YYYY') AS created_at,
@@ -816,6 +1223,15 @@ SET
 WHERE tests.test_id = $1
 AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2);
 
+-- name: UpdateMarkingScheme :execrows
+UPDATE tests
+SET 
+    negative_marking = $3,
+    partial_credit = $4,
+    min_score = $5
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2);
+
 
 -- name: UpdateInterview :one
 UPDATE interviews
@@ -834,26 +1250,64 @@ RETURNING application_id, TO_CHAR(date_time, 'HH12:MI AM DD-MM-YYYY') AS date_ti
 SELECT  
     tests.test_id
 FROM tests
-WHERE tests.end_time < NOW()
+WHERE tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
 AND tests.result_url IS NULL
+AND tests.similarity_checked_at IS NOT NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresponses
+    JOIN testresults ON testresponses.result_id = testresults.result_id
+    WHERE testresults.test_id = tests.test_id
+    AND testresponses.needs_grading = true
+    AND testresponses.graded_at IS NULL
+)
 LIMIT 1;
 
 -- name: TestAuthorization :one
 SELECT 
-    tests.test_id
+    tests.test_id,
+    (tests.similarity_checked_at IS NOT NULL)::BOOLEAN AS similarity_checked
 FROM tests
 WHERE tests.test_id = $1
 AND tests.company_id = (SELECT company_id FROM companies WHERE companies.user_id = $2)
-AND tests.end_time < NOW();
+AND tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+);
 
 -- name: TestData :one
 SELECT 
     tests.file_id,
+    tests.upload_method::TEXT AS upload_method,
     tests.test_id,
     tests.test_name,
     tests.q_count,
     TO_CHAR(tests.end_time, 'HH12:MI AM DD-MM-YYYY') AS end_time,
     tests.threshold,
+    tests.negative_marking,
+    tests.partial_credit,
+    tests.min_score,
     jobs.title,
     companies.company_name,
     companies.representative_email
@@ -862,12 +1316,36 @@ JOIN companies ON tests.company_id = companies.company_id
 JOIN jobs ON tests.job_id = jobs.job_id
 WHERE tests.test_id = $1;
 
+-- name: LockTestEvaluation :exec
+SELECT pg_advisory_xact_lock(sqlc.arg('test_id')::BIGINT);
+
+-- name: ResetTestPoints :exec
+UPDATE testresponses
+SET 
+    points = NULL,
+    max_points = NULL,
+    needs_grading = false
+FROM testresults
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND testresponses.graded_at IS NULL;
+
 -- name: ClearAnswersTable :exec
-DELETE FROM temp_correct_answers;
+DELETE FROM temp_correct_answers
+WHERE temp_correct_answers.test_id = $1;
 
 -- name: InsertAnswers :exec
-INSERT INTO temp_correct_answers (question_id, correct_answer, points)
-VALUES ($1, $2, $3);
+INSERT INTO temp_correct_answers (test_id, question_id, correct_answer, points, manual, multiple, section)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: AssignResponseSections :exec
+UPDATE testresponses
+SET section = temp_correct_answers.section
+FROM testresults, temp_correct_answers
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND temp_correct_answers.test_id = testresults.test_id
+AND testresponses.question_id = temp_correct_answers.question_id;
 
 -- name: UpdateTestResultURLUnprotected :exec
 UPDATE tests
@@ -878,10 +1356,16 @@ WHERE test_id = $1;
 -- name: EvaluateTestResult :one
 WITH tr AS (
     UPDATE testresponses
-    SET points = temp_correct_answers.points
-    FROM temp_correct_answers
-    WHERE testresponses.question_id = temp_correct_answers.question_id
+    SET 
+        points = temp_correct_answers.points,
+        max_points = temp_correct_answers.points
+    FROM testresults, temp_correct_answers
+    WHERE testresponses.result_id = testresults.result_id
+    AND testresults.test_id = $1
+    AND temp_correct_answers.test_id = testresults.test_id
+    AND testresponses.question_id = temp_correct_answers.question_id
     AND testresponses.response = temp_correct_answers.correct_answer
+    AND CARDINALITY(temp_correct_answers.correct_answer) > 0
     RETURNING testresponses.points, testresponses.result_id
 ),
 rs AS (
@@ -899,7 +1383,102 @@ up AS (
 )
 SELECT 
     SUM(temp_correct_answers.points) AS totalpoints
-FROM temp_correct_answers;
+FROM temp_correct_answers
+WHERE temp_correct_answers.test_id = $1;
+
+-- name: FlagResponsesForGrading :exec
+UPDATE testresponses
+SET 
+    needs_grading = true,
+    max_points = temp_correct_answers.points
+FROM testresults, temp_correct_answers
+WHERE testresponses.result_id = testresults.result_id
+AND testresults.test_id = $1
+AND temp_correct_answers.test_id = testresults.test_id
+AND testresponses.question_id = temp_correct_answers.question_id
+AND temp_correct_answers.manual = true
+AND testresponses.graded_at IS NULL
+AND testresponses.points IS NULL;
+
+-- name: ApplyMarkingScheme :exec
+WITH scored AS (
+    SELECT 
+        testresponses.response_id,
+        temp_correct_answers.points,
+        CASE WHEN sqlc.arg('partial_credit')::BOOLEAN AND temp_correct_answers.multiple THEN
+            ROUND(temp_correct_answers.points * (
+                CARDINALITY(ARRAY(SELECT UNNEST(testresponses.response) INTERSECT SELECT UNNEST(temp_correct_answers.correct_answer)))
+                - CARDINALITY(ARRAY(SELECT UNNEST(testresponses.response) EXCEPT SELECT UNNEST(temp_correct_answers.correct_answer)))
+            )::NUMERIC / CARDINALITY(temp_correct_answers.correct_answer))::INT
+        ELSE 0 END AS partial
+    FROM testresponses
+    JOIN testresults ON testresponses.result_id = testresults.result_id
+    JOIN temp_correct_answers ON temp_correct_answers.test_id = testresults.test_id AND testresponses.question_id = temp_correct_answers.question_id
+    WHERE testresults.test_id = sqlc.arg('test_id')
+    AND temp_correct_answers.manual = false
+    AND CARDINALITY(temp_correct_answers.correct_answer) > 0
+    AND CARDINALITY(testresponses.response) > 0
+    AND testresponses.response <> temp_correct_answers.correct_answer
+)
+UPDATE testresponses
+SET 
+    points = CASE WHEN scored.partial > 0 THEN scored.partial
+        ELSE -ROUND(scored.points * sqlc.arg('negative_marking')::INT / 100.0)::INT END,
+    max_points = scored.points
+FROM scored
+WHERE testresponses.response_id = scored.response_id;
+
+-- name: RecomputeTestScores :exec
+UPDATE testresults
+SET 
+    score = GREATEST(
+        COALESCE((SELECT SUM(testresponses.points) FROM testresponses WHERE testresponses.result_id = testresults.result_id), 0),
+        (SELECT tests.min_score FROM tests WHERE tests.test_id = testresults.test_id)
+    )
+WHERE testresults.test_id = $1;
+
+-- name: PendingGradingCount :one
+SELECT 
+    COUNT(*) AS pending
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresults.test_id = $1
+AND testresponses.needs_grading = true
+AND testresponses.graded_at IS NULL;
+
+-- name: GradingQueue :many
+SELECT 
+    testresponses.response_id,
+    testresponses.result_id,
+    testresponses.question_id,
+    testresponses.response,
+    testresponses.max_points,
+    students.student_name,
+    students.roll_number
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+JOIN students ON testresults.user_id = students.user_id
+WHERE testresults.test_id = $1
+AND testresponses.needs_grading = true
+AND testresponses.graded_at IS NULL
+ORDER BY testresponses.question_id, testresponses.response_id;
+
+-- name: ResponseForGrading :one
+SELECT 
+    testresponses.needs_grading,
+    testresponses.max_points
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresponses.response_id = $1
+AND testresults.test_id = $2;
+
+-- name: GradeResponse :exec
+UPDATE testresponses
+SET 
+    points = $2,
+    grader_comment = $3,
+    graded_at = NOW()
+WHERE testresponses.response_id = $1;
 
 -- name: CumulativeResultData :many
 WITH tr AS (
@@ -927,19 +1506,209 @@ SELECT *
 FROM main;
 
 -- name: TestPassFailCount :one
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = $1
+),
+failed AS (
+    SELECT DISTINCT 
+        testresults.result_id
+    FROM testresults
+    CROSS JOIN sections
+    WHERE testresults.test_id = $1
+    AND sections.min_score IS NOT NULL
+    AND COALESCE((
+        SELECT SUM(testresponses.points) 
+        FROM testresponses 
+        WHERE testresponses.result_id = testresults.result_id 
+        AND testresponses.section = sections.section
+    ), 0) < sections.min_score
+)
 SELECT
-    COUNT(CASE WHEN testresults.score >= $2 THEN 1 ELSE NULL END) AS pass_count,
-    COUNT(CASE WHEN testresults.score < $2 THEN 1 ELSE NULL END) AS fail_count
+    COUNT(CASE WHEN testresults.score >= $2 AND failed.result_id IS NULL THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN testresults.score < $2 OR failed.result_id IS NOT NULL THEN 1 ELSE NULL END) AS fail_count
 FROM testresults
+LEFT JOIN failed ON testresults.result_id = failed.result_id
 WHERE testresults.test_id = $1;
 
+-- name: ItemAnalysis :many
+WITH attempts AS (
+    SELECT 
+        testresults.result_id,
+        ROW_NUMBER() OVER (ORDER BY COALESCE(testresults.score, 0) DESC, testresults.result_id) AS position,
+        COUNT(*) OVER () AS total
+    FROM testresults
+    WHERE testresults.test_id = $1
+    AND testresults.end_time IS NOT NULL
+),
+groups AS (
+    SELECT 
+        attempts.result_id,
+        attempts.total,
+        attempts.position <= CEIL(attempts.total * 0.27) AS top_group,
+        attempts.position > attempts.total - CEIL(attempts.total * 0.27) AS bottom_group
+    FROM attempts
+)
+SELECT 
+    testresponses.question_id,
+    COUNT(*) AS attempted,
+    COUNT(CASE WHEN testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS correct,
+    COUNT(CASE WHEN groups.top_group AND testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS top_correct,
+    COUNT(CASE WHEN groups.bottom_group AND testresponses.points > 0 AND testresponses.points >= COALESCE(testresponses.max_points, testresponses.points) THEN 1 ELSE NULL END) AS bottom_correct,
+    COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY testresponses.time_taken), 0)::FLOAT8 AS median_time_taken
+FROM testresponses
+JOIN groups ON testresponses.result_id = groups.result_id
+GROUP BY testresponses.question_id;
+
+-- name: ItemAnalysisCandidates :one
+SELECT 
+    COUNT(*) AS candidates,
+    CEIL(COUNT(*) * 0.27)::BIGINT AS group_size
+FROM testresults
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL;
+
+-- name: ItemAnalysisAttempts :many
+SELECT 
+    testresults.user_id,
+    tests.shuffle_seed
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL;
+
+-- name: ItemOptionFrequency :many
+SELECT 
+    testresponses.question_id,
+    options.option::TEXT AS option,
+    COUNT(*) AS picks
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+CROSS JOIN LATERAL UNNEST(testresponses.response) AS options(option)
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
+GROUP BY testresponses.question_id, options.option;
+
+-- name: NewResultVersion :one
+INSERT INTO resultversions (test_id, version, reason, threshold, cutoff_marks, total_points)
+VALUES ($1, (SELECT COALESCE(MAX(resultversions.version), 0) + 1 FROM resultversions WHERE resultversions.test_id = $1), $2, $3, $4, $5)
+RETURNING version_id, version;
+
+-- name: SnapshotResultScores :exec
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = sqlc.arg('test_id')
+),
+failed AS (
+    SELECT DISTINCT 
+        testresults.result_id
+    FROM testresults
+    CROSS JOIN sections
+    WHERE testresults.test_id = sqlc.arg('test_id')
+    AND sections.min_score IS NOT NULL
+    AND COALESCE((
+        SELECT SUM(testresponses.points) 
+        FROM testresponses 
+        WHERE testresponses.result_id = testresults.result_id 
+        AND testresponses.section = sections.section
+    ), 0) < sections.min_score
+)
+INSERT INTO resultversionscores (version_id, user_id, score, passed)
+SELECT 
+    sqlc.arg('version_id'),
+    testresults.user_id,
+    testresults.score,
+    (COALESCE(testresults.score, 0) >= sqlc.arg('cutoff_marks')::BIGINT AND failed.result_id IS NULL)
+FROM testresults
+LEFT JOIN failed ON testresults.result_id = failed.result_id
+WHERE testresults.test_id = sqlc.arg('test_id');
+
+-- name: ListResultVersions :many
+SELECT 
+    resultversions.version,
+    resultversions.reason,
+    resultversions.threshold,
+    resultversions.cutoff_marks,
+    resultversions.total_points,
+    TO_CHAR(resultversions.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+    COUNT(CASE WHEN resultversionscores.passed THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN NOT resultversionscores.passed THEN 1 ELSE NULL END) AS fail_count
+FROM resultversions
+LEFT JOIN resultversionscores ON resultversions.version_id = resultversionscores.version_id
+WHERE resultversions.test_id = $1
+GROUP BY resultversions.version_id
+ORDER BY resultversions.version DESC;
+
+-- name: ResultVersionDiff :many
+WITH old AS (
+    SELECT resultversionscores.user_id, resultversionscores.score, resultversionscores.passed
+    FROM resultversionscores
+    JOIN resultversions ON resultversionscores.version_id = resultversions.version_id
+    WHERE resultversions.test_id = sqlc.arg('test_id')
+    AND resultversions.version = sqlc.arg('from_version')
+),
+new AS (
+    SELECT resultversionscores.user_id, resultversionscores.score, resultversionscores.passed
+    FROM resultversionscores
+    JOIN resultversions ON resultversionscores.version_id = resultversions.version_id
+    WHERE resultversions.test_id = sqlc.arg('test_id')
+    AND resultversions.version = sqlc.arg('to_version')
+)
+SELECT 
+    students.student_id,
+    students.student_name,
+    students.roll_number,
+    old.score AS old_score,
+    new.score AS new_score,
+    old.passed AS old_passed,
+    new.passed AS new_passed
+FROM old
+FULL JOIN new ON old.user_id = new.user_id
+JOIN students ON students.user_id = COALESCE(old.user_id, new.user_id)
+WHERE old.passed IS DISTINCT FROM new.passed
+ORDER BY new.passed NULLS LAST, students.roll_number;
+
+-- name: LatestResultVersions :many
+SELECT 
+    resultversions.version
+FROM resultversions
+WHERE resultversions.test_id = $1
+ORDER BY resultversions.version DESC
+LIMIT 2;
+
+-- name: ResultVersionExists :one
+SELECT 
+    resultversions.version_id
+FROM resultversions
+WHERE resultversions.test_id = $1
+AND resultversions.version = $2;
+
+-- name: UpdateAnswerKey :one
+UPDATE testquestions
+SET 
+    correct_answer = $3,
+    points = $4
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id;
+
 -- name: StudentTestResult :many
 WITH tr AS (
     SELECT 
         result_id,
         SUM(time_taken) AS total_time_taken,
         COUNT(result_id) AS questions_attempted,
-        COUNT(CASE WHEN points > 0 THEN 1 ELSE NULL END) AS correct_response
+        COUNT(CASE WHEN points > 0 AND points >= COALESCE(max_points, points) THEN 1 ELSE NULL END) AS correct_response,
+        COUNT(CASE WHEN points > 0 AND points < max_points THEN 1 ELSE NULL END) AS partial_response,
+        COUNT(CASE WHEN points <= 0 THEN 1 ELSE NULL END) AS wrong_response,
+        COALESCE(SUM(CASE WHEN points > 0 THEN points ELSE 0 END), 0)::BIGINT AS points_earned,
+        COALESCE(SUM(CASE WHEN points < 0 THEN -points ELSE 0 END), 0)::BIGINT AS points_deducted
     FROM testresponses
     GROUP BY result_id
 )
@@ -957,6 +1726,14 @@ SELECT
     tr.total_time_taken,
     tr.questions_attempted,
     tr.correct_response,
+    tr.partial_response,
+    tr.wrong_response,
+    tr.points_earned,
+    tr.points_deducted,
+
+    testresults.extra_minutes,
+    COALESCE(TO_CHAR(testresults.override_end_time, 'HH12:MI AM DD-MM-YYYY'), '')::TEXT AS override_end_time,
+    testresults.fresh_attempt,
 
     users.user_uuid
 FROM testresults
@@ -965,6 +1742,596 @@ JOIN users ON testresults.user_id = users.user_id
 JOIN tr ON testresults.result_id = tr.result_id
 WHERE testresults.test_id = $1;
 
+-- name: ProctorAttemptData :one
+SELECT 
+    students.student_name,
+    students.roll_number,
+    TO_CHAR(testresults.start_time, 'HH12:MI:SS AM DD-MM-YYYY') AS start_time,
+    TO_CHAR(testresults.end_time, 'HH12:MI:SS AM DD-MM-YYYY') AS end_time
+FROM testresults
+JOIN tests ON testresults.test_id = tests.test_id
+JOIN students ON testresults.user_id = students.user_id
+WHERE testresults.test_id = $1
+AND testresults.result_id = $2
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $3);
+
+-- name: ProctorTimeline :many
+SELECT 
+    proctorevents.event_type,
+    proctorevents.detail,
+    proctorevents.ip_address,
+    TO_CHAR(proctorevents.created_at, 'HH12:MI:SS AM DD-MM-YYYY') AS created_at,
+    COALESCE(EXTRACT(EPOCH FROM proctorevents.created_at - testresults.start_time), 0)::BIGINT AS seconds_in
+FROM proctorevents
+JOIN testresults ON proctorevents.result_id = testresults.result_id
+WHERE proctorevents.result_id = $1
+ORDER BY proctorevents.created_at, proctorevents.event_id;
+
+-- name: IntegrityFlags :many
+SELECT 
+    testresults.result_id,
+    students.student_name,
+    students.roll_number,
+    COUNT(CASE WHEN proctorevents.event_type = 'TabSwitch' THEN 1 ELSE NULL END) AS tab_switches,
+    COUNT(CASE WHEN proctorevents.event_type = 'FocusLoss' THEN 1 ELSE NULL END) AS focus_losses,
+    COUNT(CASE WHEN proctorevents.event_type = 'FullscreenExit' THEN 1 ELSE NULL END) AS fullscreen_exits,
+    COUNT(CASE WHEN proctorevents.event_type IN ('Copy', 'Paste') THEN 1 ELSE NULL END) AS copy_pastes,
+    COUNT(CASE WHEN proctorevents.event_type = 'IPChange' THEN 1 ELSE NULL END) AS ip_changes
+FROM testresults
+JOIN students ON testresults.user_id = students.user_id
+JOIN proctorevents ON testresults.result_id = proctorevents.result_id
+WHERE testresults.test_id = $1
+GROUP BY testresults.result_id, students.student_name, students.roll_number
+HAVING COUNT(CASE WHEN proctorevents.event_type = 'IPChange' THEN 1 ELSE NULL END) > 0
+OR COUNT(proctorevents.event_id) >= sqlc.arg('min_events')
+ORDER BY COUNT(proctorevents.event_id) DESC, testresults.result_id;
+
+
+-- name: SimilarityPoller :many
+SELECT  
+    tests.test_id
+FROM tests
+WHERE tests.end_time + tests.grace_period * INTERVAL '1 minute' < NOW()
+AND tests.similarity_checked_at IS NULL
+AND tests.result_url IS NULL
+AND NOT EXISTS (
+    SELECT 1
+    FROM testresults
+    WHERE testresults.test_id = tests.test_id
+    AND testresults.end_time IS NULL
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM testoverrides
+    WHERE testoverrides.test_id = tests.test_id
+    AND testoverrides.end_time + (tests.grace_period + testoverrides.extra_minutes) * INTERVAL '1 minute' > NOW()
+)
+ORDER BY tests.end_time;
+
+-- name: SimilarityResponses :many
+SELECT 
+    testresponses.result_id,
+    testresponses.question_id,
+    testresponses.response,
+    testresponses.time_taken
+FROM testresponses
+JOIN testresults ON testresponses.result_id = testresults.result_id
+WHERE testresults.test_id = $1
+AND testresults.end_time IS NOT NULL
+ORDER BY testresponses.result_id, testresponses.question_id;
+
+-- name: ClearSimilarityFlags :exec
+DELETE FROM similarityflags
+WHERE similarityflags.test_id = $1;
+
+-- name: InsertSimilarityFlag :exec
+INSERT INTO similarityflags (
+    test_id, result_a, result_b, common_questions, shared_wrong, time_difference
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: MarkSimilarityChecked :exec
+UPDATE tests
+SET similarity_checked_at = NOW()
+WHERE tests.test_id = $1;
+
+-- name: SimilarityFlags :many
+SELECT 
+    student_a.student_name AS student_name_a,
+    student_a.roll_number AS roll_number_a,
+    student_b.student_name AS student_name_b,
+    student_b.roll_number AS roll_number_b,
+    similarityflags.common_questions,
+    similarityflags.shared_wrong,
+    similarityflags.time_difference
+FROM similarityflags
+JOIN testresults AS result_a ON similarityflags.result_a = result_a.result_id
+JOIN testresults AS result_b ON similarityflags.result_b = result_b.result_id
+JOIN students AS student_a ON result_a.user_id = student_a.user_id
+JOIN students AS student_b ON result_b.user_id = student_b.user_id
+WHERE similarityflags.test_id = $1
+ORDER BY similarityflags.shared_wrong DESC, similarityflags.time_difference NULLS LAST, similarityflags.flag_id;
+
+
+-- name: SetAutoShortlist :exec
+UPDATE tests
+SET auto_shortlist = $2
+WHERE tests.test_id = $1;
+
+-- name: TestAutoShortlist :one
+SELECT 
+    tests.auto_shortlist
+FROM tests
+WHERE tests.test_id = $1;
+
+-- name: ShortlistPreview :many
+WITH latest AS (
+    SELECT 
+        resultversions.version_id
+    FROM resultversions
+    WHERE resultversions.test_id = sqlc.arg('test_id')
+    ORDER BY resultversions.version DESC
+    LIMIT 1
+)
+SELECT 
+    applications.application_id,
+    applications.status::TEXT AS status,
+    students.student_name,
+    students.roll_number,
+    resultversionscores.score,
+    COALESCE(resultversionscores.passed, false)::BOOLEAN AS passed
+FROM tests
+JOIN applications ON applications.job_id = tests.job_id
+JOIN students ON applications.student_id = students.student_id
+LEFT JOIN resultversionscores ON resultversionscores.version_id = (SELECT latest.version_id FROM latest)
+    AND resultversionscores.user_id = students.user_id
+WHERE tests.test_id = sqlc.arg('test_id')
+AND applications.status IN ('Applied', 'UnderReview')
+ORDER BY COALESCE(resultversionscores.passed, false) DESC, resultversionscores.score DESC NULLS LAST, applications.application_id;
+
+-- name: NewShortlistBatch :one
+INSERT INTO shortlistbatches (
+    test_id
+) VALUES (
+    $1
+)
+ON CONFLICT (test_id) DO NOTHING
+RETURNING batch_id;
+
+-- name: ApplyShortlistBatch :many
+WITH latest AS (
+    SELECT 
+        resultversions.version_id
+    FROM resultversions
+    WHERE resultversions.test_id = sqlc.arg('test_id')
+    ORDER BY resultversions.version DESC
+    LIMIT 1
+),
+candidates AS (
+    SELECT 
+        applications.application_id,
+        applications.status AS previous_status,
+        (CASE WHEN COALESCE(resultversionscores.passed, false) THEN 'ShortListed' ELSE 'Rejected' END)::application_status AS new_status
+    FROM tests
+    JOIN applications ON applications.job_id = tests.job_id
+    JOIN students ON applications.student_id = students.student_id
+    LEFT JOIN resultversionscores ON resultversionscores.version_id = (SELECT latest.version_id FROM latest)
+        AND resultversionscores.user_id = students.user_id
+    WHERE tests.test_id = sqlc.arg('test_id')
+    AND applications.status IN ('Applied', 'UnderReview')
+    FOR UPDATE OF applications
+),
+changes AS (
+    INSERT INTO shortlistchanges (batch_id, application_id, previous_status, new_status)
+    SELECT sqlc.arg('batch_id'), candidates.application_id, candidates.previous_status, candidates.new_status
+    FROM candidates
+    RETURNING shortlistchanges.application_id, shortlistchanges.new_status
+),
+upd AS (
+    UPDATE applications
+    SET status = changes.new_status
+    FROM changes
+    WHERE applications.application_id = changes.application_id
+    RETURNING applications.application_id, applications.student_id, applications.job_id, applications.status
+)
+SELECT 
+    upd.application_id,
+    upd.status::TEXT AS status,
+    students.user_id,
+    students.student_name,
+    students.student_email,
+    jobs.title,
+    companies.company_name
+FROM upd
+JOIN students ON upd.student_id = students.student_id
+JOIN jobs ON upd.job_id = jobs.job_id
+JOIN companies ON jobs.company_id = companies.company_id;
+
+-- name: LatestShortlistBatch :one
+SELECT 
+    shortlistbatches.batch_id,
+    shortlistbatches.undone_at
+FROM shortlistbatches
+WHERE shortlistbatches.test_id = $1
+ORDER BY shortlistbatches.batch_id DESC
+LIMIT 1;
+
+-- name: UndoShortlistBatch :many
+WITH undone AS (
+    UPDATE shortlistbatches
+    SET undone_at = NOW()
+    WHERE shortlistbatches.batch_id = $1
+    AND shortlistbatches.undone_at IS NULL
+    RETURNING shortlistbatches.batch_id
+),
+upd AS (
+    UPDATE applications
+    SET status = shortlistchanges.previous_status
+    FROM shortlistchanges
+    JOIN undone ON shortlistchanges.batch_id = undone.batch_id
+    WHERE applications.application_id = shortlistchanges.application_id
+    AND applications.status = shortlistchanges.new_status
+    RETURNING applications.application_id, applications.student_id, applications.status
+)
+SELECT 
+    upd.application_id,
+    upd.status::TEXT AS status,
+    students.user_id
+FROM upd
+JOIN students ON upd.student_id = students.student_id;
+
+
+-- name: TestPreviewData :one
+SELECT 
+    tests.upload_method::TEXT AS upload_method,
+    tests.file_id,
+    tests.duration,
+    tests.shuffle,
+    tests.shuffle_seed,
+    (tests.start_time <= NOW())::BOOLEAN AS started
+FROM tests
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2);
+
+-- name: PlacementPolicy :one
+SELECT 
+    block_after_accept,
+    dream_min_salary,
+    max_active_applications,
+    debar_after_declines,
+    updated_at
+FROM placementpolicy
+WHERE id = true;
+
+-- name: SetPlacementPolicy :exec
+INSERT INTO placementpolicy (
+    id, block_after_accept, dream_min_salary, max_active_applications, debar_after_declines
+) VALUES (
+    true, $1, $2, $3, $4
+)
+ON CONFLICT (id) DO UPDATE
+SET block_after_accept = EXCLUDED.block_after_accept,
+    dream_min_salary = EXCLUDED.dream_min_salary,
+    max_active_applications = EXCLUDED.max_active_applications,
+    debar_after_declines = EXCLUDED.debar_after_declines,
+    updated_at = NOW();
+
+-- name: PlacementStanding :one
+SELECT
+    CAST(COUNT(applications.application_id) FILTER (WHERE applications.status IN ('Applied', 'UnderReview', 'ShortListed', 'Offered')) AS BIGINT) AS active_applications,
+    CAST(COUNT(applications.application_id) FILTER (WHERE applications.offer_declined_at IS NOT NULL) AS BIGINT) AS declined_offers,
+    COALESCE(ARRAY_AGG(jobs.salary) FILTER (WHERE applications.status = 'Hired'), '{}')::TEXT[] AS accepted_salaries
+FROM applications
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.student_id = (SELECT student_id FROM students WHERE students.user_id = $1);
+
+-- name: ApplicationPlacement :one
+SELECT 
+    students.user_id,
+    jobs.salary
+FROM applications
+JOIN students ON students.student_id = applications.student_id
+JOIN jobs ON jobs.job_id = applications.job_id
+WHERE applications.application_id = $1;
+
+-- name: DrivePageToken :one
+SELECT 
+    drivestate.page_token
+FROM drivestate
+WHERE drivestate.id = true;
+
+-- name: SetDrivePageToken :exec
+INSERT INTO drivestate (
+    id, page_token
+) VALUES (
+    true, $1
+)
+ON CONFLICT (id) DO UPDATE
+SET page_token = EXCLUDED.page_token,
+    updated_at = NOW();
+
+-- name: UpsertSharedForm :exec
+INSERT INTO sharedforms (
+    form_id, responder_uri
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (form_id) DO UPDATE
+SET responder_uri = EXCLUDED.responder_uri;
+
+-- name: DeleteSharedForm :many
+DELETE FROM sharedforms
+WHERE sharedforms.form_id = $1
+RETURNING sharedforms.responder_uri;
+
+-- name: SharedFormByLink :one
+SELECT 
+    sharedforms.form_id
+FROM sharedforms
+WHERE sharedforms.responder_uri = $1;
+
+-- name: EditableTestData :one
+SELECT 
+    tests.upload_method::TEXT AS upload_method,
+    tests.end_time,
+    tests.duration,
+    (SELECT COUNT(*) FROM testresults WHERE testresults.test_id = tests.test_id) AS attempts
+FROM tests
+WHERE tests.test_id = $1
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2);
+
+-- name: GetTestQuestions :many
+SELECT 
+    testquestions.question_id,
+    testquestions.item_id,
+    testquestions.position,
+    testquestions.type,
+    testquestions.title,
+    testquestions.description,
+    testquestions.options,
+    testquestions.correct_answer,
+    testquestions.points,
+    testquestions.section_id,
+    testquestions.rule_id
+FROM testquestions
+LEFT JOIN testsections ON testquestions.section_id = testsections.section_id
+WHERE testquestions.test_id = $1
+ORDER BY testsections.position NULLS FIRST, testsections.section_id, testquestions.position, testquestions.question_id;
+
+-- name: InsertTestQuestion :one
+INSERT INTO testquestions (test_id, position, type, title, description, options, correct_answer, points, section_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING question_id;
+
+-- name: UpdateTestQuestion :one
+UPDATE testquestions
+SET 
+    position = $3,
+    type = $4,
+    title = $5,
+    description = $6,
+    options = $7,
+    correct_answer = $8,
+    points = $9,
+    section_id = $10
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id;
+
+-- name: DeleteTestQuestion :one
+DELETE FROM testquestions
+WHERE testquestions.question_id = $1
+AND testquestions.test_id = $2
+RETURNING question_id;
+
+-- name: SyncTestQCount :exec
+UPDATE tests
+SET 
+    q_count = (SELECT COUNT(*) FROM testquestions WHERE testquestions.test_id = $1 AND testquestions.rule_id IS NULL)
+        + (
+            SELECT COALESCE(SUM(LEAST(testdrawrules.count, (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id))), 0) 
+            FROM testdrawrules 
+            WHERE testdrawrules.test_id = $1
+        )
+WHERE tests.test_id = $1;
+
+-- name: ListBankQuestions :many
+SELECT 
+    bankquestions.question_id,
+    bankquestions.type,
+    bankquestions.title,
+    bankquestions.description,
+    bankquestions.options,
+    bankquestions.correct_answer,
+    bankquestions.tags,
+    bankquestions.difficulty
+FROM bankquestions
+WHERE bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $1)
+AND (sqlc.narg('tag')::TEXT IS NULL OR sqlc.narg('tag')::TEXT = ANY(bankquestions.tags))
+AND (sqlc.narg('difficulty')::TEXT IS NULL OR bankquestions.difficulty = sqlc.narg('difficulty')::TEXT)
+ORDER BY bankquestions.question_id;
+
+-- name: InsertBankQuestion :one
+INSERT INTO bankquestions (company_id, type, title, description, options, correct_answer, tags, difficulty)
+VALUES ((SELECT companies.company_id FROM companies WHERE companies.user_id = $1), $2, $3, $4, $5, $6, $7, $8)
+RETURNING question_id;
+
+-- name: UpdateBankQuestion :one
+UPDATE bankquestions
+SET 
+    type = $3,
+    title = $4,
+    description = $5,
+    options = $6,
+    correct_answer = $7,
+    tags = $8,
+    difficulty = $9
+WHERE bankquestions.question_id = $1
+AND bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+RETURNING question_id;
+
+-- name: DeleteBankQuestion :one
+DELETE FROM bankquestions
+WHERE bankquestions.question_id = $1
+AND bankquestions.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $2)
+RETURNING question_id;
+
+-- name: ListDrawRules :many
+SELECT 
+    testdrawrules.rule_id,
+    testdrawrules.tags,
+    testdrawrules.difficulty,
+    testdrawrules.count,
+    testdrawrules.points,
+    testdrawrules.position,
+    testdrawrules.section_id,
+    (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id) AS pool_size
+FROM testdrawrules
+WHERE testdrawrules.test_id = $1
+ORDER BY testdrawrules.position, testdrawrules.rule_id;
+
+-- name: InsertDrawRule :one
+INSERT INTO testdrawrules (test_id, tags, difficulty, count, points, position, section_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING rule_id;
+
+-- name: UpdateDrawRule :one
+UPDATE testdrawrules
+SET 
+    tags = $3,
+    difficulty = $4,
+    count = $5,
+    points = $6,
+    position = $7,
+    section_id = $8
+WHERE testdrawrules.rule_id = $1
+AND testdrawrules.test_id = $2
+RETURNING rule_id;
+
+-- name: DeleteDrawRule :one
+DELETE FROM testdrawrules
+WHERE testdrawrules.rule_id = $1
+AND testdrawrules.test_id = $2
+RETURNING rule_id;
+
+-- name: SyncDrawPool :exec
+WITH cleared AS (
+    DELETE FROM testquestions
+    WHERE testquestions.test_id = $1
+    AND testquestions.rule_id IS NOT NULL
+)
+INSERT INTO testquestions (test_id, position, type, title, description, options, correct_answer, points, section_id, rule_id)
+SELECT DISTINCT ON (bankquestions.question_id)
+    testdrawrules.test_id,
+    testdrawrules.position,
+    bankquestions.type,
+    bankquestions.title,
+    bankquestions.description,
+    bankquestions.options,
+    bankquestions.correct_answer,
+    testdrawrules.points,
+    testdrawrules.section_id,
+    testdrawrules.rule_id
+FROM testdrawrules
+JOIN tests ON testdrawrules.test_id = tests.test_id
+JOIN bankquestions ON bankquestions.company_id = tests.company_id
+WHERE testdrawrules.test_id = $1
+AND (CARDINALITY(testdrawrules.tags) = 0 OR bankquestions.tags && testdrawrules.tags)
+AND (testdrawrules.difficulty IS NULL OR bankquestions.difficulty = testdrawrules.difficulty)
+ORDER BY bankquestions.question_id, testdrawrules.position, testdrawrules.rule_id;
+
+-- name: DrawTotalPoints :one
+SELECT 
+    (SELECT COUNT(*) FROM testdrawrules WHERE testdrawrules.test_id = $1) AS rules,
+    ((
+        SELECT COALESCE(SUM(testquestions.points), 0) 
+        FROM testquestions 
+        WHERE testquestions.test_id = $1 
+        AND testquestions.rule_id IS NULL
+    ) + (
+        SELECT COALESCE(SUM(LEAST(testdrawrules.count, (SELECT COUNT(*) FROM testquestions WHERE testquestions.rule_id = testdrawrules.rule_id)) * testdrawrules.points), 0)
+        FROM testdrawrules
+        WHERE testdrawrules.test_id = $1
+    ))::BIGINT AS total_points;
+
+-- name: ShuffleAuditData :one
+SELECT 
+    tests.file_id,
+    tests.upload_method::TEXT AS upload_method,
+    tests.shuffle,
+    tests.shuffle_seed,
+    testresults.user_id
+FROM tests
+JOIN testresults ON tests.test_id = testresults.test_id
+WHERE tests.test_id = $1
+AND testresults.result_id = $2
+AND tests.company_id = (SELECT companies.company_id FROM companies WHERE companies.user_id = $3);
+
+-- name: ListTestSections :many
+SELECT 
+    testsections.section_id,
+    testsections.name,
+    testsections.position,
+    testsections.duration,
+    testsections.min_score
+FROM testsections
+WHERE testsections.test_id = $1
+ORDER BY testsections.position, testsections.section_id;
+
+-- name: InsertTestSection :one
+INSERT INTO testsections (test_id, name, position, duration, min_score)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING section_id;
+
+-- name: UpdateTestSection :one
+UPDATE testsections
+SET 
+    name = $3,
+    position = $4,
+    duration = $5,
+    min_score = $6
+WHERE testsections.section_id = $1
+AND testsections.test_id = $2
+RETURNING section_id;
+
+-- name: DeleteTestSection :one
+DELETE FROM testsections
+WHERE testsections.section_id = $1
+AND testsections.test_id = $2
+RETURNING section_id;
+
+-- name: SectionPassFailCount :many
+WITH sections AS (
+    SELECT 
+        (ROW_NUMBER() OVER (ORDER BY testsections.position, testsections.section_id) - 1)::INT AS section,
+        testsections.name,
+        testsections.min_score
+    FROM testsections
+    WHERE testsections.test_id = $1
+),
+scores AS (
+    SELECT 
+        testresults.result_id,
+        sections.section,
+        COALESCE(SUM(testresponses.points), 0) AS score
+    FROM testresults
+    CROSS JOIN sections
+    LEFT JOIN testresponses ON (testresponses.result_id = testresults.result_id AND testresponses.section = sections.section)
+    WHERE testresults.test_id = $1
+    GROUP BY testresults.result_id, sections.section
+)
+SELECT 
+    sections.section,
+    sections.name,
+    sections.min_score,
+    COUNT(CASE WHEN scores.result_id IS NOT NULL AND (sections.min_score IS NULL OR scores.score >= sections.min_score) THEN 1 ELSE NULL END) AS pass_count,
+    COUNT(CASE WHEN scores.score < sections.min_score THEN 1 ELSE NULL END) AS fail_count
+FROM sections
+LEFT JOIN scores ON scores.section = sections.section
+GROUP BY sections.section, sections.name, sections.min_score
+ORDER BY sections.section;
+
 
 
 
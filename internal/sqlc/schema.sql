@@ -47,12 +47,36 @@ CREATE TABLE jobs (
     extras JSON,
     active_status boolean NOT NULL DEFAULT true,
     description TEXT,
+    -- eligibility of the applicants, an empty list allows any
+    min_cgpa DOUBLE PRECISION,
+    eligible_departments TEXT[] NOT NULL DEFAULT '{}',
+    eligible_courses TEXT[] NOT NULL DEFAULT '{}',
+    eligible_years TEXT[] NOT NULL DEFAULT '{}',
+    eligible_genders TEXT[] NOT NULL DEFAULT '{}',
+    -- the job is closed by the deadline poller once this passes, no deadline keeps it open until closed by the company
+    application_deadline TIMESTAMPTZ,
+    -- set when the deadline poller closed the job, only such a job is reopened by moving its deadline
+    closed_by_deadline BOOLEAN NOT NULL DEFAULT false,
     CONSTRAINT jobs_pkey PRIMARY KEY (job_id),
     CONSTRAINT jobs_company_id_fkey FOREIGN KEY (company_id)
         REFERENCES companies(company_id)
         ON DELETE CASCADE
 );
 
+-- the ordered stages of the hiring pipeline of a job, run while its applications are shortlisted
+CREATE TABLE jobstages (
+    stage_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    job_id BIGINT NOT NULL,
+    position INT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT jobstages_pkey PRIMARY KEY (stage_id),
+    CONSTRAINT unique_jobstages_job_id_position UNIQUE (job_id, position),
+    CONSTRAINT jobs_jobstages_fkey FOREIGN KEY (job_id)
+        REFERENCES jobs(job_id)
+        ON DELETE CASCADE
+);
+
 CREATE TABLE students (
     student_id BIGINT NOT NULL PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY,
     student_name TEXT NOT NULL,
@@ -86,6 +110,11 @@ CREATE TABLE applications (
     data_url TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     status application_status NOT NULL DEFAULT 'Applied',
+    -- the stage of the job's pipeline the application has reached, a rejected application keeps the stage it was rejected at
+    stage_id BIGINT,
+    -- a declined offer is rejected, stamped to tell it apart for the placement policy
+    offer_declined_at TIMESTAMPTZ,
+    CONSTRAINT jobstages_app_fkey FOREIGN KEY (stage_id) REFERENCES jobstages(stage_id) ON DELETE SET NULL,
     CONSTRAINT students_app_pkey FOREIGN KEY (student_id) REFERENCES students(student_id) ON DELETE CASCADE,
     CONSTRAINT jobs_pkey FOREIGN KEY (job_id) REFERENCES jobs(job_id) ON DELETE CASCADE
 );
@@ -120,7 +149,24 @@ CREATE TABLE tests (
     result_url TEXT,
     threshold INTEGER NOT NULL DEFAULT 40,
     published BOOLEAN NOT NULL DEFAULT false,
+    negative_marking INTEGER NOT NULL DEFAULT 0 CHECK (negative_marking BETWEEN 0 AND 100),
+    partial_credit BOOLEAN NOT NULL DEFAULT false,
+    min_score INTEGER,
+    shuffle BOOLEAN NOT NULL DEFAULT false,
+    shuffle_seed BIGINT NOT NULL DEFAULT (random() * 4611686018427387903)::BIGINT,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    late_entry BIGINT,
+    grace_period BIGINT NOT NULL DEFAULT 0,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- set once the attempts have been compared for similar answers, the result draft waits for it
+    similarity_checked_at TIMESTAMPTZ,
+    -- the applications of the job are shortlisted or rejected by the cutoff when the results are published
+    auto_shortlist BOOLEAN NOT NULL DEFAULT false,
+    -- set once the applicants have been emailed about the test, a test built on the platform waits for its first question
+    announced_at TIMESTAMPTZ,
+    CONSTRAINT test_window_check CHECK (start_time < end_time),
+    CONSTRAINT test_late_entry_check CHECK (late_entry IS NULL OR late_entry > 0),
+    CONSTRAINT test_grace_period_check CHECK (grace_period >= 0),
     CONSTRAINT companies_tests_pkey FOREIGN KEY (company_id) REFERENCES companies(company_id) ON DELETE CASCADE ON UPDATE CASCADE,
     CONSTRAINT jobs_pkey FOREIGN KEY (jod_id) REFERENCES jobs(job_id) ON DELETE CASCADE ON UPDATE CASCADE
 );
@@ -132,9 +178,35 @@ CREATE TABLE testresults(
     start_time TIMESTAMP WITH TIME ZONE ,
     end_time TIMESTAMP WITH TIME ZONE ,
     score BIGINT DEFAULT 0,
+    extra_minutes INT NOT NULL DEFAULT 0,
+    override_end_time TIMESTAMP WITH TIME ZONE,
+    fresh_attempt BOOLEAN NOT NULL DEFAULT false,
     CONSTRAINT "testresults_result_id_pkey" PRIMARY KEY (result_id)
 );
 
+CREATE TABLE testoverrides (
+    override_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    extra_minutes INT NOT NULL DEFAULT 0,
+    end_time TIMESTAMP WITH TIME ZONE,
+    fresh_attempt BOOLEAN NOT NULL DEFAULT false,
+    reason TEXT NOT NULL,
+    granted_by BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT testoverrides_pkey PRIMARY KEY (override_id),
+    CONSTRAINT unique_override_test_id_user_id UNIQUE (test_id, user_id),
+    CONSTRAINT override_extra_minutes_check CHECK (extra_minutes >= 0),
+    CONSTRAINT tests_testoverrides_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT users_testoverrides_fkey FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
 CREATE TABLE testresponses (
     response_id BIGINT NOT NULL DEFAULT nextval('testresponses_response_id_seq'::regclass),
     result_id BIGINT NOT NULL,
@@ -143,6 +215,11 @@ CREATE TABLE testresponses (
     time_taken BIGINT,
     points INT,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    needs_grading BOOLEAN NOT NULL DEFAULT false,
+    max_points INT,
+    grader_comment TEXT,
+    graded_at TIMESTAMP WITH TIME ZONE,
+    section INT NOT NULL DEFAULT 0,
     CONSTRAINT response_id_pkey PRIMARY KEY (response_id),
     CONSTRAINT unique_result_id_q_id UNIQUE (result_id, question_id),
     CONSTRAINT result_id_testresults_fkey FOREIGN KEY (result_id)
@@ -151,10 +228,183 @@ CREATE TABLE testresponses (
         ON DELETE CASCADE
 );
 
+CREATE TABLE proctorevents (
+    event_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    result_id BIGINT NOT NULL,
+    event_type TEXT NOT NULL,
+    detail TEXT,
+    ip_address TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT proctorevents_pkey PRIMARY KEY (event_id),
+    CONSTRAINT proctor_event_type_check CHECK (event_type IN ('TabSwitch', 'FocusLoss', 'FullscreenExit', 'Copy', 'Paste', 'IPChange')),
+    CONSTRAINT testresults_proctorevents_fkey FOREIGN KEY (result_id)
+        REFERENCES testresults(result_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE TABLE resultversions (
+    version_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    reason TEXT NOT NULL,
+    threshold INT NOT NULL,
+    cutoff_marks BIGINT NOT NULL,
+    total_points BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT resultversions_pkey PRIMARY KEY (version_id),
+    CONSTRAINT unique_resultversions_test_id_version UNIQUE (test_id, version),
+    CONSTRAINT tests_resultversions_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+-- the scores are kept by the user and not the attempt, a fresh attempt deletes the old one but not its history
+CREATE TABLE resultversionscores (
+    version_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    score BIGINT,
+    passed BOOLEAN NOT NULL,
+    CONSTRAINT resultversionscores_pkey PRIMARY KEY (version_id, user_id),
+    CONSTRAINT resultversions_resultversionscores_fkey FOREIGN KEY (version_id)
+        REFERENCES resultversions(version_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT users_resultversionscores_fkey FOREIGN KEY (user_id)
+        REFERENCES users(user_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+-- the pairs of attempts of a test with suspiciously similar answers, for the company to review in the result draft
+-- result_a is always the lower result id of the pair
+CREATE TABLE similarityflags (
+    flag_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    result_a BIGINT NOT NULL,
+    result_b BIGINT NOT NULL,
+    common_questions INT NOT NULL,
+    shared_wrong INT NOT NULL,
+    time_difference FLOAT8,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT similarityflags_pkey PRIMARY KEY (flag_id),
+    CONSTRAINT unique_similarityflags_pair UNIQUE (result_a, result_b),
+    CONSTRAINT similarityflags_pair_check CHECK (result_a < result_b),
+    CONSTRAINT tests_similarityflags_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT testresults_similarityflags_a_fkey FOREIGN KEY (result_a)
+        REFERENCES testresults(result_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT testresults_similarityflags_b_fkey FOREIGN KEY (result_b)
+        REFERENCES testresults(result_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+-- the applications moved by the cutoff of a test at once, with their previous status so the batch can be undone
+-- a test is shortlisted once, when its results are published, an undone batch is kept
+CREATE TABLE shortlistbatches (
+    batch_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    undone_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT shortlistbatches_pkey PRIMARY KEY (batch_id),
+    CONSTRAINT unique_shortlistbatches_test_id UNIQUE (test_id),
+    CONSTRAINT tests_shortlistbatches_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE TABLE shortlistchanges (
+    batch_id BIGINT NOT NULL,
+    application_id BIGINT NOT NULL,
+    previous_status application_status NOT NULL,
+    new_status application_status NOT NULL,
+    CONSTRAINT shortlistchanges_pkey PRIMARY KEY (batch_id, application_id),
+    CONSTRAINT shortlistbatches_shortlistchanges_fkey FOREIGN KEY (batch_id)
+        REFERENCES shortlistbatches(batch_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT applications_shortlistchanges_fkey FOREIGN KEY (application_id)
+        REFERENCES applications(application_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+-- the page token of the Drive changes of the collaborator account, a single row
+-- the changes after it are read on the next sync, so no change is missed across restarts
+CREATE TABLE drivestate (
+    id BOOLEAN NOT NULL DEFAULT true,
+    page_token TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT drivestate_pkey PRIMARY KEY (id),
+    CONSTRAINT drivestate_single_row_check CHECK (id)
+);
+
+-- the placement policy set by the admins, a single row, a rule that is not set does not apply
+-- a dream job pays at least dream_min_salary, in rupees a year, a student who accepted an offer can still take one dream job
+CREATE TABLE placementpolicy (
+    id BOOLEAN NOT NULL DEFAULT true,
+    block_after_accept BOOLEAN NOT NULL DEFAULT false,
+    dream_min_salary DOUBLE PRECISION,
+    max_active_applications INT,
+    debar_after_declines INT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT placementpolicy_pkey PRIMARY KEY (id),
+    CONSTRAINT placementpolicy_single_row_check CHECK (id)
+);
+
+-- the forms shared with the collaborator account, found through the Drive changes
+CREATE TABLE sharedforms (
+    form_id TEXT NOT NULL,
+    responder_uri TEXT NOT NULL,
+    discovered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT sharedforms_pkey PRIMARY KEY (form_id),
+    CONSTRAINT unique_sharedforms_responder_uri UNIQUE (responder_uri)
+);
+
+-- a snapshot of a job as it was posted and after every edit, version 1 is the job as posted
+-- a job posted before the edit history has its version 1 taken from the job as it was before its first edit
+CREATE TABLE jobversions (
+    job_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    title TEXT NOT NULL,
+    location TEXT NOT NULL,
+    type TEXT NOT NULL,
+    salary TEXT NOT NULL,
+    skills TEXT[] NOT NULL,
+    position TEXT NOT NULL,
+    description TEXT,
+    extras JSON,
+    min_cgpa DOUBLE PRECISION,
+    eligible_departments TEXT[] NOT NULL,
+    eligible_courses TEXT[] NOT NULL,
+    eligible_years TEXT[] NOT NULL,
+    eligible_genders TEXT[] NOT NULL,
+    application_deadline TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT jobversions_pkey PRIMARY KEY (job_id, version),
+    CONSTRAINT jobs_jobversions_fkey FOREIGN KEY (job_id)
+        REFERENCES jobs(job_id)
+        ON DELETE CASCADE
+);
+
+-- the answer key of every test being evaluated, the question ids of copied forms repeat across tests
+-- an existing database is moved to this table by migrations/0001_temp_correct_answers_per_test.sql
 CREATE TABLE IF NOT EXISTS temp_correct_answers (
-    question_id TEXT PRIMARY KEY,
+    test_id BIGINT NOT NULL,
+    question_id TEXT NOT NULL,
     correct_answer TEXT[],
-    points INT
+    points INT,
+    manual BOOLEAN NOT NULL DEFAULT false,
+    multiple BOOLEAN NOT NULL DEFAULT false,
+    section INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (test_id, question_id)
 );
 
 CREATE TABLE notifications (
@@ -217,3 +467,94 @@ CREATE TABLE discussions
         ON DELETE NO ACTION
         NOT VALID
 );
+
+CREATE TABLE testsections (
+    section_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    duration BIGINT,
+    min_score INT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT testsections_pkey PRIMARY KEY (section_id),
+    CONSTRAINT unique_test_id_section_name UNIQUE (test_id, name),
+    CONSTRAINT section_duration_check CHECK (duration IS NULL OR duration > 0),
+    CONSTRAINT tests_testsections_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE TABLE bankquestions (
+    question_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    company_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    correct_answer TEXT[] NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    difficulty TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT bankquestions_pkey PRIMARY KEY (question_id),
+    CONSTRAINT bank_question_type_check CHECK (type IN ('SingleChoice', 'MultipleChoice', 'ShortText', 'Paragraph')),
+    CONSTRAINT bank_question_difficulty_check CHECK (difficulty IN ('Easy', 'Medium', 'Hard')),
+    CONSTRAINT companies_bankquestions_fkey FOREIGN KEY (company_id)
+        REFERENCES companies(company_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE TABLE testdrawrules (
+    rule_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    difficulty TEXT,
+    count INT NOT NULL,
+    points INT NOT NULL DEFAULT 1,
+    position INT NOT NULL DEFAULT 0,
+    section_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT testdrawrules_pkey PRIMARY KEY (rule_id),
+    CONSTRAINT draw_rule_count_check CHECK (count > 0),
+    CONSTRAINT draw_rule_difficulty_check CHECK (difficulty IS NULL OR difficulty IN ('Easy', 'Medium', 'Hard')),
+    CONSTRAINT tests_testdrawrules_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT testsections_testdrawrules_fkey FOREIGN KEY (section_id)
+        REFERENCES testsections(section_id)
+        ON UPDATE CASCADE
+        ON DELETE SET NULL
+);
+
+CREATE TABLE testquestions (
+    question_id BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+    test_id BIGINT NOT NULL,
+    item_id TEXT NOT NULL DEFAULT md5(random()::text || clock_timestamp()::text),
+    position INT NOT NULL DEFAULT 0,
+    type TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    correct_answer TEXT[] NOT NULL,
+    points INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    section_id BIGINT,
+    rule_id BIGINT,
+    CONSTRAINT testquestions_pkey PRIMARY KEY (question_id),
+    CONSTRAINT unique_item_id UNIQUE (item_id),
+    CONSTRAINT question_type_check CHECK (type IN ('SingleChoice', 'MultipleChoice', 'ShortText', 'Paragraph')),
+    CONSTRAINT tests_testquestions_fkey FOREIGN KEY (test_id)
+        REFERENCES tests(test_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE,
+    CONSTRAINT testsections_testquestions_fkey FOREIGN KEY (section_id)
+        REFERENCES testsections(section_id)
+        ON UPDATE CASCADE
+        ON DELETE SET NULL,
+    CONSTRAINT testdrawrules_testquestions_fkey FOREIGN KEY (rule_id)
+        REFERENCES testdrawrules(rule_id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
@@ -0,0 +1,210 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils/ctxutils"
+	"go.mod/internal/utils/testforms"
+)
+
+// an attempt's responses by the question, with the time taken on each
+type attemptAnswers struct {
+	resultID int64
+	responses map[string]string
+	times map[string]int64
+}
+
+// SimilarityPoller compares the attempts of every ended test in pairs, once all of them are submitted.
+// A pair is flagged when it shares many identical wrong answers or spent nearly the same time on every question,
+// the flags are listed in the result draft, which waits for the comparison.
+// Has its own error quota, independent of the test results poller.
+func (a *AsyncService) SimilarityPoller(ctx context.Context) error {
+
+	timeout := config.SimilarityPollerTimeout * time.Second
+
+	fmt.Printf("Starting the answer similarity poller : Timeout: %d\n", timeout)
+
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	errored := 0
+	for range ticker.C {
+		testIDs, err := a.Queries.SimilarityPoller(ctx)
+		if err != nil {
+			fmt.Println(err)
+			errored += 1
+			if errored > errQuota {
+				// TODO: raise a critical error
+				return err
+			}
+			continue
+		}
+
+		for _, testID := range testIDs {
+			flagged, err := a.checkSimilarity(ctx, testID)
+			if err != nil {
+				// a test that cannot be compared is marked as checked, so its result draft is not held back
+				ctxutils.NewError(&dto.ErrorData{
+					Critical: fmt.Sprintf("failed to check answer similarity for test ID %d : %v", testID, err),
+				})
+				err = a.Queries.MarkSimilarityChecked(ctx, testID)
+				if err != nil {
+					fmt.Println(err)
+				}
+				continue
+			}
+			if flagged > 0 {
+				fmt.Printf("Flagged %d pairs of attempts with similar answers for test ID : %d\n", flagged, testID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSimilarity replaces the similarity flags of a test and marks it as checked, returns the number of flagged pairs
+func (a *AsyncService) checkSimilarity(ctx context.Context, testID int64) (int, error) {
+
+	testData, err := a.Queries.TestData(ctx, testID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get test data : %v", err)
+	}
+	// only the questions that are marked automatically have a known wrong answer
+	answers, err := testforms.AnswerKey(ctx, a.Queries, a.Tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get answer key : %v", err)
+	}
+	correct := make(map[string]string, len(answers))
+	for _, answer := range answers {
+		if !answer.Manual && len(answer.CorrectAnswer) > 0 {
+			correct[answer.QuestionID] = normalizeResponse(answer.CorrectAnswer)
+		}
+	}
+
+	responses, err := a.Queries.SimilarityResponses(ctx, testID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get test responses : %v", err)
+	}
+	// the responses are ordered by the attempt
+	attempts := []*attemptAnswers{}
+	for _, r := range responses {
+		if len(attempts) == 0 || attempts[len(attempts)-1].resultID != r.ResultID {
+			attempts = append(attempts, &attemptAnswers{
+				resultID: r.ResultID,
+				responses: map[string]string{},
+				times: map[string]int64{},
+			})
+		}
+		curr := attempts[len(attempts)-1]
+		if len(r.Response) > 0 {
+			curr.responses[r.QuestionID] = normalizeResponse(r.Response)
+		}
+		if r.TimeTaken.Valid && r.TimeTaken.Int64 > 0 {
+			curr.times[r.QuestionID] = r.TimeTaken.Int64
+		}
+	}
+
+	err = a.Queries.ClearSimilarityFlags(ctx, testID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear similarity flags : %v", err)
+	}
+
+	flagged := 0
+	for i := range attempts {
+		for j := i + 1; j < len(attempts); j++ {
+			params, flag := comparePair(attempts[i], attempts[j], correct)
+			if !flag {
+				continue
+			}
+			params.TestID = testID
+			err = a.Queries.InsertSimilarityFlag(ctx, params)
+			if err != nil {
+				return 0, fmt.Errorf("failed to insert similarity flag : %v", err)
+			}
+			flagged++
+		}
+	}
+
+	err = a.Queries.MarkSimilarityChecked(ctx, testID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark test as checked for similarity : %v", err)
+	}
+
+	return flagged, nil
+}
+
+// comparePair compares the answers of two attempts, the pair is flagged by either the shared wrong answers or the times.
+// The attempts are in the order of their result id.
+func comparePair(first *attemptAnswers, second *attemptAnswers, correct map[string]string) (sqlc.InsertSimilarityFlagParams, bool) {
+
+	common := 0
+	sharedWrong := 0
+	firstWrong := 0
+	secondWrong := 0
+	for questionID, response := range first.responses {
+		key, marked := correct[questionID]
+		if marked && response != key {
+			firstWrong++
+		}
+		other, ok := second.responses[questionID]
+		if !ok {
+			continue
+		}
+		common++
+		if marked && response != key && response == other {
+			sharedWrong++
+		}
+	}
+	for questionID, response := range second.responses {
+		if key, marked := correct[questionID]; marked && response != key {
+			secondWrong++
+		}
+	}
+
+	// the average difference of the time on the questions both spent time on, as a fraction of the longer time
+	timed := 0
+	difference := 0.0
+	for questionID, t := range first.times {
+		other, ok := second.times[questionID]
+		if !ok {
+			continue
+		}
+		timed++
+		difference += math.Abs(float64(t - other)) / float64(max(t, other))
+	}
+	timeDifference := pgtype.Float8{}
+	if timed >= config.SimilarityMinTimedQuestions {
+		timeDifference = pgtype.Float8{Float64: math.Round(difference / float64(timed) * 1000) / 1000, Valid: true}
+	}
+
+	sharesWrong := sharedWrong >= config.SimilarityMinSharedWrong &&
+		float64(sharedWrong) >= config.SimilaritySharedWrongRatio * float64(min(firstWrong, secondWrong))
+	sameTimes := timeDifference.Valid && timeDifference.Float64 <= config.SimilarityTimeTolerance
+
+	return sqlc.InsertSimilarityFlagParams{
+		ResultA: first.resultID,
+		ResultB: second.resultID,
+		CommonQuestions: int32(common),
+		SharedWrong: int32(sharedWrong),
+		TimeDifference: timeDifference,
+	}, sharesWrong || sameTimes
+}
+
+// normalizeResponse joins the selected options in a fixed order, so the same selection compares equal
+func normalizeResponse(response []string) string {
+	sorted := make([]string, 0, len(response))
+	for _, r := range response {
+		sorted = append(sorted, strings.TrimSpace(r))
+	}
+	slices.Sort(sorted)
+	return strings.Join(sorted, "\x00")
+}
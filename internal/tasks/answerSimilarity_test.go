@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mod/internal/config"
+)
+
+func TestComparePair(t *testing.T) {
+
+	// every question's correct answer is a, the questions q0..q9 are marked automatically
+	correct := map[string]string{}
+	for i := range 10 {
+		correct[fmt.Sprintf("q%d", i)] = "a"
+	}
+	answers := func(values ...string) map[string]string {
+		responses := map[string]string{}
+		for i, v := range values {
+			if v != "" {
+				responses[fmt.Sprintf("q%d", i)] = v
+			}
+		}
+		return responses
+	}
+	times := func(values ...int64) map[string]int64 {
+		taken := map[string]int64{}
+		for i, v := range values {
+			taken[fmt.Sprintf("q%d", i)] = v
+		}
+		return taken
+	}
+
+	tests := []struct {
+		name string
+		first *attemptAnswers
+		second *attemptAnswers
+		flagged bool
+		common int32
+		sharedWrong int32
+		timed bool
+	}{
+		{
+			name: "identical wrong answers",
+			first: &attemptAnswers{responses: answers("b", "c", "d", "a")},
+			second: &attemptAnswers{responses: answers("b", "c", "d", "a")},
+			flagged: true, common: 4, sharedWrong: 3,
+		},
+		{
+			name: "too few shared wrong answers",
+			first: &attemptAnswers{responses: answers("b", "c", "a", "a")},
+			second: &attemptAnswers{responses: answers("b", "c", "a", "a")},
+			flagged: false, common: 4, sharedWrong: 2,
+		},
+		{
+			name: "different wrong answers",
+			first: &attemptAnswers{responses: answers("b", "c", "d", "b")},
+			second: &attemptAnswers{responses: answers("c", "d", "b", "c")},
+			flagged: false, common: 4, sharedWrong: 0,
+		},
+		{
+			name: "shared wrong answers are few among the wrong answers",
+			first: &attemptAnswers{responses: answers("b", "b", "b", "b", "b", "b", "b", "b", "b", "b")},
+			second: &attemptAnswers{responses: answers("b", "b", "b", "c", "c", "c", "c", "c", "c", "c")},
+			flagged: false, common: 10, sharedWrong: 3,
+		},
+		{
+			name: "unmarked questions are not wrong",
+			first: &attemptAnswers{responses: map[string]string{"x1": "b", "x2": "b", "x3": "b"}},
+			second: &attemptAnswers{responses: map[string]string{"x1": "b", "x2": "b", "x3": "b"}},
+			flagged: false, common: 3, sharedWrong: 0,
+		},
+		{
+			name: "nearly the same times",
+			first: &attemptAnswers{responses: answers("a"), times: times(100, 200, 300, 400, 500)},
+			second: &attemptAnswers{responses: answers("a"), times: times(95, 190, 285, 380, 475)},
+			flagged: true, common: 1, timed: true,
+		},
+		{
+			name: "different times",
+			first: &attemptAnswers{times: times(100, 200, 300, 400, 500)},
+			second: &attemptAnswers{times: times(50, 100, 150, 200, 250)},
+			flagged: false, timed: true,
+		},
+		{
+			name: "too few timed questions",
+			first: &attemptAnswers{times: times(100, 200, 300, 400)},
+			second: &attemptAnswers{times: times(100, 200, 300, 400)},
+			flagged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, flagged := comparePair(tt.first, tt.second, correct)
+			if flagged != tt.flagged {
+				t.Errorf("got flagged %v, want %v", flagged, tt.flagged)
+			}
+			if params.CommonQuestions != tt.common || params.SharedWrong != tt.sharedWrong {
+				t.Errorf("got %d common, %d shared wrong, want %d, %d", params.CommonQuestions, params.SharedWrong, tt.common, tt.sharedWrong)
+			}
+			if params.TimeDifference.Valid != tt.timed {
+				t.Errorf("got time difference %v, want it set %v", params.TimeDifference, tt.timed)
+			}
+		})
+	}
+}
+
+func TestComparePairTimeDifference(t *testing.T) {
+
+	first := &attemptAnswers{resultID: 1, times: map[string]int64{}}
+	second := &attemptAnswers{resultID: 2, times: map[string]int64{}}
+	for i := range config.SimilarityMinTimedQuestions {
+		first.times[fmt.Sprintf("q%d", i)] = 100
+		second.times[fmt.Sprintf("q%d", i)] = 80
+	}
+
+	params, _ := comparePair(first, second, map[string]string{})
+	if params.ResultA != 1 || params.ResultB != 2 {
+		t.Errorf("got results %d and %d, want 1 and 2", params.ResultA, params.ResultB)
+	}
+	if !params.TimeDifference.Valid || params.TimeDifference.Float64 != 0.2 {
+		t.Errorf("got time difference %v, want 0.2", params.TimeDifference)
+	}
+}
+
+func TestNormalizeResponse(t *testing.T) {
+
+	tests := []struct {
+		name string
+		a, b []string
+		same bool
+	}{
+		{"same order", []string{"a", "b"}, []string{"a", "b"}, true},
+		{"any order", []string{"b", "a"}, []string{"a", "b"}, true},
+		{"spaces are trimmed", []string{" a", "b "}, []string{"a", "b"}, true},
+		{"different selection", []string{"a"}, []string{"a", "b"}, false},
+		{"values are not joined", []string{"ab"}, []string{"a", "b"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (normalizeResponse(tt.a) == normalizeResponse(tt.b)) != tt.same {
+				t.Errorf("got %q and %q, want same = %v", normalizeResponse(tt.a), normalizeResponse(tt.b), tt.same)
+			}
+		})
+	}
+}
@@ -9,13 +9,13 @@ import (
 
 type AsyncService struct {
 	Queries *sqlc.Queries
-	GAPIService *apicalls.Caller
+	Tests apicalls.TestProvider
 }
 
-func NewAsyncService(queries *sqlc.Queries, gapiService *apicalls.Caller) *AsyncService {
+func NewAsyncService(queries *sqlc.Queries, testProvider apicalls.TestProvider) *AsyncService {
 	return &AsyncService{
 		Queries: queries,
-		GAPIService: gapiService,
+		Tests: testProvider,
 	}
 }
 
@@ -32,6 +32,40 @@ func (a *AsyncService) StartAsyncs() error {
 		}
 	} ()
 
+	// finalizes the test attempts that were never submitted
+	go func() {
+		err := a.ExpiredAttemptsPoller(ctx)
+		if err != nil {
+			return
+		}
+	} ()
+
+	// compares the answers of the attempts of the ended tests, before their result is generated
+	go func() {
+		err := a.SimilarityPoller(ctx)
+		if err != nil {
+			return
+		}
+	} ()
+
+	// closes the jobs past their application deadline
+	go func() {
+		err := a.JobDeadlinePoller(ctx)
+		if err != nil {
+			return
+		}
+	} ()
+
+	// finds the forms shared with the provider, only the providers that sync them in the background
+	if syncer, ok := a.Tests.(apicalls.FormSyncer); ok {
+		go func() {
+			err := a.DriveChangesPoller(ctx, syncer)
+			if err != nil {
+				return
+			}
+		} ()
+	}
+
 
 
 	return nil
@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
+)
+
+// DriveChangesPoller syncs the forms shared with the test provider on a schedule,
+// so that the responder link of a new test resolves without reading the Drive changes in the request.
+// The first sync runs at the start, the page token is stored by the provider and survives restarts.
+// Has its own error quota, independent of the other pollers.
+func (a *AsyncService) DriveChangesPoller(ctx context.Context, syncer apicalls.FormSyncer) error {
+
+	timeout := config.DriveChangesPollerTimeout * time.Second
+
+	fmt.Printf("Starting the drive changes poller : Timeout: %d\n", timeout)
+
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	errored := 0
+	for ; true; <-ticker.C {
+		synced, err := syncer.SyncForms(ctx)
+		if err != nil {
+			fmt.Println(err)
+			errored += 1
+			if errored > errQuota {
+				// TODO: raise a critical error
+				return err
+			}
+			continue
+		}
+		if synced > 0 {
+			fmt.Printf("Synced %d shared forms from the drive changes\n", synced)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mod/internal/config"
+)
+
+// ExpiredAttemptsPoller finalizes the test attempts that were started but never submitted,
+// eg. the student closed the page or lost the connection.
+// An attempt expires once its duration or the test's end_time has passed, whichever is earlier,
+// its end_time is then set to that deadline, the same as if the student had submitted it.
+// Has its own error quota, independent of the test results poller.
+func (a *AsyncService) ExpiredAttemptsPoller(ctx context.Context) error {
+
+	timeout := config.ExpiredAttemptsPollerTimeout * time.Second
+
+	fmt.Printf("Starting the expired attempts poller : Timeout: %d\n", timeout)
+
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	errored := 0
+	for range ticker.C {
+		finalized, err := a.Queries.FinalizeExpiredAttempts(ctx)
+		if err != nil {
+			fmt.Println(err)
+			errored += 1
+			if errored > errQuota {
+				// TODO: raise a critical error
+				return err
+			}
+			continue
+		}
+		if finalized > 0 {
+			fmt.Printf("Finalized %d expired test attempts\n", finalized)
+		}
+	}
+
+	return nil
+}
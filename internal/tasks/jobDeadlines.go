@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils"
+	"go.mod/internal/utils/ctxutils"
+)
+
+// JobDeadlinePoller closes the active jobs whose application deadline has passed,
+// and lets the company know how many applications the job got.
+// A job is closed by a single update, so the company is notified once even if the poller restarts.
+// Has its own error quota, independent of the other pollers.
+func (a *AsyncService) JobDeadlinePoller(ctx context.Context) error {
+
+	timeout := config.JobDeadlinePollerTimeout * time.Second
+
+	fmt.Printf("Starting the job deadline poller : Timeout: %d\n", timeout)
+
+	ticker := time.NewTicker(timeout)
+	defer ticker.Stop()
+
+	errored := 0
+	for range ticker.C {
+		closed, err := a.Queries.CloseExpiredJobs(ctx)
+		if err != nil {
+			fmt.Println(err)
+			errored += 1
+			if errored > errQuota {
+				// TODO: raise a critical error
+				return err
+			}
+			continue
+		}
+
+		for _, job := range closed {
+			err = a.notifyJobClosed(ctx, job)
+			if err != nil {
+				// the job is already closed, a failed notification does not stop the others
+				ctxutils.NewError(&dto.ErrorData{
+					Critical: err.Error(),
+				})
+			}
+		}
+		if len(closed) > 0 {
+			fmt.Printf("Closed %d jobs past their application deadline\n", len(closed))
+		}
+	}
+
+	return nil
+}
+
+// notifyJobClosed notifies the company of a job closed by its deadline, and sends it an email
+func (a *AsyncService) notifyJobClosed(ctx context.Context, job sqlc.CloseExpiredJobsRow) error {
+
+	err := a.Queries.InsertNotifications(ctx, sqlc.InsertNotificationsParams{
+		UserID: job.UserID,
+		Title: pgtype.Text{String: "Job Closed", Valid: true},
+		Description: pgtype.Text{String: fmt.Sprintf("The application deadline for %s (ID: %d) has passed, the job is closed with %d applications.", job.Title, job.JobID, job.Applicants), Valid: true},
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to notify the company of closed job ID : %d : %v", job.JobID, err)
+	}
+
+	emailData := struct {
+		CompanyName string
+		JobTitle string
+		JobID int64
+		Deadline string
+		Applicants int64
+	} {
+		job.CompanyName,
+		job.Title,
+		job.JobID,
+		job.ApplicationDeadline.Time.Format("02 Jan 2006 15:04"),
+		job.Applicants,
+	}
+	template, err := utils.DynamicHTML("./template/emails/jobClosed.html", emailData)
+	if err != nil {
+		return fmt.Errorf("failed to generate job closed email for job ID : %d : %v", job.JobID, err)
+	}
+	go utils.SendEmailHTML(template, []string{job.RepresentativeEmail})
+
+	return nil
+}
@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -39,7 +40,11 @@ func (a *AsyncService) TestResultsPoller(ctx context.Context) error {
 			}
 		} else {
 			// calls the generate test result draft util
-			_, err := testresgen.GenerateCumulativeTestResult(a.Queries, a.GAPIService, testID)
+			_, err := testresgen.GenerateCumulativeTestResult(a.Queries, a.Tests, testID, config.ResultReasonTestEnded)
+			if errors.Is(err, testresgen.ErrGradingPending) {
+				// skipped by the poller until the company grades the responses
+				continue
+			}
 			if err != nil {
 				return err
 			}
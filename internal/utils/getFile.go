@@ -1,21 +1,120 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.mod/internal/config"
 )
 
 // not much as of now
 // can be updated later on
 
+var mediaClient = &http.Client{Timeout: config.MediaFetchTimeout * time.Second}
 
+// a media hash is the hex encoded sha256 of its content
+var mediaHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
 
 // can be used to get files from offshore storage and return it as []byte
+// the file is written next to pathToSave and then moved in place, so concurrent downloads to the same path
+// never read each other's partial writes, the returned bytes are the downloaded ones and not read back
 func GetFileFromPath(url string, pathToSave string) ([]byte, error) {
+
+	fileByte, err := download(url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeAtomic(pathToSave, fileByte)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileByte, nil
+}
+
+// StoreMedia downloads the media at the url into the store and returns its content hash.
+// The media is stored by its hash, so the same image used by many tests is stored once
+// and concurrent downloads of the same media write the same file.
+func StoreMedia(url string, storeDir string) (string, []byte, error) {
+
+	fileByte, err := download(url)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sum := sha256.Sum256(fileByte)
+	hash := hex.EncodeToString(sum[:])
+
+	path := filepath.Join(storeDir, hash)
+	_, err = os.Stat(path)
+	if err == nil {
+		return hash, fileByte, nil
+	}
+
+	err = os.MkdirAll(storeDir, 0755)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create media store: %v", err)
+	}
+	err = writeAtomic(path, fileByte)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hash, fileByte, nil
+}
+
+// ReadMedia returns the content of the stored media by its hash
+func ReadMedia(hash string, storeDir string) ([]byte, error) {
+
+	if !mediaHash.MatchString(hash) {
+		return nil, fmt.Errorf("invalid media hash: %s", hash)
+	}
+
+	return os.ReadFile(filepath.Join(storeDir, hash))
+}
+
+// StoreMediaAll downloads the media at the urls into the store with at most 'parallel' downloads at once.
+// The hashes and contents are returned in the order of the urls, if any download fails the first error is returned.
+func StoreMediaAll(urls []string, storeDir string, parallel int) ([]string, [][]byte, error) {
+
+	hashes := make([]string, len(urls))
+	contents := make([][]byte, len(urls))
+	errs := make([]error, len(urls))
+
+	sem := make(chan struct{}, max(parallel, 1))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			hashes[i], contents[i], errs[i] = StoreMedia(url, storeDir)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return hashes, contents, nil
+}
+
+func download(url string) ([]byte, error) {
 	// Send GET request to fetch the file
-	response, err := http.Get(url)
+	response, err := mediaClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch the file: %v", err)
 	}
@@ -26,25 +125,40 @@ func GetFileFromPath(url string, pathToSave string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to download file, status code: %d", response.StatusCode)
 	}
 
-	// Create a new file on the local system
-	outFile, err := os.Create(pathToSave)
+	fileByte, err := io.ReadAll(io.LimitReader(response.Body, config.MediaMaxSize + 1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %v", err)
+		return nil, fmt.Errorf("failed to read the file: %v", err)
+	}
+	if len(fileByte) > config.MediaMaxSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", config.MediaMaxSize)
 	}
-	defer outFile.Close()
-	
-	// Copy the content of the response body into the local file
-	_, err = io.Copy(outFile, response.Body)
+
+	return fileByte, nil
+}
+
+// writeAtomic writes to a temporary file in the same directory and renames it to the path
+func writeAtomic(path string, data []byte) error {
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path) + ".*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to write to file: %v", err)
+		return fmt.Errorf("failed to create file: %v", err)
 	}
+	defer os.Remove(tmp.Name())
 
-	fmt.Println("File downloaded successfully!")
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write to file: %v", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %v", err)
+	}
 
-	fileByte, err := os.ReadFile("./temp")
+	err = os.Rename(tmp.Name(), path)
 	if err != nil {
-		fmt.Println(err)
+		return fmt.Errorf("failed to move file in place: %v", err)
 	}
 
-	return fileByte, nil
-}
\ No newline at end of file
+	return nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+	// the time zone database is embedded, the server may not have one
+	_ "time/tzdata"
+
+	"go.mod/internal/config"
+)
+
+// ParseLocalTime parses a datetime-local value of a form, 2006-01-02T15:04, in the platform's time zone.
+// The value carries no offset, it is the time the user sees on their clock.
+func ParseLocalTime(value string) (time.Time, error) {
+
+	location, err := time.LoadLocation(config.PlatformTimeZone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load time zone %s : %v", config.PlatformTimeZone, err)
+	}
+
+	return time.ParseInLocation("2006-01-02T15:04", value, location)
+}
@@ -0,0 +1,316 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// a minimal pdf writer for the static reports that are sent as attachments
+// it only has what the reports need, text in the standard Helvetica fonts (no fonts are embedded),
+// filled rectangles and lines, and a flow layout of headings, paragraphs, tables and bar charts
+// the pages are A4, the coordinates are in points from the top left corner of the page
+
+const (
+	PageWidth = 595.28
+	PageHeight = 841.89
+	Margin = 50.0
+
+	fontRegular = "F1"
+	fontBold = "F2"
+)
+
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	Black = Color{0, 0, 0}
+	Grey = Color{0.6, 0.6, 0.6}
+	LightGrey = Color{0.92, 0.92, 0.92}
+	Green = Color{0.2, 0.6, 0.3}
+	Orange = Color{0.95, 0.6, 0.1}
+	Red = Color{0.85, 0.2, 0.2}
+	Blue = Color{0.2, 0.4, 0.8}
+)
+
+type Document struct {
+	pages []*bytes.Buffer
+	// the top of the free space on the current page, for the flow layout
+	y float64
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page, the flow layout continues at its top
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+	d.y = Margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// Text writes the text with its baseline at y
+func (d *Document) Text(x, y, size float64, bold bool, color Color, s string) {
+	font := fontRegular
+	if bold {
+		font = fontBold
+	}
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", color.R, color.G, color.B, font, size, x, PageHeight - y, encode(s))
+}
+
+// Rect fills a rectangle with its top left corner at x, y
+func (d *Document) Rect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", color.R, color.G, color.B, x, PageHeight - y - h, w, h)
+}
+
+func (d *Document) Line(x1, y1, x2, y2 float64, color Color) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n", color.R, color.G, color.B, x1, PageHeight - y1, x2, PageHeight - y2)
+}
+
+// ensure moves to a new page if the next h points do not fit on the current one
+func (d *Document) ensure(h float64) {
+	if d.y + h > PageHeight - Margin {
+		d.AddPage()
+	}
+}
+
+func (d *Document) Heading(s string) {
+	d.ensure(30)
+	d.y += 20
+	d.Text(Margin, d.y, 16, true, Black, s)
+	d.y += 10
+}
+
+func (d *Document) Subheading(s string) {
+	d.ensure(26)
+	d.y += 18
+	d.Text(Margin, d.y, 12, true, Black, s)
+	d.y += 8
+}
+
+// Paragraph writes the text wrapped to the width of the page
+func (d *Document) Paragraph(s string) {
+	const size = 10
+	width := PageWidth - 2 * Margin
+	for _, line := range wrap(s, size, width) {
+		d.ensure(14)
+		d.y += 14
+		d.Text(Margin, d.y, size, false, Black, line)
+	}
+	d.y += 4
+}
+
+// Table writes the rows in columns of equal width under a bold header, the cells that do not fit are cut short
+func (d *Document) Table(headers []string, rows [][]string) {
+	if len(headers) == 0 {
+		return
+	}
+	const size = 9
+	const rowHeight = 18.0
+	colWidth := (PageWidth - 2 * Margin) / float64(len(headers))
+
+	header := func() {
+		d.Rect(Margin, d.y, PageWidth - 2 * Margin, rowHeight, LightGrey)
+		for i, h := range headers {
+			d.Text(Margin + float64(i) * colWidth + 4, d.y + 12, size, true, Black, fit(h, size, colWidth - 8))
+		}
+		d.y += rowHeight
+	}
+
+	d.ensure(2 * rowHeight)
+	header()
+	for _, row := range rows {
+		// the header is repeated at the top of every page the table runs into
+		if d.y + rowHeight > PageHeight - Margin {
+			d.AddPage()
+			header()
+		}
+		for i, cell := range row {
+			if i >= len(headers) {
+				break
+			}
+			d.Text(Margin + float64(i) * colWidth + 4, d.y + 12, size, false, Black, fit(cell, size, colWidth - 8))
+		}
+		d.y += rowHeight
+		d.Line(Margin, d.y, PageWidth - Margin, d.y, LightGrey)
+	}
+	d.y += 8
+}
+
+// BarChart draws a bar for every value with its label under it and the value over it.
+// The negative values are drawn below the zero line, colors are used in turn.
+func (d *Document) BarChart(title string, labels []string, values []float64, colors []Color) {
+	const chartHeight = 150.0
+	const labelSize = 8
+	if len(values) == 0 {
+		return
+	}
+	if len(colors) == 0 {
+		colors = []Color{Blue}
+	}
+
+	d.ensure(chartHeight + 60)
+	d.Subheading(title)
+	d.y += 12
+
+	top, bottom := 0.0, 0.0
+	for _, v := range values {
+		top = math.Max(top, v)
+		bottom = math.Min(bottom, v)
+	}
+	span := top - bottom
+	if span == 0 {
+		span = 1
+	}
+	scale := chartHeight / span
+	zero := d.y + top * scale
+
+	width := PageWidth - 2 * Margin
+	slot := width / float64(len(values))
+	barWidth := math.Min(slot * 0.6, 60)
+	for i, v := range values {
+		x := Margin + float64(i) * slot + (slot - barWidth) / 2
+		h := math.Abs(v) * scale
+		y := zero - h
+		valueY := y - 3
+		if v < 0 {
+			y = zero
+			valueY = zero + h + 10
+		}
+		d.Rect(x, y, barWidth, h, colors[i % len(colors)])
+		value := formatValue(v)
+		d.Text(x + (barWidth - textWidth(value, labelSize)) / 2, valueY, labelSize, false, Black, value)
+	}
+	d.Line(Margin, zero, PageWidth - Margin, zero, Grey)
+
+	d.y += chartHeight + 14
+	for i := range values {
+		if i >= len(labels) {
+			break
+		}
+		label := fit(labels[i], labelSize, slot - 4)
+		d.Text(Margin + float64(i) * slot + (slot - textWidth(label, labelSize)) / 2, d.y, labelSize, false, Black, label)
+	}
+	d.y += 12
+}
+
+// Bytes writes out the whole document
+func (d *Document) Bytes() []byte {
+
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// 1 catalog, 2 pages, 3 and 4 fonts, then a page and its content for every page
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5 + 2 * i)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>", PageWidth, PageHeight, fontRegular, fontBold, 6 + 2 * i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.Len(), p.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets) + 1)
+	for _, o := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets) + 1, xref)
+
+	return out.Bytes()
+}
+
+// encode escapes the text for a pdf string, the characters outside of latin-1 are replaced as the fonts are not embedded
+func encode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\t':
+			b.WriteByte(' ')
+		case r < 32 || r > 255:
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
+
+// textWidth is an estimate of the width of the text in Helvetica, close enough to lay out and cut the text
+func textWidth(s string, size float64) float64 {
+	w := 0.0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("il.,:;|!'I ()[]", r):
+			w += 0.28
+		case strings.ContainsRune("mwMW", r):
+			w += 0.83
+		case r >= 'A' && r <= 'Z':
+			w += 0.67
+		default:
+			w += 0.556
+		}
+	}
+	return w * size
+}
+
+// fit cuts the text short with dots if it is wider than width
+func fit(s string, size float64, width float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes) + "...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// wrap splits the text into lines that fit the width, at the spaces
+func wrap(s string, size float64, width float64) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(s) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && textWidth(next, size) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
@@ -0,0 +1,181 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+
+	tests := []struct {
+		in string
+		want string
+	}{
+		{"Result", "Result"},
+		{"f(x) = 1", "f\\(x\\) = 1"},
+		{`C:\tests`, `C:\\tests`},
+		{"two\nlines\tand tab", "two lines and tab"},
+		{"café", "caf\xe9"},
+		{"₹ 12 LPA", "? 12 LPA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := encode(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+
+	tests := []struct {
+		name string
+		s string
+		width float64
+		want []string
+	}{
+		{"fits on a line", "a short line", 1000, []string{"a short line"}},
+		{"split at the spaces", "aaaa bbbb cccc", textWidth("aaaa bbbb", 10), []string{"aaaa bbbb", "cccc"}},
+		{"a long word is not split", "aaaaaaaaaa b", textWidth("aaa", 10), []string{"aaaaaaaaaa", "b"}},
+		{"spaces are collapsed", "  a   b  ", 1000, []string{"a b"}},
+		{"empty", "", 1000, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrap(tt.s, 10, tt.width)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			for _, line := range got {
+				if strings.Contains(line, " ") && textWidth(line, 10) > tt.width {
+					t.Errorf("line %q is wider than %g", line, tt.width)
+				}
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+
+	tests := []struct {
+		name string
+		s string
+		width float64
+		want string
+	}{
+		{"fits", "Candidate", 1000, "Candidate"},
+		{"cut short", "Candidate name", textWidth("Cand...", 9), "Cand..."},
+		{"nothing fits", "Candidate", 0, "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fit(tt.s, 9, tt.width); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+
+	tests := []struct {
+		v float64
+		want string
+	}{
+		{12, "12"},
+		{-3, "-3"},
+		{12.25, "12.2"},
+		{0.5, "0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatValue(tt.v); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBytes(t *testing.T) {
+
+	rows := [][]string{}
+	for i := range 100 {
+		rows = append(rows, []string{strconv.Itoa(i), "Candidate", "42"})
+	}
+
+	tests := []struct {
+		name string
+		build func(d *Document)
+		pages int
+	}{
+		{"empty", func(d *Document) {}, 1},
+		{"one page", func(d *Document) {
+			d.Heading("Test Result")
+			d.Paragraph("The result of the test (draft).")
+			d.BarChart("Scores", []string{"A", "B"}, []float64{10, -2}, []Color{Green, Red})
+		}, 1},
+		{"a table runs into the next pages", func(d *Document) {
+			d.Heading("Candidates")
+			d.Table([]string{"#", "Name", "Score"}, rows)
+		}, 3},
+		{"a new page", func(d *Document) {
+			d.Heading("First")
+			d.AddPage()
+			d.Heading("Second")
+		}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New()
+			tt.build(d)
+			out := d.Bytes()
+
+			if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+				t.Fatalf("not a pdf : %q ... %q", out[:min(len(out), 16)], out[max(0, len(out)-16):])
+			}
+			if got := bytes.Count(out, []byte("/Type /Page ")); got != tt.pages {
+				t.Errorf("got %d pages, want %d", got, tt.pages)
+			}
+			if !bytes.Contains(out, []byte(fmt.Sprintf("/Count %d", tt.pages))) {
+				t.Errorf("the page tree does not count %d pages", tt.pages)
+			}
+			checkXref(t, out)
+		})
+	}
+}
+
+// checkXref checks that every object in the cross reference table starts at its offset, and startxref points to the table
+func checkXref(t *testing.T, out []byte) {
+	t.Helper()
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(out[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point to the xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	if len(entries) == 0 {
+		t.Fatal("no objects in the xref table")
+	}
+	for i, e := range entries {
+		offset, _ := strconv.Atoi(string(e[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i + 1); !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("object %d is not at offset %d", i + 1, offset)
+		}
+	}
+}
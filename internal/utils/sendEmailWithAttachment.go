@@ -4,12 +4,19 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
+	"mime"
 	"mime/multipart"
 	"net/smtp"
 	"os"
 	"path/filepath"
 )
 
+// EmailAttachment is a file attached to an email, the content type is found from the extension of its name
+type EmailAttachment struct {
+	Name string
+	Content []byte
+}
+
 
 func SendEmailHTMLWithAttachment(body bytes.Buffer, to_Email []string, attachment *[]byte, name string) (error)  {
 	// Email headers
@@ -118,4 +125,71 @@ func SendEmailHTMLWithAttachmentFilePath(body bytes.Buffer, to_Email []string, f
 	}
 
 	return SendEmailHTMLWithAttachment(body, to_Email, &fileBytes, fileName)
-}
\ No newline at end of file
+}
+
+// SendEmailHTMLWithAttachments sends the email with every attachment, unlike the single attachment versions
+// it returns the errors so that the caller can retry the email
+func SendEmailHTMLWithAttachments(body bytes.Buffer, to_Email []string, attachments []EmailAttachment) (error) {
+	// Email headers
+	subject := "Subject: PMS\n"
+	fromEmail := os.Getenv("SMTP_GO_From")
+
+	// Load environment variables
+	smtpHost := os.Getenv("SMTP_GO_Host")
+	smtpPort := os.Getenv("SMTP_GO_HostAddress")
+	username := os.Getenv("SMTP_GO_Username")
+	password := os.Getenv("SMTP_GO_Pass")
+
+	if smtpHost == "" || smtpPort == "" || fromEmail == "" || username == "" || password == "" {
+		return fmt.Errorf("missing required SMTP configuration in environment variables")
+	}
+
+	var emailContent bytes.Buffer
+	writer := multipart.NewWriter(&emailContent)
+
+	emailContent.WriteString(fmt.Sprintf("From: %s\n", fromEmail))
+	emailContent.WriteString(fmt.Sprintf("To: %s\n", to_Email[0]))
+	emailContent.WriteString(subject)
+	emailContent.WriteString("MIME-Version: 1.0\n")
+	emailContent.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\n\n", writer.Boundary()))
+
+	htmlPart, err := writer.CreatePart(map[string][]string{
+		"Content-Type": {"text/html; charset=UTF-8"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email body part: %w", err)
+	}
+	_, err = htmlPart.Write(body.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+
+	for _, a := range attachments {
+		contentType := mime.TypeByExtension(filepath.Ext(a.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		attachmentPart, err := writer.CreatePart(map[string][]string{
+			"Content-Type":              {fmt.Sprintf("%s; name=%s", contentType, a.Name)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%s", a.Name)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create attachment part: %w", err)
+		}
+		_, err = attachmentPart.Write([]byte(base64.StdEncoding.EncodeToString(a.Content)))
+		if err != nil {
+			return fmt.Errorf("failed to write attachment: %w", err)
+		}
+	}
+
+	writer.Close()
+
+	auth := smtp.PlainAuth("", username, password, smtpHost)
+
+	err = smtp.SendMail(smtpPort, auth, fromEmail, to_Email, emailContent.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package testforms
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+)
+
+// the company's question bank is drawn from by the draw rules of a test
+// the questions matching a rule (its pool) are copied into the test's questions, tagged with the rule,
+// so the test does not change when the bank is edited later and is evaluated like any other test built on the platform
+// a bank question matching more than one rule is in the pool of the first one (by position)
+// every candidate is served Count questions from each pool, drawn with a seed from the test and the user id
+
+// ValidateBank checks a bank question before it is stored, the returned error is meant for the user.
+// The tags are trimmed and lower cased, empty and repeated tags are dropped.
+func ValidateBank(q *dto.BankQuestionData) error {
+
+	question := dto.TestQuestionData{
+		Type: q.Type,
+		Title: q.Title,
+		Options: q.Options,
+		CorrectAnswer: q.CorrectAnswer,
+	}
+	err := Validate(&question)
+	if err != nil {
+		return err
+	}
+	q.Title = question.Title
+	q.Options = question.Options
+	q.CorrectAnswer = question.CorrectAnswer
+
+	err = ValidateDifficulty(q.Difficulty)
+	if err != nil {
+		return err
+	}
+	if q.Difficulty == "" {
+		return fmt.Errorf("question difficulty is required")
+	}
+
+	q.Tags = Tags(q.Tags)
+	return nil
+}
+
+// ValidateDifficulty checks the difficulty of a bank question or a draw rule, empty is allowed
+func ValidateDifficulty(difficulty string) error {
+	switch difficulty {
+	case "", config.DifficultyEasy, config.DifficultyMedium, config.DifficultyHard:
+		return nil
+	default:
+		return fmt.Errorf("invalid difficulty '%s', must be one of %s, %s, %s", difficulty, config.DifficultyEasy, config.DifficultyMedium, config.DifficultyHard)
+	}
+}
+
+// Tags trims and lower cases the tags, empty and repeated tags are dropped
+func Tags(tags []string) []string {
+	cleaned := []string{}
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t != "" && !slices.Contains(cleaned, t) {
+			cleaned = append(cleaned, t)
+		}
+	}
+	return cleaned
+}
+
+// DrawOrder returns the items served to a user, the input is not modified.
+// Count items are drawn from the pool of every rule, the drawn items keep their place in the order,
+// the items that are not drawn by any rule (questions added directly, page breaks) are always served.
+func DrawOrder(order []string, itemRules map[string]int64, counts map[int64]int32, seed uint64) []string {
+
+	r := rand.New(rand.NewPCG(seed, seed>>1))
+
+	// collect the pool of every rule, in order
+	pools := map[int64][]string{}
+	ruleOrder := []int64{}
+	for _, itemID := range order {
+		rule, ok := itemRules[itemID]
+		if !ok {
+			continue
+		}
+		if _, ok := pools[rule]; !ok {
+			ruleOrder = append(ruleOrder, rule)
+		}
+		pools[rule] = append(pools[rule], itemID)
+	}
+
+	// the rules are drawn one after the other so the result does not depend on map iteration
+	drawn := map[string]bool{}
+	for _, rule := range ruleOrder {
+		pool := pools[rule]
+		r.Shuffle(len(pool), func(i, j int) {
+			pool[i], pool[j] = pool[j], pool[i]
+		})
+		for _, itemID := range pool[:min(int(counts[rule]), len(pool))] {
+			drawn[itemID] = true
+		}
+	}
+
+	served := make([]string, 0, len(order))
+	for _, itemID := range order {
+		if _, ok := itemRules[itemID]; !ok || drawn[itemID] {
+			served = append(served, itemID)
+		}
+	}
+
+	return served
+}
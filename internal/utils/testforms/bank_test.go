@@ -0,0 +1,50 @@
+package testforms
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDrawOrder(t *testing.T) {
+
+	order := []string{"intro", "q1", "q2", "q3", "q4", "q5"}
+	itemRules := map[string]int64{"q1": 1, "q2": 1, "q3": 1, "q4": 2, "q5": 2}
+
+	tests := []struct {
+		name string
+		counts map[int64]int32
+		// the number of questions drawn for every rule
+		want map[int64]int
+	}{
+		{"draw from both rules", map[int64]int32{1: 2, 2: 1}, map[int64]int{1: 2, 2: 1}},
+		{"count above the pool", map[int64]int32{1: 5, 2: 2}, map[int64]int{1: 3, 2: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drawn := DrawOrder(order, itemRules, tt.counts, Seed(1, "10", "draw"))
+
+			got := map[int64]int{}
+			last := -1
+			for _, itemID := range drawn {
+				// the drawn items keep their order
+				i := slices.Index(order, itemID)
+				if i <= last {
+					t.Errorf("got %v, not in the order of %v", drawn, order)
+				}
+				last = i
+				if rule, ok := itemRules[itemID]; ok {
+					got[rule]++
+				}
+			}
+			if !slices.Contains(drawn, "intro") {
+				t.Errorf("the item without a rule was left out : %v", drawn)
+			}
+			for rule, count := range tt.want {
+				if got[rule] != count {
+					t.Errorf("rule %d : got %d questions, want %d", rule, got[rule], count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package testforms
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+)
+
+// the CSV file needs a header row, the columns are matched by name (case insensitive) and can be in any order
+// Options and CorrectAnswer hold multiple values separated by config.TestImportValueSeparator
+// Description and Points are optional, Points defaults to 1
+//
+//	Type,Title,Description,Options,CorrectAnswer,Points
+//	SingleChoice,2 + 2 = ?,,3|4|5,4,2
+//
+// the JSON file is an array of dto.TestQuestionData objects, QuestionID, TestID, Position and SectionID are ignored
+//
+//	[{"Type": "ShortText", "Title": "Capital of France", "CorrectAnswer": ["Paris"], "Points": 1}]
+//
+// the questions are positioned in the order they appear in the file
+
+var csvColumns = []string{"type", "title", "description", "options", "correctanswer", "points"}
+var csvRequiredColumns = []string{"type", "title", "correctanswer"}
+
+// ParseFile reads the uploaded CSV or JSON file and validates every question in it.
+// If any question is invalid, all the errors are returned with their line numbers and no questions are returned.
+func ParseFile(fileHeader *multipart.FileHeader) ([]dto.TestQuestionData, []string) {
+
+	if fileHeader.Size > config.TestImportMaxFileSize {
+		return nil, []string{fmt.Sprintf("file is too large, the limit is %d bytes", config.TestImportMaxFileSize)}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, []string{fmt.Sprintf("unable to open file : %v", err)}
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("unable to read file : %v", err)}
+	}
+
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		return ParseCSV(data)
+	case ".json":
+		return ParseJSON(data)
+	default:
+		return nil, []string{"only .csv and .json files can be imported"}
+	}
+}
+
+// ParseCSV parses and validates the questions in a CSV file, see the format above.
+func ParseCSV(data []byte) ([]dto.TestQuestionData, []string) {
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, []string{fmt.Sprintf("line 1 : unable to read header : %v", err)}
+	}
+
+	// map the column names to their index
+	columns := map[string]int{}
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(h))
+		known := false
+		for _, c := range csvColumns {
+			if name == c {
+				known = true
+			}
+		}
+		if !known {
+			return nil, []string{fmt.Sprintf("line 1 : unknown column '%s', the columns are Type, Title, Description, Options, CorrectAnswer, Points", h)}
+		}
+		columns[name] = i
+	}
+	for _, c := range csvRequiredColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, []string{fmt.Sprintf("line 1 : missing required column '%s'", c)}
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	values := func(field string) []string {
+		if field == "" {
+			return []string{}
+		}
+		return strings.Split(field, config.TestImportValueSeparator)
+	}
+
+	questions := []dto.TestQuestionData{}
+	lineErrs := []string{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				lineErrs = append(lineErrs, fmt.Sprintf("line %d : %v", parseErr.StartLine, parseErr.Err))
+				// a wrong number of fields still returns the record, anything else cannot be recovered from
+				if errors.Is(parseErr.Err, csv.ErrFieldCount) {
+					continue
+				}
+				break
+			}
+			lineErrs = append(lineErrs, err.Error())
+			break
+		}
+		line, _ := reader.FieldPos(0)
+
+		question := dto.TestQuestionData{
+			Position: int32(len(questions) + len(lineErrs)),
+			Type: field(record, "type"),
+			Title: field(record, "title"),
+			Description: field(record, "description"),
+			Options: values(field(record, "options")),
+			CorrectAnswer: values(field(record, "correctanswer")),
+			Points: 1,
+		}
+		if points := field(record, "points"); points != "" {
+			p, err := strconv.ParseInt(points, 10, 32)
+			if err != nil {
+				lineErrs = append(lineErrs, fmt.Sprintf("line %d : points must be a number", line))
+				continue
+			}
+			question.Points = int32(p)
+		}
+
+		err = Validate(&question)
+		if err != nil {
+			lineErrs = append(lineErrs, fmt.Sprintf("line %d : %v", line, err))
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	return checkParsed(questions, lineErrs)
+}
+
+// ParseJSON parses and validates the questions in a JSON file, see the format above.
+// The errors point to the line where the question's object starts.
+func ParseJSON(data []byte) ([]dto.TestQuestionData, []string) {
+
+	// lineAt returns the line of the first token at or after the offset
+	lineAt := func(offset int64) int {
+		for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+			offset++
+		}
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('[') {
+		return nil, []string{fmt.Sprintf("line %d : the file must contain an array of questions", lineAt(decoder.InputOffset()))}
+	}
+
+	questions := []dto.TestQuestionData{}
+	lineErrs := []string{}
+	for decoder.More() {
+		line := lineAt(decoder.InputOffset())
+
+		question := dto.TestQuestionData{
+			Points: 1,
+		}
+		err = decoder.Decode(&question)
+		if err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				lineErrs = append(lineErrs, fmt.Sprintf("line %d : invalid value for %s", line, typeErr.Field))
+				continue
+			}
+			// syntax errors cannot be recovered from
+			lineErrs = append(lineErrs, fmt.Sprintf("line %d : %v", lineAt(decoder.InputOffset()), err))
+			break
+		}
+		question.Position = int32(len(questions) + len(lineErrs))
+		if question.Options == nil {
+			question.Options = []string{}
+		}
+
+		err = Validate(&question)
+		if err != nil {
+			lineErrs = append(lineErrs, fmt.Sprintf("line %d : %v", line, err))
+			continue
+		}
+		questions = append(questions, question)
+	}
+
+	return checkParsed(questions, lineErrs)
+}
+
+func checkParsed(questions []dto.TestQuestionData, lineErrs []string) ([]dto.TestQuestionData, []string) {
+	if len(lineErrs) > 0 {
+		return nil, lineErrs
+	}
+	if len(questions) == 0 {
+		return nil, []string{"the file does not contain any questions"}
+	}
+	if len(questions) > config.TestImportMaxQuestions {
+		return nil, []string{fmt.Sprintf("a test can have at most %d questions", config.TestImportMaxQuestions)}
+	}
+	return questions, nil
+}
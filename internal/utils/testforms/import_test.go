@@ -0,0 +1,177 @@
+package testforms
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"go.mod/internal/dto"
+)
+
+func TestParseCSV(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data string
+		// the titles of the parsed questions, in order
+		titles []string
+		// a part of every error, in order
+		errs []string
+	}{
+		{
+			name: "every type",
+			data: "Type,Title,Description,Options,CorrectAnswer,Points\n" +
+				"SingleChoice,2 + 2 = ?,,3|4|5,4,2\n" +
+				"MultipleChoice,Primes,,2|3|4,3|2,\n" +
+				"ShortText,Capital of France,,,Paris,1\n" +
+				"Paragraph,Explain,,,,5\n",
+			titles: []string{"2 + 2 = ?", "Primes", "Capital of France", "Explain"},
+		},
+		{
+			name: "columns in any order and case",
+			data: "correctanswer,TITLE,type\nParis,Capital of France,ShortText\n",
+			titles: []string{"Capital of France"},
+		},
+		{
+			name: "unknown column",
+			data: "Type,Title,CorrectAnswer,Marks\nShortText,Capital,Paris,1\n",
+			errs: []string{"line 1 : unknown column 'Marks'"},
+		},
+		{
+			name: "missing required column",
+			data: "Type,Title\nShortText,Capital\n",
+			errs: []string{"line 1 : missing required column 'correctanswer'"},
+		},
+		{
+			name: "every invalid line is reported",
+			data: "Type,Title,Options,CorrectAnswer,Points\n" +
+				"SingleChoice,2 + 2 = ?,3|4,5,1\n" +
+				"ShortText,Capital,,Paris,one\n" +
+				"Essay,Explain,,,1\n",
+			errs: []string{
+				"line 2 : correct answer '5' is not one of the options",
+				"line 3 : points must be a number",
+				"line 4 : invalid question type 'Essay'",
+			},
+		},
+		{
+			name: "wrong number of fields",
+			data: "Type,Title,CorrectAnswer\nShortText,Capital\nShortText,Capital,Paris\n",
+			errs: []string{"line 2 : wrong number of fields"},
+		},
+		{
+			name: "no questions",
+			data: "Type,Title,CorrectAnswer\n",
+			errs: []string{"the file does not contain any questions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, lineErrs := ParseCSV([]byte(tt.data))
+			checkParseResult(t, questions, lineErrs, tt.titles, tt.errs)
+		})
+	}
+}
+
+func TestParseCSVFields(t *testing.T) {
+
+	questions, lineErrs := ParseCSV([]byte("Type,Title,Options,CorrectAnswer\nMultipleChoice, Primes ,2| 3 |4,3|2\n"))
+	if len(lineErrs) > 0 {
+		t.Fatalf("unexpected errors : %v", lineErrs)
+	}
+	q := questions[0]
+	if q.Title != "Primes" || q.Points != 1 || q.Position != 0 {
+		t.Errorf("got title %q, points %d, position %d", q.Title, q.Points, q.Position)
+	}
+	if !slices.Equal(q.Options, []string{"2", "3", "4"}) {
+		t.Errorf("got options %v", q.Options)
+	}
+	// the answers of a MultipleChoice question are in the order of the options
+	if !slices.Equal(q.CorrectAnswer, []string{"2", "3"}) {
+		t.Errorf("got correct answer %v", q.CorrectAnswer)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data string
+		titles []string
+		errs []string
+	}{
+		{
+			name: "questions",
+			data: `[
+	{"Type": "ShortText", "Title": "Capital of France", "CorrectAnswer": ["Paris"]},
+	{"Type": "SingleChoice", "Title": "2 + 2 = ?", "Options": ["3", "4"], "CorrectAnswer": ["4"], "Points": 2}
+]`,
+			titles: []string{"Capital of France", "2 + 2 = ?"},
+		},
+		{
+			name: "not an array",
+			data: `{"Type": "ShortText"}`,
+			errs: []string{"line 1 : the file must contain an array of questions"},
+		},
+		{
+			name: "errors point to the line of the question",
+			data: `[
+	{"Type": "ShortText", "Title": "Capital of France", "CorrectAnswer": ["Paris"]},
+	{"Type": "ShortText", "Title": "", "CorrectAnswer": ["Paris"]},
+	{"Type": "ShortText", "Title": "Capital", "Points": "one"}
+]`,
+			errs: []string{
+				"line 3 : question title is required",
+				"line 4 : invalid value for Points",
+			},
+		},
+		{
+			name: "syntax error",
+			data: "[\n\t{\"Type\": \"ShortText\",}\n]",
+			errs: []string{"line 2 : invalid character"},
+		},
+		{
+			name: "empty array",
+			data: `[]`,
+			errs: []string{"the file does not contain any questions"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, lineErrs := ParseJSON([]byte(tt.data))
+			checkParseResult(t, questions, lineErrs, tt.titles, tt.errs)
+		})
+	}
+}
+
+func checkParseResult(t *testing.T, questions []dto.TestQuestionData, lineErrs []string, titles []string, errs []string) {
+	t.Helper()
+
+	if len(lineErrs) != len(errs) {
+		t.Fatalf("got errors %q, want %q", lineErrs, errs)
+	}
+	for i := range errs {
+		if !strings.Contains(lineErrs[i], errs[i]) {
+			t.Errorf("error %d : got %q, want it to contain %q", i, lineErrs[i], errs[i])
+		}
+	}
+	if len(errs) > 0 {
+		if questions != nil {
+			t.Errorf("got %d questions with errors, want none", len(questions))
+		}
+		return
+	}
+
+	got := []string{}
+	for i, q := range questions {
+		got = append(got, q.Title)
+		if q.Position != int32(i) {
+			t.Errorf("question %d : got position %d", i, q.Position)
+		}
+	}
+	if !slices.Equal(got, titles) {
+		t.Errorf("got questions %q, want %q", got, titles)
+	}
+}
@@ -0,0 +1,110 @@
+package testforms
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+
+	"google.golang.org/api/forms/v1"
+)
+
+// the questions and options of a test can be shuffled for every user, so that the candidates sitting next to each other see different orders
+// the shuffle is seeded by the test's shuffle seed and the user id, the same user always gets the same order
+// so the order a candidate saw can be reproduced later for audits (see ShuffleOrder, ShuffleOptions)
+// the responses are stored as the option values, grading does not depend on the order
+
+// Seed derives the seed of a user's shuffle from the test's seed, the parts (user id, item id) are hashed in order.
+func Seed(testSeed int64, parts ...string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strconv.FormatInt(testSeed, 10)))
+	for _, p := range parts {
+		h.Write([]byte{0})
+		h.Write([]byte(p))
+	}
+	return h.Sum64()
+}
+
+// ShuffleOrder returns the order of the items for a user, the input is not modified.
+// Only the questions are moved, and only within their own section,
+// the other items (page breaks, text, images) keep their place so the section boundaries stay the same.
+func ShuffleOrder(order []string, sections map[string]int32, questions map[string]bool, seed uint64) []string {
+
+	shuffled := make([]string, len(order))
+	copy(shuffled, order)
+
+	r := rand.New(rand.NewPCG(seed, seed>>1))
+
+	// collect the positions of the questions of every section, in order
+	positions := map[int32][]int{}
+	sectionOrder := []int32{}
+	for i, itemID := range shuffled {
+		if !questions[itemID] {
+			continue
+		}
+		section := sections[itemID]
+		if _, ok := positions[section]; !ok {
+			sectionOrder = append(sectionOrder, section)
+		}
+		positions[section] = append(positions[section], i)
+	}
+
+	// the sections are shuffled one after the other so the result does not depend on map iteration
+	for _, section := range sectionOrder {
+		pos := positions[section]
+		r.Shuffle(len(pos), func(i, j int) {
+			shuffled[pos[i]], shuffled[pos[j]] = shuffled[pos[j]], shuffled[pos[i]]
+		})
+	}
+
+	return shuffled
+}
+
+// ShuffleOptions shuffles the options of a choice question in place, other items are left as they are.
+// The "other" option always stays last.
+func ShuffleOptions(item *forms.Item, seed uint64) {
+
+	if item == nil || item.QuestionItem == nil || item.QuestionItem.Question == nil || item.QuestionItem.Question.ChoiceQuestion == nil {
+		return
+	}
+	options := item.QuestionItem.Question.ChoiceQuestion.Options
+
+	n := len(options)
+	if n > 0 && options[n-1].IsOther {
+		n--
+	}
+
+	r := rand.New(rand.NewPCG(seed, seed>>1))
+	r.Shuffle(n, func(i, j int) {
+		options[i], options[j] = options[j], options[i]
+	})
+}
+
+// OptionOrder arranges the selected values of a choice question in the order of its options,
+// the answer key of a MultipleChoice question is stored in that order and the responses are compared as arrays.
+// Values that are not options are kept at the end in the order they were sent.
+func OptionOrder(item *forms.Item, response []string) []string {
+
+	if item == nil || item.QuestionItem == nil || item.QuestionItem.Question == nil || item.QuestionItem.Question.ChoiceQuestion == nil {
+		return response
+	}
+
+	selected := map[string]int{}
+	for _, r := range response {
+		selected[r]++
+	}
+
+	ordered := make([]string, 0, len(response))
+	for _, o := range item.QuestionItem.Question.ChoiceQuestion.Options {
+		for ; selected[o.Value] > 0; selected[o.Value]-- {
+			ordered = append(ordered, o.Value)
+		}
+	}
+	for _, r := range response {
+		if selected[r] > 0 {
+			ordered = append(ordered, r)
+			selected[r]--
+		}
+	}
+
+	return ordered
+}
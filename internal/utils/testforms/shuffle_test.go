@@ -0,0 +1,165 @@
+package testforms
+
+import (
+	"slices"
+	"testing"
+
+	"google.golang.org/api/forms/v1"
+)
+
+func TestShuffleOrder(t *testing.T) {
+
+	// two sections, a text item in the first and a page break between them
+	order := []string{"q1", "q2", "text", "q3", "q4", "break", "q5", "q6", "q7"}
+	sections := map[string]int32{"q1": 0, "q2": 0, "text": 0, "q3": 0, "q4": 0, "break": 1, "q5": 1, "q6": 1, "q7": 1}
+	questions := map[string]bool{"q1": true, "q2": true, "q3": true, "q4": true, "q5": true, "q6": true, "q7": true}
+
+	tests := []struct {
+		name string
+		seed uint64
+	}{
+		{"seed 1", Seed(1, "10")},
+		{"seed 2", Seed(1, "11")},
+		{"seed 3", Seed(2, "10")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := slices.Clone(order)
+			shuffled := ShuffleOrder(input, sections, questions, tt.seed)
+
+			if !slices.Equal(input, order) {
+				t.Fatalf("the input was modified : %v", input)
+			}
+			if len(shuffled) != len(order) {
+				t.Fatalf("got %d items, want %d", len(shuffled), len(order))
+			}
+			for i, itemID := range order {
+				// the other items keep their place
+				if !questions[itemID] && shuffled[i] != itemID {
+					t.Errorf("item %s moved to %s's place", itemID, shuffled[i])
+				}
+				// the questions stay in their section
+				if sections[shuffled[i]] != sections[itemID] {
+					t.Errorf("item %s moved out of section %d", shuffled[i], sections[shuffled[i]])
+				}
+			}
+			if sorted := slices.Sorted(slices.Values(shuffled)); !slices.Equal(sorted, slices.Sorted(slices.Values(order))) {
+				t.Errorf("got items %v, want %v", shuffled, order)
+			}
+			// the same seed always gives the same order
+			if again := ShuffleOrder(order, sections, questions, tt.seed); !slices.Equal(again, shuffled) {
+				t.Errorf("got %v, then %v for the same seed", shuffled, again)
+			}
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+
+	tests := []struct {
+		name string
+		a, b uint64
+		same bool
+	}{
+		{"same parts", Seed(7, "10", "item"), Seed(7, "10", "item"), true},
+		{"different user", Seed(7, "10"), Seed(7, "11"), false},
+		{"different test seed", Seed(7, "10"), Seed(8, "10"), false},
+		{"parts are not concatenated", Seed(7, "1", "0"), Seed(7, "10"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.a == tt.b) != tt.same {
+				t.Errorf("got %d and %d, want same = %v", tt.a, tt.b, tt.same)
+			}
+		})
+	}
+}
+
+func choiceItem(values ...string) *forms.Item {
+	options := []*forms.Option{}
+	for _, v := range values {
+		options = append(options, &forms.Option{Value: v})
+	}
+	return &forms.Item{
+		QuestionItem: &forms.QuestionItem{
+			Question: &forms.Question{
+				ChoiceQuestion: &forms.ChoiceQuestion{Options: options},
+			},
+		},
+	}
+}
+
+func TestShuffleOptions(t *testing.T) {
+
+	tests := []struct {
+		name string
+		item *forms.Item
+		// the last option stays in place
+		other bool
+	}{
+		{"choice", choiceItem("a", "b", "c", "d", "e"), false},
+		{"other stays last", choiceItem("a", "b", "c", "d", ""), true},
+		{"text question", &forms.Item{QuestionItem: &forms.QuestionItem{Question: &forms.Question{}}}, false},
+		{"not a question", &forms.Item{}, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.other {
+				options := tt.item.QuestionItem.Question.ChoiceQuestion.Options
+				options[len(options)-1].IsOther = true
+			}
+			before := optionValues(tt.item)
+
+			ShuffleOptions(tt.item, Seed(1, "10", "item"))
+
+			after := optionValues(tt.item)
+			if !slices.Equal(slices.Sorted(slices.Values(after)), slices.Sorted(slices.Values(before))) {
+				t.Errorf("got options %v, want a permutation of %v", after, before)
+			}
+			if tt.other && after[len(after)-1] != before[len(before)-1] {
+				t.Errorf("the other option moved, got %v", after)
+			}
+		})
+	}
+}
+
+func optionValues(item *forms.Item) []string {
+	values := []string{}
+	if item == nil || item.QuestionItem == nil || item.QuestionItem.Question.ChoiceQuestion == nil {
+		return values
+	}
+	for _, o := range item.QuestionItem.Question.ChoiceQuestion.Options {
+		values = append(values, o.Value)
+	}
+	return values
+}
+
+func TestOptionOrder(t *testing.T) {
+
+	tests := []struct {
+		name string
+		item *forms.Item
+		response []string
+		want []string
+	}{
+		{"in the order of the options", choiceItem("a", "b", "c"), []string{"c", "a"}, []string{"a", "c"}},
+		{"already ordered", choiceItem("a", "b", "c"), []string{"a", "b"}, []string{"a", "b"}},
+		{"values that are not options are last", choiceItem("a", "b", "c"), []string{"x", "b", "a"}, []string{"a", "b", "x"}},
+		{"repeated values are kept", choiceItem("a", "b"), []string{"b", "a", "b"}, []string{"a", "b", "b"}},
+		{"empty response", choiceItem("a", "b"), []string{}, []string{}},
+		{"not a choice question", &forms.Item{}, []string{"b", "a"}, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OptionOrder(tt.item, tt.response)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
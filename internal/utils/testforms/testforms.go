@@ -0,0 +1,233 @@
+package testforms
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"google.golang.org/api/forms/v1"
+)
+
+// tests built on the platform (Manual, CSVJSON) store their questions in the testquestions table
+// they are converted to the same *forms.Form the Forms API returns,
+// so taking the test, caching it and evaluating it stays the same for every upload method
+// tests hosted outside the platform (GForms) are served by the apicalls.TestProvider
+
+// a test can be split into sections (testsections), the sections of the form are split by page breaks
+// the n-th section of the form (see apicalls.SectionIndex) is the n-th row of the test's sections ordered by position
+// for the tests built on the platform, a page break is added at the start of every section
+
+// Questions returns the form of a test without the answer key, as it is served to the students.
+func Questions(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, testID int64, uploadMethod string, fileID string) (*forms.Form, error) {
+
+	switch uploadMethod {
+	case config.TestUploadManual, config.TestUploadCSVJSON:
+		questions, err := queries.GetTestQuestions(ctx, testID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get test questions : %v", err)
+		}
+		sections, err := queries.ListTestSections(ctx, testID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get test sections : %v", err)
+		}
+		return FromQuestions(questions, sections), nil
+	default:
+		return tests.GetQuestions(fileID)
+	}
+}
+
+// AnswerKey returns the correct answers and points of every question of a test.
+func AnswerKey(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, testID int64, uploadMethod string, fileID string) ([]dto.TestAnswer, error) {
+
+	switch uploadMethod {
+	case config.TestUploadManual, config.TestUploadCSVJSON:
+		questions, err := queries.GetTestQuestions(ctx, testID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get test questions : %v", err)
+		}
+		sections, err := queries.ListTestSections(ctx, testID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get test sections : %v", err)
+		}
+		answers := []dto.TestAnswer{}
+		for _, q := range questions {
+			answers = append(answers, dto.TestAnswer{
+				QuestionID: q.ItemID,
+				CorrectAnswer: q.CorrectAnswer,
+				Points: int64(q.Points),
+				Manual: q.Type == config.QuestionShortText || q.Type == config.QuestionParagraph,
+				Multiple: q.Type == config.QuestionMultipleChoice,
+				Section: SectionOf(q, sections),
+			})
+		}
+		return answers, nil
+	default:
+		return tests.GetAnswerKey(fileID)
+	}
+}
+
+// SectionOf returns the index of the question's section, the questions without a section are in the first one.
+func SectionOf(q sqlc.GetTestQuestionsRow, sections []sqlc.ListTestSectionsRow) int32 {
+	for i, s := range sections {
+		if q.SectionID.Valid && s.SectionID == q.SectionID.Int64 {
+			return int32(i)
+		}
+	}
+	return 0
+}
+
+// FromQuestions converts the questions from the db to a form without the answer key, the item id is used as the question id too.
+// A page break with the section's name starts every section, in the order of the sections and even if a section has no questions,
+// so the n-th page break of the form is the n-th section (see apicalls.SectionIndex).
+func FromQuestions(questions []sqlc.GetTestQuestionsRow, sections []sqlc.ListTestSectionsRow) *forms.Form {
+
+	form := &forms.Form{
+		Items: make([]*forms.Item, 0, len(questions) + len(sections)),
+	}
+
+	if len(sections) == 0 {
+		for _, q := range questions {
+			form.Items = append(form.Items, questionItem(q))
+		}
+		return form
+	}
+
+	// the questions keep their order within their section
+	bySection := make([][]sqlc.GetTestQuestionsRow, len(sections))
+	for _, q := range questions {
+		i := SectionOf(q, sections)
+		bySection[i] = append(bySection[i], q)
+	}
+	for i, s := range sections {
+		form.Items = append(form.Items, &forms.Item{
+			ItemId: fmt.Sprintf("section%d", s.SectionID),
+			Title: s.Name,
+			PageBreakItem: &forms.PageBreakItem{},
+		})
+		for _, q := range bySection[i] {
+			form.Items = append(form.Items, questionItem(q))
+		}
+	}
+
+	return form
+}
+
+// questionItem converts a question from the db to an item of the form without the answer key
+func questionItem(q sqlc.GetTestQuestionsRow) *forms.Item {
+
+	question := &forms.Question{
+		QuestionId: q.ItemID,
+		Required: false,
+	}
+
+	switch q.Type {
+	case config.QuestionSingleChoice, config.QuestionMultipleChoice:
+		choice := &forms.ChoiceQuestion{
+			Type: "RADIO",
+		}
+		if q.Type == config.QuestionMultipleChoice {
+			choice.Type = "CHECKBOX"
+		}
+		for _, o := range q.Options {
+			choice.Options = append(choice.Options, &forms.Option{Value: o})
+		}
+		question.ChoiceQuestion = choice
+	case config.QuestionShortText:
+		question.TextQuestion = &forms.TextQuestion{Paragraph: false}
+	case config.QuestionParagraph:
+		question.TextQuestion = &forms.TextQuestion{Paragraph: true}
+	}
+
+	return &forms.Item{
+		ItemId: q.ItemID,
+		Title: q.Title,
+		Description: q.Description.String,
+		QuestionItem: &forms.QuestionItem{
+			Question: question,
+		},
+	}
+}
+
+// Validate checks a question before it is stored, the returned error is meant for the user.
+// The options and answers are trimmed and the MultipleChoice answers are arranged in the order of the options,
+// the responses are compared as arrays so the order matters.
+func Validate(q *dto.TestQuestionData) error {
+
+	q.Title = strings.TrimSpace(q.Title)
+	if q.Title == "" {
+		return fmt.Errorf("question title is required")
+	}
+	if q.Points < 0 {
+		return fmt.Errorf("points cannot be negative")
+	}
+	if q.Position < 0 {
+		return fmt.Errorf("position cannot be negative")
+	}
+	for i := range q.Options {
+		q.Options[i] = strings.TrimSpace(q.Options[i])
+	}
+	for i := range q.CorrectAnswer {
+		q.CorrectAnswer[i] = strings.TrimSpace(q.CorrectAnswer[i])
+	}
+
+	switch q.Type {
+	case config.QuestionSingleChoice, config.QuestionMultipleChoice:
+		if len(q.Options) < 2 {
+			return fmt.Errorf("a %s question needs at least 2 options", q.Type)
+		}
+		for i, o := range q.Options {
+			if o == "" {
+				return fmt.Errorf("option %d is empty", i + 1)
+			}
+			if slices.Index(q.Options, o) != i {
+				return fmt.Errorf("option '%s' is repeated", o)
+			}
+		}
+		for _, a := range q.CorrectAnswer {
+			if !slices.Contains(q.Options, a) {
+				return fmt.Errorf("correct answer '%s' is not one of the options", a)
+			}
+		}
+		if q.Type == config.QuestionSingleChoice && len(q.CorrectAnswer) != 1 {
+			return fmt.Errorf("a SingleChoice question must have exactly 1 correct answer")
+		}
+		if len(q.CorrectAnswer) == 0 {
+			return fmt.Errorf("a MultipleChoice question must have at least 1 correct answer")
+		}
+		// arrange the answers in the order of the options, this also drops repeated answers
+		ordered := []string{}
+		for _, o := range q.Options {
+			if slices.Contains(q.CorrectAnswer, o) {
+				ordered = append(ordered, o)
+			}
+		}
+		q.CorrectAnswer = ordered
+	case config.QuestionShortText:
+		if len(q.Options) != 0 {
+			return fmt.Errorf("a ShortText question cannot have options")
+		}
+		if len(q.CorrectAnswer) != 1 || q.CorrectAnswer[0] == "" {
+			return fmt.Errorf("a ShortText question must have exactly 1 correct answer")
+		}
+		// the column is NOT NULL, a nil slice is sent as NULL
+		q.Options = []string{}
+	case config.QuestionParagraph:
+		if len(q.Options) != 0 {
+			return fmt.Errorf("a Paragraph question cannot have options")
+		}
+		if len(q.CorrectAnswer) != 0 {
+			return fmt.Errorf("a Paragraph question is graded manually and cannot have a correct answer")
+		}
+		q.Options = []string{}
+		q.CorrectAnswer = []string{}
+	default:
+		return fmt.Errorf("invalid question type '%s', must be one of %s, %s, %s, %s", q.Type, config.QuestionSingleChoice, config.QuestionMultipleChoice, config.QuestionShortText, config.QuestionParagraph)
+	}
+
+	return nil
+}
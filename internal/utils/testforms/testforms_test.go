@@ -0,0 +1,83 @@
+package testforms
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
+	sqlc "go.mod/internal/sqlc/generate"
+)
+
+func TestFromQuestionsSections(t *testing.T) {
+
+	question := func(id string, sectionID int64) sqlc.GetTestQuestionsRow {
+		return sqlc.GetTestQuestionsRow{
+			ItemID: id,
+			Type: config.QuestionShortText,
+			Title: id,
+			SectionID: pgtype.Int8{Int64: sectionID, Valid: sectionID > 0},
+		}
+	}
+	sections := []sqlc.ListTestSectionsRow{
+		{SectionID: 10, Name: "Aptitude"},
+		{SectionID: 20, Name: "Empty"},
+		{SectionID: 30, Name: "Coding"},
+	}
+
+	tests := []struct {
+		name string
+		questions []sqlc.GetTestQuestionsRow
+		sections []sqlc.ListTestSectionsRow
+		// the section index of every question
+		want map[string]int32
+	}{
+		{
+			name: "no sections",
+			questions: []sqlc.GetTestQuestionsRow{question("q1", 0), question("q2", 0)},
+			want: map[string]int32{"q1": 0, "q2": 0},
+		},
+		{
+			name: "an empty section keeps its position",
+			questions: []sqlc.GetTestQuestionsRow{question("q1", 30), question("q2", 10), question("q3", 30)},
+			sections: sections,
+			want: map[string]int32{"q1": 2, "q2": 0, "q3": 2},
+		},
+		{
+			name: "a question without a section is in the first one",
+			questions: []sqlc.GetTestQuestionsRow{question("q1", 0), question("q2", 20)},
+			sections: sections,
+			want: map[string]int32{"q1": 0, "q2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := FromQuestions(tt.questions, tt.sections)
+
+			index := apicalls.SectionIndex(form)
+			got := map[string]int32{}
+			breaks := 0
+			for _, item := range form.Items {
+				if item.PageBreakItem != nil {
+					breaks++
+					continue
+				}
+				got[item.ItemId] = index[item.ItemId]
+				// SectionOf and the section of the item in the form agree
+				for _, q := range tt.questions {
+					if q.ItemID == item.ItemId && SectionOf(q, tt.sections) != index[item.ItemId] {
+						t.Errorf("question %s : SectionOf is %d, the form has it in %d", q.ItemID, SectionOf(q, tt.sections), index[item.ItemId])
+					}
+				}
+			}
+			if breaks != len(tt.sections) {
+				t.Errorf("got %d page breaks, want one for every section, %d", breaks, len(tt.sections))
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("got sections %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
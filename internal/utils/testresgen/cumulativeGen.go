@@ -1,39 +1,63 @@
 package testresgen
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"time"
 
 	"github.com/go-echarts/go-echarts/v2/components"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
 	"go.mod/internal/dto"
 	gocharts "go.mod/internal/go-charts"
 	sqlc "go.mod/internal/sqlc/generate"
 	"go.mod/internal/utils"
 	"go.mod/internal/utils/ctxutils"
+	"go.mod/internal/utils/testforms"
+	"google.golang.org/api/forms/v1"
 )
 
+// ErrGradingPending is returned when the result cannot be generated yet as some responses wait for manual grading
+var ErrGradingPending = errors.New("responses are pending manual grading")
+
 type resultData struct {
 	ctx context.Context
 	queries *sqlc.Queries
-	gapi *apicalls.Caller
+	tests apicalls.TestProvider
 
 	testID int64
 
 	totalPoints int64
+	cutoffMarks int64
 	testData sqlc.TestDataRow
+	// the answer key the responses were evaluated with
+	answers []dto.TestAnswer
+	// the form of a test from the provider, nil for the tests built on the platform
+	form *forms.Form
+
+}
 
+// resultDraft is a version of the result generated in the transaction, written and sent once it is committed
+type resultDraft struct {
+	path string
+	version int32
+	page []byte
 }
 
 // GenerateCumulativeTestResult generates test's cumulative result draft.
 // Returns the internal path to the result file or an error.
-// The result file is an html page that requires internet connectivity to render, 	
-// this is to maintain the interactivity of the charts and graphs
-func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, googleAPI *apicalls.Caller, testid int64) (string, error) {
+// The result file is an html page with its scripts embedded, it renders without internet connectivity
+// and keeps the interactivity of the charts and graphs
+// Every draft is a new version of the result, stored with the reason it was generated and a snapshot of the scores
+// The drafts of a test are generated one at a time, in a transaction that holds a lock on the test,
+// a draft triggered while another one is being generated waits for it
+// The transaction only does the db work, the form of the test is fetched before it
+// and the result file is written and emailed once it is committed
+func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, testProvider apicalls.TestProvider, testid int64, reason string) (string, error) {
 	// have a separate context as this works async
 	context, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
@@ -42,19 +66,110 @@ func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, googleAPI *apicalls
 	data := &resultData{
 		ctx: context,
 		queries: sqlcQueries,
-		gapi: googleAPI,
+		tests: testProvider,
 		testID: testid,
 	}
 
-	// gets the fileid, calls the API's for the form data, extracts the correct answers from them
+	err := fetchForm(data)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to get the form of test ID : %d : %v", testid, err.Error()),
+		})
+		return "", err
+	}
+
+	tx, err := config.Pool.Begin(context)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to begin transaction for test result for test ID : %d : %v", testid, err.Error()),
+		})
+		return "", err
+	}
+	// a no-op once the transaction is committed
+	defer tx.Rollback(context)
+
+	data.queries = sqlcQueries.WithTx(tx)
+	err = data.queries.LockTestEvaluation(context, testid)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to lock the evaluation of test ID : %d : %v", testid, err.Error()),
+		})
+		return "", err
+	}
+
+	draft, err := generateCumulativeTestResult(data, reason)
+	// the evaluation is kept when the result waits for manual grading, the grading queue is made from it
+	if err != nil && !errors.Is(err, ErrGradingPending) {
+		return "", err
+	}
+	commitErr := tx.Commit(context)
+	if commitErr != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to commit test result for test ID : %d : %v", testid, commitErr.Error()),
+		})
+		return "", commitErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = sendCumulativeTestResult(data, draft, reason)
+	if err != nil {
+		return "", err
+	}
+
+	return draft.path, nil
+}
+
+// fetchForm gets the form and the answer key of a test from its provider, before the test is locked.
+// The tests built on the platform are read in the transaction with the rest of the evaluation.
+func fetchForm(data *resultData) error {
+
+	testData, err := data.queries.TestData(data.ctx, data.testID)
+	if err != nil {
+		return err
+	}
+	if testData.UploadMethod == config.TestUploadManual || testData.UploadMethod == config.TestUploadCSVJSON {
+		return nil
+	}
+	data.form, err = data.tests.GetQuestions(testData.FileID)
+	if err != nil {
+		return err
+	}
+	data.answers, err = data.tests.GetAnswerKey(testData.FileID)
+	return err
+}
+
+// generateCumulativeTestResult evaluates the test and generates a new version of its result draft, with the queries of the locked transaction
+func generateCumulativeTestResult(data *resultData, reason string) (*resultDraft, error) {
+
+	testid := data.testID
+
+	// gets the correct answers of the test, from the form fetched before the lock or from the db
 	// and evaluates the test responses, updates the test results for score, etc
 	err := evaluate(data)
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
 			Critical: fmt.Sprintf("Failed to evaluate test result for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return nil, err
+	}
+
+	// the result waits until every subjective response has been graded
+	// it is generated again once the company grades the last one
+	pending, err := data.queries.PendingGradingCount(data.ctx, testid)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to get pending grading count for test ID : %d : %v", testid, err.Error()),
+		})
+		return nil, err
 	}
+	if pending > 0 {
+		return nil, ErrGradingPending
+	}
+
+	factor := float64(data.testData.Threshold) / float64(100)
+	data.cutoffMarks = int64(factor * float64(data.totalPoints))
 
 	// this function is responsible for generating all the charts for the result
 	page, err := generateCumulativeCharts(data)
@@ -62,45 +177,97 @@ func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, googleAPI *apicalls
 		ctxutils.NewError(&dto.ErrorData{
 			Critical: fmt.Sprintf("Failed to generate cumulative charts for test result for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return nil, err
 	}
 
-	// the file strucuture : ./test_result/{testid}/individual/...individual_results
-	//                       ./test_result/{testid}/...cumulative_result
-	// older versions of the result are over-written and only the latest is stored for the simplicity, versioning can be added later
-	// a new file is created if none exists
-
-	resultDir := fmt.Sprintf("%s%d/%s", os.Getenv("TestResultStorageDir"), testid, "individual")
-	err = os.MkdirAll(resultDir, 0755)
+	version, err := data.queries.NewResultVersion(data.ctx, sqlc.NewResultVersionParams{
+		TestID: testid,
+		Reason: reason,
+		Threshold: data.testData.Threshold,
+		CutoffMarks: data.cutoffMarks,
+		TotalPoints: data.totalPoints,
+	})
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
-			Critical: fmt.Sprintf("Failed to create directories for test result for test ID : %d : %v", testid, err.Error()),
+			Critical: fmt.Sprintf("Failed to create result version for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return nil, err
 	}
-
-	cResultPath := fmt.Sprintf("%s%d/%d&%s%s", os.Getenv("TestResultStorageDir"), testid, testid, "testresult", ".html")
-	file, err := os.Create(cResultPath)
+	err = data.queries.SnapshotResultScores(data.ctx, sqlc.SnapshotResultScoresParams{
+		TestID: testid,
+		VersionID: version.VersionID,
+		CutoffMarks: data.cutoffMarks,
+	})
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
-			Critical: fmt.Sprintf("Failed to create result file for test result for test ID : %d : %v", testid, err.Error()),
+			Critical: fmt.Sprintf("Failed to snapshot scores of result version for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return nil, err
 	}
-	defer file.Close()
 
-	// render the charts on the file as html
-	err = page.Render(io.MultiWriter(file))
+	// render the charts as html, with the assets embedded so it opens offline
+	// the page is kept in memory, it is written once the transaction is committed
+	var rendered bytes.Buffer
+	err = gocharts.RenderOffline(page, &rendered)
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
 			Critical: fmt.Sprintf("Failed to render page for test result for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return nil, err
 	}
 
 	// TODO:
 	// further parts of the result are added here
 
+	cResultPath := ResultVersionPath(testid, version.Version)
+
+	// update the test result_url in the db, this is the cumulative result path and not individual results
+	err = data.queries.UpdateTestResultURLUnprotected(data.ctx, sqlc.UpdateTestResultURLUnprotectedParams{
+		TestID: testid,
+		ResultUrl: pgtype.Text{String: cResultPath, Valid: true},
+	})
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to update cumulative result url in db for test ID : %d : %v", testid, err.Error()),
+		})
+		return nil, err
+	}
+
+	// return the draft with no errors
+	return &resultDraft{
+		path: cResultPath,
+		version: version.Version,
+		page: rendered.Bytes(),
+	}, nil
+}
+
+// sendCumulativeTestResult writes the committed draft to its result file and emails it to the company's representative
+func sendCumulativeTestResult(data *resultData, draft *resultDraft, reason string) error {
+
+	testid := data.testID
+
+	// the file strucuture : ./test_result/{testid}/individual/...individual_results
+	//                       ./test_result/{testid}/...cumulative_result of every version
+	// every draft is stored as a new version along with the scores and the pass / fail of every student at the time,
+	// the versions can be compared before the result is published
+
+	resultDir := fmt.Sprintf("%s%d/%s", os.Getenv("TestResultStorageDir"), testid, "individual")
+	err := os.MkdirAll(resultDir, 0755)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to create directories for test result for test ID : %d : %v", testid, err.Error()),
+		})
+		return err
+	}
+
+	err = os.WriteFile(draft.path, draft.page, 0644)
+	if err != nil {
+		ctxutils.NewError(&dto.ErrorData{
+			Critical: fmt.Sprintf("Failed to write result file for test result for test ID : %d : %v", testid, err.Error()),
+		})
+		return err
+	}
+
 	// construct a local struct for the email data
 	emailData := struct {
 		CompanyName string
@@ -109,6 +276,8 @@ func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, googleAPI *apicalls
 		EndTime string
 		Threshold int32
 		TimeNow string
+		Version int32
+		Reason string
 	} {
 		data.testData.CompanyName,
 		data.testData.TestID,
@@ -116,42 +285,33 @@ func GenerateCumulativeTestResult(sqlcQueries *sqlc.Queries, googleAPI *apicalls
 		data.testData.EndTime,
 		data.testData.Threshold,
 		time.Now().Local().Format("03:04 PM 02-01-2006"),
+		draft.version,
+		reason,
 	}
 
-	fmt.Println("dynamic template")
 	// generate the email template
 	template, err := utils.DynamicHTML("./template/company/emails/resultdraft.html", emailData)
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
 			Critical: fmt.Sprintf("Failed to generate dynamic email template for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return err
 	}
 	// send the email 
-	err = utils.SendEmailHTMLWithAttachmentFilePath(template, []string{data.testData.RepresentativeEmail}, cResultPath, fmt.Sprintf("%dresult%s", data.testData.TestID, ".html"))
+	err = utils.SendEmailHTMLWithAttachmentFilePath(template, []string{data.testData.RepresentativeEmail}, draft.path, fmt.Sprintf("%dresult%s", data.testData.TestID, ".html"))
 	if err != nil {
 		ctxutils.NewError(&dto.ErrorData{
 			Critical: fmt.Sprintf("Failed to send email with result for test ID : %d : %v", testid, err.Error()),
 		})
-		return "", err
+		return err
 	}
 
-	fmt.Println(data.testData.RepresentativeEmail)
-
-	// update the test result_url in the db, this is the cumulative result path and not individual results
-	err = sqlcQueries.UpdateTestResultURLUnprotected(context, sqlc.UpdateTestResultURLUnprotectedParams{
-		TestID: testid,
-		ResultUrl: pgtype.Text{String: cResultPath, Valid: true},
-	})
-	if err != nil {
-		ctxutils.NewError(&dto.ErrorData{
-			Critical: fmt.Sprintf("Failed to update cumulative result url in db for test ID : %d : %v", testid, err.Error()),
-		})
-		return "", err
-	}
+	return nil
+}
 
-	// return the result path with no errors
-	return cResultPath, nil
+// ResultVersionPath is the internal path to a version of the test's cumulative result draft
+func ResultVersionPath(testid int64, version int32) string {
+	return fmt.Sprintf("%s%d/%d&%s&v%d%s", os.Getenv("TestResultStorageDir"), testid, testid, "testresult", version, ".html")
 }
 
 func generateCumulativeCharts(data *resultData) (*components.Page, error) { 
@@ -164,11 +324,23 @@ func generateCumulativeCharts(data *resultData) (*components.Page, error) {
 		return nil, err
 	}
 
-	factor := float64(data.testData.Threshold) / float64(100)
-	cutoffMarks := int64(factor * float64(data.totalPoints))
 	passfailCount, err := data.queries.TestPassFailCount(data.ctx, sqlc.TestPassFailCountParams{
 		TestID: data.testID,
-		Score: pgtype.Int8{Int64: cutoffMarks, Valid: true},
+		Score: pgtype.Int8{Int64: data.cutoffMarks, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the pass / fail of every section by its own cutoff, empty if the test has no sections
+	// the overall pass count above already excludes the students who failed any section
+	sectionPassFail, err := data.queries.SectionPassFailCount(data.ctx, data.testID)
+	if err != nil {
+		return nil, err
+	}
+	// the attempts with enough proctoring events to be looked at, or any ip change
+	integrityFlags, err := data.queries.IntegrityFlags(data.ctx, sqlc.IntegrityFlagsParams{
+		TestID: data.testID,
+		MinEvents: config.IntegrityFlagMinEvents,
 	})
 	if err != nil {
 		return nil, err
@@ -202,6 +374,57 @@ func generateCumulativeCharts(data *resultData) (*components.Page, error) {
 
 	// 2) a pie chart that shows the pass / fail ratio
 
+	// 3) a bar chart of the pass / fail count in every section
+	sectionNames := make([]string, 0, len(sectionPassFail))
+	sectionPass := make([]int64, 0, len(sectionPassFail))
+	sectionFail := make([]int64, 0, len(sectionPassFail))
+	for _, s := range sectionPassFail {
+		sectionNames = append(sectionNames, s.Name)
+		sectionPass = append(sectionPass, s.PassCount)
+		sectionFail = append(sectionFail, s.FailCount)
+	}
+
+	// 4) a bar chart of the proctoring events of every flagged attempt, by the event type
+	flaggedStudents := make([]string, 0, len(integrityFlags))
+	flagEvents := make([][]int64, 5)
+	for _, f := range integrityFlags {
+		flaggedStudents = append(flaggedStudents, fmt.Sprintf("%s (%s)", f.StudentName, f.RollNumber))
+		for i, count := range []int64{f.TabSwitches, f.FocusLosses, f.FullscreenExits, f.CopyPastes, f.IpChanges} {
+			flagEvents[i] = append(flagEvents[i], count)
+		}
+	}
+
+	// 5) a bar chart of the pairs of attempts flagged for similar answers
+	similarityFlags, err := data.queries.SimilarityFlags(data.ctx, data.testID)
+	if err != nil {
+		return nil, err
+	}
+	similarPairs := make([]string, 0, len(similarityFlags))
+	sharedWrong := make([]int64, 0, len(similarityFlags))
+	timeDifference := make([]float64, 0, len(similarityFlags))
+	for _, f := range similarityFlags {
+		similarPairs = append(similarPairs, fmt.Sprintf("%s (%s) / %s (%s)", f.StudentNameA, f.RollNumberA, f.StudentNameB, f.RollNumberB))
+		sharedWrong = append(sharedWrong, int64(f.SharedWrong))
+		if f.TimeDifference.Valid {
+			timeDifference = append(timeDifference, f.TimeDifference.Float64 * 100)
+		} else {
+			timeDifference = append(timeDifference, -1)
+		}
+	}
+
+	// 6) the analysis of every question, its difficulty, discrimination, median time and the picks of its options
+	form := data.form
+	if form == nil {
+		form, err = testforms.Questions(data.ctx, data.queries, data.tests, data.testID, data.testData.UploadMethod, data.testData.FileID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	items, err := itemAnalysis(data.ctx, data.queries, data.testData, form, data.answers)
+	if err != nil {
+		return nil, err
+	}
+
 	// more coming soon !
 
 
@@ -211,6 +434,16 @@ func generateCumulativeCharts(data *resultData) (*components.Page, error) {
 		Yaxis: yaxis,
 		PassCount: passfailCount.PassCount,
 		FailCount: passfailCount.FailCount,
+		SectionNames: sectionNames,
+		SectionPass: sectionPass,
+		SectionFail: sectionFail,
+		FlaggedStudents: flaggedStudents,
+		FlagTypes: []string{"Tab Switch", "Focus Loss", "Fullscreen Exit", "Copy / Paste", "IP Change"},
+		FlagEvents: flagEvents,
+		SimilarPairs: similarPairs,
+		SharedWrong: sharedWrong,
+		TimeDifference: timeDifference,
+		Items: items,
 	}
 
 	// we send all that calc data to the go-charts func to create charts out of them
@@ -231,44 +464,84 @@ func evaluate(data *resultData) (error) {
 	if err != nil {
 		return err
 	}
-	// clear the table to store answers, this is done to avoid unique constraint violation error
-	// this can also be nested directly into the insert query or can be sorted with a on conflict clause
-	// but it is not neccessary here, less complexity
-	err = data.queries.ClearAnswersTable(data.ctx)
+	// the points of the previous evaluation are cleared, the key may have changed since
+	// a response that matched the old key, or to a question dropped from it, must not keep its points
+	// the manually graded responses keep theirs
+	err = data.queries.ResetTestPoints(data.ctx, data.testID)
 	if err != nil {
 		return err
 	}
-	// this gets the complete form data including correct answers
-	gForm, err := data.gapi.GetCompleteForm(data.testData.FileID)
+	// clear the answers of the test, this is done to avoid unique constraint violation error
+	// this can also be nested directly into the insert query or can be sorted with a on conflict clause
+	// but it is not neccessary here, less complexity
+	// the keys of the other tests are kept, every query below is scoped to this test
+	err = data.queries.ClearAnswersTable(data.ctx, data.testID)
 	if err != nil {
 		return err
 	}
-	// loop over the form, check if fields exist and extract values
+	// this gets the correct answers and points of every question, the key of a form from the provider is already fetched
+	if data.form == nil {
+		data.answers, err = testforms.AnswerKey(data.ctx, data.queries, data.tests, data.testID, data.testData.UploadMethod, data.testData.FileID)
+		if err != nil {
+			return err
+		}
+	}
 	// insert the {questionId, answer, points} in the temp_answers table
 	// this table is then used to evaluate the responses 
-	for _, b := range gForm.Items {
-		qItem := b.QuestionItem
-		ans := []string{}
-		if (qItem != nil && qItem.Question != nil && qItem.Question.Grading != nil &&
-			qItem.Question.Grading.CorrectAnswers != nil && qItem.Question.Grading.CorrectAnswers.Answers != nil ) {
-				
-			for _, a := range qItem.Question.Grading.CorrectAnswers.Answers {
-				ans = append(ans, a.Value)
-			}
-
-			err = data.queries.InsertAnswers(data.ctx, sqlc.InsertAnswersParams{
-				QuestionID: b.ItemId,
-				CorrectAnswer: ans,
-				Points: pgtype.Int4{Int32: int32(qItem.Question.Grading.PointValue), Valid: true},
-			})
-			if err != nil {
-				return err
-			}
+	for _, a := range data.answers {
+		err = data.queries.InsertAnswers(data.ctx, sqlc.InsertAnswersParams{
+			TestID: data.testID,
+			QuestionID: a.QuestionID,
+			CorrectAnswer: a.CorrectAnswer,
+			Points: pgtype.Int4{Int32: int32(a.Points), Valid: true},
+			Manual: a.Manual,
+			Multiple: a.Multiple,
+			Section: a.Section,
+		})
+		if err != nil {
+			return err
 		}
 	}
+	// every response is tagged with the section of its question, the section scores and cutoffs are computed from it
+	err = data.queries.AssignResponseSections(data.ctx, data.testID)
+	if err != nil {
+		return err
+	}
 	// evaluate the responses accordingly
 	// this also updates the testresults.score with the SUM(points)
-	data.totalPoints, err = data.queries.EvaluateTestResult(data.ctx)
+	data.totalPoints, err = data.queries.EvaluateTestResult(data.ctx, data.testID)
+	if err != nil {
+		return err
+	}
+	// the answer key has the whole pool of the questions drawn from the question bank,
+	// every candidate is only served the drawn count of each pool
+	if data.testData.UploadMethod == config.TestUploadManual || data.testData.UploadMethod == config.TestUploadCSVJSON {
+		drawData, err := data.queries.DrawTotalPoints(data.ctx, data.testID)
+		if err != nil {
+			return err
+		}
+		if drawData.Rules > 0 {
+			data.totalPoints = drawData.TotalPoints
+		}
+	}
+	// the remaining responses are marked by the test's scheme, partial credit on multi-select questions
+	// and negative points for the wrong answers
+	err = data.queries.ApplyMarkingScheme(data.ctx, sqlc.ApplyMarkingSchemeParams{
+		PartialCredit: data.testData.PartialCredit,
+		TestID: data.testID,
+		NegativeMarking: data.testData.NegativeMarking,
+	})
+	if err != nil {
+		return err
+	}
+	// the responses to text questions that did not match exactly are queued for manual grading by the company
+	err = data.queries.FlagResponsesForGrading(data.ctx, data.testID)
+	if err != nil {
+		return err
+	}
+	// the score is recomputed from all the responses, so that the manually graded points are included
+	// it cannot go below the test's min score
+	err = data.queries.RecomputeTestScores(data.ctx, data.testID)
 	if err != nil {
 		return err
 	}
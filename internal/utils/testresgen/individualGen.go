@@ -6,8 +6,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"io"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/go-echarts/go-echarts/v2/opts"
@@ -25,15 +25,18 @@ import (
 type PublishData struct {
 	ctx context.Context
 	queries *sqlc.Queries
-	gapi *apicalls.Caller
+	tests apicalls.TestProvider
 
 	testID int64
 	qCount int64
+	testData sqlc.TestDataRow
 }
 type EmailTask struct {
 	RecipientEmail 	[]string
 	BodyTemplate	bytes.Buffer
 	AttachmentPath 	string
+	// the static version of the result, attached with the page
+	PDFPath			string
 }
 type IndividualEmailData struct {
 	StudentName string
@@ -44,7 +47,7 @@ type IndividualEmailData struct {
 
 }
 
-func PublishTestResults(sqlcQueries *sqlc.Queries, googleAPI *apicalls.Caller, testid int64) (error) {
+func PublishTestResults(sqlcQueries *sqlc.Queries, testProvider apicalls.TestProvider, testid int64) (error) {
 	// the context is cancelled before the workers can finish
 	// use wg.Wait() if context is needed 
 	context, cancelCtx := context.WithCancel(context.Background())
@@ -54,7 +57,7 @@ func PublishTestResults(sqlcQueries *sqlc.Queries, googleAPI *apicalls.Caller, t
 	data := &PublishData{
 		ctx: context,
 		queries: sqlcQueries,
-		gapi: googleAPI,
+		tests: testProvider,
 		testID: testid,
 	}
 
@@ -126,6 +129,7 @@ func enqueueTestResults(taskQ chan *EmailTask, data *PublishData) ([]int64, erro
 	// TODO: this can actually be problematic,
 	// we would want to extract qCount or other data from form instread of the test metadata which cannot be trusted
 	data.qCount = testData.QCount
+	data.testData = testData
 
 	// get all data neeeded to generate result
 	stResult, err := data.queries.StudentTestResult(data.ctx, data.testID)
@@ -138,7 +142,7 @@ func enqueueTestResults(taskQ chan *EmailTask, data *PublishData) ([]int64, erro
 
 		curr := stResult[i]
 		// generate result for individual student
-		resultPath, err := generateIndividualCharts(data, &curr)
+		resultPath, pdfPath, err := generateIndividualCharts(data, &curr)
 		if err != nil {
 			// TODO: well the actual error string is still not being logged
 			failedGen = append(failedGen, curr.ResultID)
@@ -161,6 +165,7 @@ func enqueueTestResults(taskQ chan *EmailTask, data *PublishData) ([]int64, erro
 			RecipientEmail: []string{curr.StudentEmail},
 			BodyTemplate: template,
 			AttachmentPath: resultPath,
+			PDFPath: pdfPath,
 		}
 		taskQ <- mail
 	}
@@ -174,7 +179,7 @@ func sendEmailTask(workerID int, taskQ <-chan *EmailTask, failedQ chan *EmailTas
 	defer wg.Done()
 
 	for task := range taskQ {
-		err := utils.SendEmailHTMLWithAttachmentFilePath(task.BodyTemplate, task.RecipientEmail, task.AttachmentPath, "testresult.html")
+		err := sendResultEmail(task)
 		if err != nil {
 			failedQ <- task
 		} 
@@ -186,6 +191,24 @@ func sendEmailTask(workerID int, taskQ <-chan *EmailTask, failedQ chan *EmailTas
 	return nil
 }
 
+// sendResultEmail sends the result page and its pdf as attachments
+func sendResultEmail(task *EmailTask) error {
+
+	page, err := os.ReadFile(task.AttachmentPath)
+	if err != nil {
+		return err
+	}
+	pdf, err := os.ReadFile(task.PDFPath)
+	if err != nil {
+		return err
+	}
+
+	return utils.SendEmailHTMLWithAttachments(task.BodyTemplate, task.RecipientEmail, []utils.EmailAttachment{
+		{Name: "testresult.html", Content: page},
+		{Name: "testresult.pdf", Content: pdf},
+	})
+}
+
 func sendEmailFailed(workerID int, failedQ <-chan *EmailTask, wg *sync.WaitGroup) error {
 	defer wg.Done()
 
@@ -197,15 +220,15 @@ func sendEmailFailed(workerID int, failedQ <-chan *EmailTask, wg *sync.WaitGroup
 	return nil
 }
 
-func generateIndividualCharts(data *PublishData, curr *sqlc.StudentTestResultRow) (string, error) { 
+// generateIndividualCharts generates the result page of a student and its static pdf version, returns the paths to both
+func generateIndividualCharts(data *PublishData, curr *sqlc.StudentTestResultRow) (string, string, error) { 
 
 	// curr.Score
 	// curr.TotalTimeTaken
 
 	accuracy := ( float32(curr.CorrectResponse) / float32(curr.QuestionsAttempted)) * 100
 	
-	// get the complete page with charts on it
-	page, err := gocharts.IndividualResult(&dto.IndividualChartsData{
+	chartsData := &dto.IndividualChartsData{
 		FunnelDimensions: []string{"Total", "Attempted", "Correct"},
 		FunnelValues: []int64{data.qCount, curr.QuestionsAttempted, curr.CorrectResponse},
 
@@ -215,9 +238,30 @@ func generateIndividualCharts(data *PublishData, curr *sqlc.StudentTestResultRow
 			{Name: "Correct", Max: float32(data.qCount), Color: "green"},
 		},
 		RadarValues: []float32{accuracy, float32(curr.QuestionsAttempted), float32(curr.CorrectResponse)},
-	})
+
+		ResponseNames: []string{"Correct", "Partial", "Wrong", "Unattempted"},
+		ResponseValues: []int64{curr.CorrectResponse, curr.PartialResponse, curr.WrongResponse, max(data.qCount - curr.QuestionsAttempted, 0)},
+
+		// the floor adjustment is what the test's min score added back to the sum of points
+		ScoreNames: []string{"Earned", "Deducted", "Floor Adjustment", "Score"},
+		ScoreValues: []int64{curr.PointsEarned, -curr.PointsDeducted, curr.Score.Int64 - (curr.PointsEarned - curr.PointsDeducted), curr.Score.Int64},
+
+		Overrides: attemptOverrides(curr),
+
+		Title: fmt.Sprintf("%s : Result", data.testData.TestName),
+		Details: [][]string{
+			{"Student", fmt.Sprintf("%s (%s)", curr.StudentName, curr.RollNumber)},
+			{"Job", fmt.Sprintf("%s, %s", data.testData.Title, data.testData.CompanyName)},
+			{"Started at", curr.StartTime},
+			{"Submitted at", curr.EndTime},
+			{"Time taken", fmt.Sprintf("%d seconds", curr.TotalTimeTaken)},
+		},
+	}
+
+	// get the complete page with charts on it
+	page, err := gocharts.IndividualResult(chartsData)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	
@@ -232,16 +276,43 @@ func generateIndividualCharts(data *PublishData, curr *sqlc.StudentTestResultRow
 	// create the file
 	file, err := os.Create(resultPath)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer file.Close()
 
-	// render/write the complete page to the empty file
-	err = page.Render(io.MultiWriter(file))
+	// render/write the complete page to the empty file, with its assets so it opens offline
+	err = gocharts.RenderOffline(page, file)
+	if err != nil {
+		return "", "", err
+	}
+
+	// the same result as a pdf, next to the page
+	pdfPath := strings.TrimSuffix(resultPath, ".html") + ".pdf"
+	err = os.WriteFile(pdfPath, gocharts.IndividualResultPDF(chartsData), 0644)
 	if err != nil {
-		return "", err
+		return "", "", err
+	}
+
+	// return result paths and no error
+	return resultPath, pdfPath, nil 
+}
+
+// attemptOverrides describes the overrides of the test window that were in effect for the attempt
+func attemptOverrides(curr *sqlc.StudentTestResultRow) string {
+
+	overrides := []string{}
+	if curr.ExtraMinutes > 0 {
+		overrides = append(overrides, fmt.Sprintf("%d extra minutes", curr.ExtraMinutes))
+	}
+	if curr.OverrideEndTime != "" {
+		overrides = append(overrides, "make-up window until " + curr.OverrideEndTime)
+	}
+	if curr.FreshAttempt {
+		overrides = append(overrides, "fresh attempt")
+	}
+	if len(overrides) == 0 {
+		return ""
 	}
 
-	// return result path and no error
-	return resultPath, nil 
+	return "Overrides : " + strings.Join(overrides, ", ")
 }
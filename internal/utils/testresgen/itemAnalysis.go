@@ -0,0 +1,173 @@
+package testresgen
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"go.mod/internal/apicalls"
+	"go.mod/internal/config"
+	"go.mod/internal/dto"
+	sqlc "go.mod/internal/sqlc/generate"
+	"go.mod/internal/utils/testforms"
+	"google.golang.org/api/forms/v1"
+)
+
+// ItemAnalysis returns the analysis of every question of a test from its evaluated responses.
+// It is only meaningful once the test has been evaluated, the points of the responses are set by the evaluation.
+func ItemAnalysis(ctx context.Context, queries *sqlc.Queries, tests apicalls.TestProvider, testID int64) ([]dto.ItemAnalysis, error) {
+
+	testData, err := queries.TestData(ctx, testID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := testforms.AnswerKey(ctx, queries, tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	gForm, err := testforms.Questions(ctx, queries, tests, testID, testData.UploadMethod, testData.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test questions : %v", err)
+	}
+
+	return itemAnalysis(ctx, queries, testData, gForm, answers)
+}
+
+// itemAnalysis builds the analysis of the questions in the order of the form,
+// the questions that nobody answered are included with no responses
+func itemAnalysis(ctx context.Context, queries *sqlc.Queries, testData sqlc.TestDataRow, gForm *forms.Form, answers []dto.TestAnswer) ([]dto.ItemAnalysis, error) {
+
+	candidates, err := queries.ItemAnalysisCandidates(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := queries.ItemAnalysis(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+	frequencies, err := queries.ItemOptionFrequency(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+
+	byQuestion := make(map[string]sqlc.ItemAnalysisRow, len(stats))
+	for _, s := range stats {
+		byQuestion[s.QuestionID] = s
+	}
+	picks := make(map[string]map[string]int64)
+	for _, f := range frequencies {
+		if picks[f.QuestionID] == nil {
+			picks[f.QuestionID] = make(map[string]int64)
+		}
+		picks[f.QuestionID][f.Option] = f.Picks
+	}
+	correctAnswers := make(map[string][]string, len(answers))
+	for _, a := range answers {
+		correctAnswers[a.QuestionID] = a.CorrectAnswer
+	}
+	served, err := servedCounts(ctx, queries, testData, gForm)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []dto.ItemAnalysis{}
+	for _, item := range gForm.Items {
+		if item.QuestionItem == nil {
+			continue
+		}
+		s := byQuestion[item.ItemId]
+		// a question drawn from the question bank is only served to some of the candidates
+		servedTo := candidates.Candidates
+		if served != nil {
+			servedTo = served[item.ItemId]
+		}
+		analysis := dto.ItemAnalysis{
+			QuestionID: item.ItemId,
+			Title: item.Title,
+			Candidates: servedTo,
+			Attempted: s.Attempted,
+			Correct: s.Correct,
+			MedianTimeTaken: s.MedianTimeTaken,
+		}
+		if servedTo > 0 {
+			analysis.Difficulty = math.Round(float64(s.Correct) * 10000 / float64(servedTo)) / 100
+		}
+		if candidates.GroupSize > 0 {
+			analysis.Discrimination = math.Round(float64(s.TopCorrect - s.BottomCorrect) * 100 / float64(candidates.GroupSize)) / 100
+		}
+		analysis.Options = optionFrequencies(item, picks[item.ItemId], correctAnswers[item.ItemId])
+		items = append(items, analysis)
+	}
+
+	return items, nil
+}
+
+// servedCounts returns the number of candidates every question was served to, nil if every question is served to all of them.
+// The questions drawn from the question bank are drawn again for every candidate from the test's seed, the same way they were served.
+func servedCounts(ctx context.Context, queries *sqlc.Queries, testData sqlc.TestDataRow, gForm *forms.Form) (map[string]int64, error) {
+
+	if testData.UploadMethod != config.TestUploadManual && testData.UploadMethod != config.TestUploadCSVJSON {
+		return nil, nil
+	}
+	rules, err := queries.ListDrawRules(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	counts := make(map[int64]int32, len(rules))
+	for _, r := range rules {
+		counts[r.RuleID] = r.Count
+	}
+	testQuestions, err := queries.GetTestQuestions(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+	itemRules := map[string]int64{}
+	for _, q := range testQuestions {
+		if q.RuleID.Valid {
+			itemRules[q.ItemID] = q.RuleID.Int64
+		}
+	}
+	order := make([]string, 0, len(gForm.Items))
+	for _, item := range gForm.Items {
+		order = append(order, item.ItemId)
+	}
+
+	attempts, err := queries.ItemAnalysisAttempts(ctx, testData.TestID)
+	if err != nil {
+		return nil, err
+	}
+	served := make(map[string]int64, len(order))
+	for _, a := range attempts {
+		seed := testforms.Seed(a.ShuffleSeed, strconv.FormatInt(a.UserID, 10), "draw")
+		for _, itemID := range testforms.DrawOrder(order, itemRules, counts, seed) {
+			served[itemID]++
+		}
+	}
+
+	return served, nil
+}
+
+// optionFrequencies returns how many times each option of a choice question was picked, nil for the other questions
+func optionFrequencies(item *forms.Item, picks map[string]int64, correct []string) []dto.OptionFrequency {
+
+	if item.QuestionItem.Question == nil || item.QuestionItem.Question.ChoiceQuestion == nil {
+		return nil
+	}
+
+	options := []dto.OptionFrequency{}
+	for _, o := range item.QuestionItem.Question.ChoiceQuestion.Options {
+		options = append(options, dto.OptionFrequency{
+			Option: o.Value,
+			Picks: picks[o.Value],
+			Correct: slices.Contains(correct, o.Value),
+		})
+	}
+
+	return options
+}